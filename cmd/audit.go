@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/cwc/pkg/audit"
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/git"
+	cwcui "github.com/intility/cwc/pkg/ui"
+)
+
+const (
+	auditLogFile    = "audit.jsonl"
+	auditDateFormat = "2006-01-02"
+	auditTimeFormat = "2006-01-02 15:04:05"
+)
+
+// auditedClientProvider records the requests of the clients in the audit log when it is enabled.
+type auditedClientProvider struct {
+	config.ClientProvider
+	cfgProvider config.Provider
+	describe    audit.Describer
+}
+
+func newClientProvider(cfgProvider config.Provider, describe audit.Describer) *auditedClientProvider {
+	return &auditedClientProvider{
+		ClientProvider: config.NewOpenAIClientProvider(cfgProvider),
+		cfgProvider:    cfgProvider,
+		describe:       describe,
+	}
+}
+
+func (p *auditedClientProvider) NewClientFromConfig() (chat.Backend, error) { //nolint:ireturn
+	backend, err := p.ClientProvider.NewClientFromConfig()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	cfg, err := p.cfgProvider.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	if !cfg.AuditLog {
+		return backend, nil
+	}
+
+	log, err := getAuditLog()
+	if err != nil {
+		return nil, err
+	}
+
+	repository := auditRepository()
+
+	return audit.NewBackend(backend, log, func(entry *audit.Entry) {
+		entry.Endpoint = cfg.Endpoint
+		entry.Deployment = cfg.ModelDeployment
+		entry.Repository = repository
+
+		if p.describe != nil {
+			p.describe(entry)
+		}
+	}), nil
+}
+
+// auditRepository returns the root of the git repository, or the working directory outside one.
+func auditRepository() string {
+	root, err := git.TopLevel()
+	if err == nil {
+		return root
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	return wd
+}
+
+func getAuditLog() (*audit.Log, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return nil, fmt.Errorf("error getting state directory: %w", err)
+	}
+
+	return audit.NewLog(filepath.Join(stateDir, auditLogFile)), nil
+}
+
+func createAuditCmd() *cobra.Command {
+	var (
+		since      string
+		until      string
+		filter     audit.Filter
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "List the requests recorded in the audit log",
+		Long: `When the audit log is enabled with "cwc config set auditLog=true", every request sent to the
+model is recorded in the XDG state directory, typically ~/.local/state/cwc/audit.jsonl.
+An entry holds the time, endpoint, model, template, repository, the paths, sizes and hashes of the
+files in the context, the size of the piped input and the hash of the prompt, never their contents.`,
+		Example: `  cwc audit --since 2024-05-01
+  cwc audit --file "pkg/*.go" --repo cwc
+  cwc audit --json | jq .files`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+
+			filter.Since, err = parseAuditTime("since", since)
+			if err != nil {
+				return err
+			}
+
+			filter.Until, err = parseAuditTime("until", until)
+			if err != nil {
+				return err
+			}
+
+			log, err := getAuditLog()
+			if err != nil {
+				return err
+			}
+
+			entries, err := log.Entries(filter)
+			if err != nil {
+				return fmt.Errorf("error reading audit log: %w", err)
+			}
+
+			if jsonOutput {
+				return printAuditJSON(cmd, entries)
+			}
+
+			printAuditTable(entries)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only requests from this date (2006-01-02) or time (RFC 3339) on")
+	cmd.Flags().StringVar(&until, "until", "", "only requests before this date (2006-01-02) or time (RFC 3339)")
+	cmd.Flags().StringVarP(&filter.File, "file", "f", "", "only requests with this file, or a file matching this glob")
+	cmd.Flags().StringVarP(&filter.Repository, "repo", "r", "", "only requests from this repository, by path or name")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "print the entries as json, one per line")
+
+	return cmd
+}
+
+// parseAuditTime parses a date in local time or an RFC 3339 time, the empty string is the zero time.
+func parseAuditTime(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.ParseInLocation(auditDateFormat, value, time.Local)
+	if err == nil {
+		return parsed, nil
+	}
+
+	parsed, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.ArgParseError{
+			Message: fmt.Sprintf("invalid --%s %q, expected a date like 2006-01-02 or an RFC 3339 time", flag, value),
+		}
+	}
+
+	return parsed, nil
+}
+
+func printAuditJSON(cmd *cobra.Command, entries []audit.Entry) error {
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+
+	for _, entry := range entries {
+		err := encoder.Encode(entry)
+		if err != nil {
+			return fmt.Errorf("error encoding audit log entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func printAuditTable(entries []audit.Entry) {
+	if len(entries) == 0 {
+		cwcui.NewUI().PrintMessage("No recorded requests\n", cwcui.MessageTypeInfo)
+		return
+	}
+
+	table := [][]string{{"Time", "Repository", "Template", "Model", "Files", "Stdin", "Tokens"}}
+	for _, entry := range entries {
+		table = append(table, []string{
+			entry.Time.Local().Format(auditTimeFormat),
+			filepath.Base(entry.Repository),
+			entry.Template,
+			entry.Model,
+			strconv.Itoa(len(entry.Files)),
+			strconv.Itoa(entry.StdinBytes),
+			strconv.Itoa(entry.PromptTokens),
+		})
+	}
+
+	printTable(table)
+}
@@ -3,13 +3,19 @@ package cmd
 import (
 	stdErrors "errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/intility/cwc/pkg/chat"
 	"github.com/intility/cwc/pkg/config"
 	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/templates"
 	cwcui "github.com/intility/cwc/pkg/ui"
 )
 
@@ -35,11 +41,15 @@ func createConfigCommand() *cobra.Command {
 }
 
 func createGetConfigCommand() *cobra.Command {
+	var templateName string
+
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Print current config",
-		Long:  "Print current config",
-		Args:  cobra.NoArgs,
+		Long: "Print current config, the model parameters and the language overrides in effect.\n" +
+			"Parameters are resolved in order of precedence: flags, template, config and built-in defaults.\n" +
+			"Language overrides of the project config (.cwc/config.yaml) are added on top of the config.",
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := config.NewDefaultProvider()
 			cfg, err := provider.GetConfig()
@@ -49,10 +59,28 @@ func createGetConfigCommand() *cobra.Command {
 
 			printConfig(cfg)
 
+			// like the chat, a missing template leaves the parameters of the config in effect
+			tmpl, err := getTemplateLocator(provider).GetTemplate(templateName)
+			if err != nil && !errors.IsTemplateNotFoundError(err) {
+				return fmt.Errorf("failed to load template: %w", err)
+			}
+
+			printParameters(cfg, templateName, tmpl)
+
+			project, err := config.LoadProjectConfig(config.DefaultProjectConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load project config: %w", err)
+			}
+
+			printLanguages(cfg.Languages, project.Languages)
+
 			return nil
 		},
 	}
 
+	cmd.Flags().StringVarP(&templateName, "template", "t", "default",
+		"show the parameters in effect when using the given template")
+
 	return cmd
 }
 
@@ -131,6 +159,14 @@ func setConfigValue(cfg *config.Config, key, value string) error {
 	ui := cwcui.NewUI() //nolint:varnamelen
 
 	switch key {
+	case "backend":
+		if !slices.Contains(config.Backends(), value) {
+			return errors.ArgParseError{
+				Message: "invalid backend: " + value + ", expected one of: " + strings.Join(config.Backends(), ", "),
+			}
+		}
+
+		cfg.Backend = value
 	case "endpoint":
 		cfg.Endpoint = value
 	case "deploymentName":
@@ -151,20 +187,84 @@ func setConfigValue(cfg *config.Config, key, value string) error {
 		}
 
 		cfg.ExcludeGitDir = b
+	case "tokenBudget":
+		budget := 0
+
+		if value != "" {
+			i, err := strconv.Atoi(value)
+			if err != nil || i < 0 {
+				return errors.ArgParseError{Message: "invalid non-negative integer for tokenBudget: " + value}
+			}
+
+			budget = i
+		}
+
+		cfg.TokenBudget = budget
+	case "compaction":
+		if value != "" && !slices.Contains(chat.CompactionStrategies(), value) {
+			return errors.ArgParseError{
+				Message: "invalid compaction strategy: " + value +
+					", expected one of: " + strings.Join(chat.CompactionStrategies(), ", "),
+			}
+		}
+
+		cfg.Compaction = value
+	case "compactionWindow":
+		window := 0
+
+		if value != "" {
+			i, err := strconv.Atoi(value)
+			if err != nil || i < 0 {
+				return errors.ArgParseError{Message: "invalid non-negative integer for compactionWindow: " + value}
+			}
+
+			window = i
+		}
+
+		cfg.CompactionWindow = window
+	case "secrets":
+		if value != "" && !slices.Contains(redact.Modes(), value) {
+			return errors.ArgParseError{
+				Message: "invalid secrets mode: " + value + ", expected one of: " + strings.Join(redact.Modes(), ", "),
+			}
+		}
+
+		cfg.Secrets = value
+	case "auditLog":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.ArgParseError{Message: "invalid boolean value for auditLog: " + value}
+		}
+
+		cfg.AuditLog = b
+	case "model", "temperature", "topP", "maxTokens", "stop", "seed":
+		return setParameterValue(&cfg.Parameters, key, value)
 	default:
 		ui.PrintMessage(fmt.Sprintf("Unknown config key: %s\n", key), cwcui.MessageTypeError)
 
 		validKeys := []string{
+			"backend",
 			"endpoint",
 			"deploymentName",
 			"apiKey",
 			"useGitignore",
 			"excludeGitDir",
+			"tokenBudget",
+			"compaction",
+			"compactionWindow",
+			"secrets",
+			"auditLog",
+			"model",
+			"temperature",
+			"topP",
+			"maxTokens",
+			"stop",
+			"seed",
 		}
 
 		ui.PrintMessage("Valid keys are: "+strings.Join(validKeys, ", "), cwcui.MessageTypeInfo)
 
-		return errors.SuppressedError{}
+		return errors.SuppressedError{Err: errors.ArgParseError{Message: "unknown config key: " + key}}
 	}
 
 	return nil
@@ -173,17 +273,212 @@ func setConfigValue(cfg *config.Config, key, value string) error {
 func printConfig(cfg *config.Config) {
 	table := [][]string{
 		{"Name", "Value"},
+		{"backend", cfg.BackendOrDefault()},
 		{"endpoint", cfg.Endpoint},
 		{"deploymentName", cfg.ModelDeployment},
 		{"apiKey", cfg.APIKey()},
 		{"SEP", ""},
 		{"useGitignore", fmt.Sprintf("%t", cfg.UseGitignore)},
 		{"excludeGitDir", fmt.Sprintf("%t", cfg.ExcludeGitDir)},
+		{"tokenBudget", formatNonZero(cfg.TokenBudget)},
+		{"compaction", cfg.Compaction},
+		{"compactionWindow", formatNonZero(cfg.CompactionWindow)},
+		{"secrets", cfg.Secrets},
+		{"auditLog", fmt.Sprintf("%t", cfg.AuditLog)},
+	}
+
+	printTable(table)
+}
+
+// setParameterValue sets a model parameter, an empty value unsets it.
+func setParameterValue(params *chat.Parameters, key, value string) error { //nolint:cyclop
+	switch key {
+	case "model":
+		params.Model = value
+	case "temperature", "topP":
+		var parsed *float32
+
+		if value != "" {
+			f, err := strconv.ParseFloat(value, 32)
+			if err != nil {
+				return errors.ArgParseError{Message: "invalid number for " + key + ": " + value}
+			}
+
+			f32 := float32(f)
+			parsed = &f32
+		}
+
+		if key == "temperature" {
+			params.Temperature = parsed
+		} else {
+			params.TopP = parsed
+		}
+	case "maxTokens":
+		params.MaxTokens = 0
+
+		if value != "" {
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.ArgParseError{Message: "invalid integer for maxTokens: " + value}
+			}
+
+			params.MaxTokens = i
+		}
+	case "stop":
+		params.Stop = nil
+		if value != "" {
+			params.Stop = strings.Split(value, ",")
+		}
+	case "seed":
+		params.Seed = nil
+
+		if value != "" {
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.ArgParseError{Message: "invalid integer for seed: " + value}
+			}
+
+			params.Seed = &i
+		}
+	}
+
+	return nil
+}
+
+// printParameters prints the model parameters in effect with the named template, which is nil when
+// there is no such template, and where each value comes from.
+func printParameters(cfg *config.Config, templateName string, tmpl *templates.Template) {
+	defaults := chat.Parameters{Model: cfg.DefaultModel()}
+
+	layers := []struct {
+		source string
+		params chat.Parameters
+	}{
+		{source: "default", params: defaults},
+		{source: "config", params: cfg.Parameters},
+	}
+
+	if tmpl != nil {
+		layers = append(layers, struct {
+			source string
+			params chat.Parameters
+		}{source: "template " + tmpl.Name, params: tmpl.Parameters})
+	}
+
+	fields := []struct {
+		name  string
+		value func(p chat.Parameters) string
+	}{
+		{"model", func(p chat.Parameters) string { return p.Model }},
+		{"temperature", func(p chat.Parameters) string { return formatFloatPtr(p.Temperature) }},
+		{"topP", func(p chat.Parameters) string { return formatFloatPtr(p.TopP) }},
+		{"maxTokens", func(p chat.Parameters) string { return formatNonZero(p.MaxTokens) }},
+		{"stop", func(p chat.Parameters) string { return strings.Join(p.Stop, ",") }},
+		{"seed", func(p chat.Parameters) string { return formatIntPtr(p.Seed) }},
+	}
+
+	table := [][]string{{"Parameter", "Value", "Source"}}
+
+	for _, field := range fields {
+		value, source := "", "server default"
+
+		for _, layer := range layers {
+			if v := field.value(layer.params); v != "" {
+				value, source = v, layer.source
+			}
+		}
+
+		table = append(table, []string{field.name, value, source})
 	}
 
+	heading := fmt.Sprintf("Parameters in effect with the template %q:\n", templateName)
+	if tmpl == nil {
+		heading = fmt.Sprintf("Parameters in effect, there is no template %q to merge:\n", templateName)
+	}
+
+	cwcui.NewUI().PrintMessage(heading, cwcui.MessageTypeInfo)
 	printTable(table)
 }
 
+// printLanguages prints the language overrides of the config with those of the project on top,
+// as they are merged when gathering files.
+func printLanguages(user, project filetree.LanguageOverrides) {
+	table := [][]string{{"Override", "Value", "Source"}}
+
+	mappings := func(kind string, user, project map[string]string) {
+		merged := maps.Clone(user)
+		if merged == nil {
+			merged = map[string]string{}
+		}
+
+		maps.Copy(merged, project)
+
+		keys := make([]string, 0, len(merged))
+		for key := range merged {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			source := "config"
+			if _, ok := project[key]; ok {
+				source = "project"
+			}
+
+			table = append(table, []string{kind + " " + key, merged[key], source})
+		}
+	}
+
+	globs := func(kind string, user, project []string) {
+		for _, glob := range user {
+			table = append(table, []string{kind, glob, "config"})
+		}
+
+		for _, glob := range project {
+			table = append(table, []string{kind, glob, "project"})
+		}
+	}
+
+	mappings("extension", user.Extensions, project.Extensions)
+	mappings("filename", user.Filenames, project.Filenames)
+	globs("text", user.Text, project.Text)
+	globs("exclude", user.Exclude, project.Exclude)
+
+	ui := cwcui.NewUI() //nolint:varnamelen
+	if len(table) == 1 {
+		ui.PrintMessage("Language overrides: none\n", cwcui.MessageTypeInfo)
+		return
+	}
+
+	ui.PrintMessage("Language overrides in effect:\n", cwcui.MessageTypeInfo)
+	printTable(table)
+}
+
+func formatFloatPtr(f *float32) string {
+	if f == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(float64(*f), 'f', -1, 32)
+}
+
+func formatIntPtr(i *int) string {
+	if i == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*i)
+}
+
+func formatNonZero(i int) string {
+	if i == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(i)
+}
+
 func printTable(table [][]string) {
 	ui := cwcui.NewUI() //nolint:varnamelen
 	columnLengths := calculateColumnLengths(table)
@@ -1,16 +1,25 @@
 package cmd
 
 import (
+	stdErrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/intility/cwc/internal"
+	"github.com/intility/cwc/pkg/audit"
+	"github.com/intility/cwc/pkg/chat"
 	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
 	"github.com/intility/cwc/pkg/filetree"
 	"github.com/intility/cwc/pkg/prompting"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/sessions"
 	"github.com/intility/cwc/pkg/systemcontext"
 	"github.com/intility/cwc/pkg/templates"
 	cwcui "github.com/intility/cwc/pkg/ui"
@@ -24,12 +33,17 @@ It allows for specification of paths to include or exclude files from the chat c
 
 Features at a glance:
 
-- Regex-based file inclusion and exclusion patterns
+- Regex and glob file inclusion and exclusion patterns
 - .gitignore integration for ignoring files
+- .cwcignore and .cwcinclude files for excluding and including files per repository
+- Selection of staged files or files changed since a git revision, with their diff and commits
+- Reading the files of one or two git revisions without checking them out
 - Option to specify directories for inclusion scope
 - Interactive file selection and confirmation
 - Reading from standard input for a non-interactive session
 - Use of templates for system messages and default prompts
+- Sessions are saved and can be resumed with 'cwc sessions resume'
+- An opt-in audit log of every request sent to the model, queried with 'cwc audit'
 
 The command can also receive context from standard input, useful for piping the output from another command as input.
 
@@ -38,26 +52,76 @@ Examples:
 Including all '.go' files while excluding the 'vendor/' directory:
 > cwc --include='.*.go$' --exclude='vendor/'
 
+The same with globs, which are prefixed with 'glob:', and a regular expression may be prefixed with 're:':
+> cwc --include='glob:**/*.go' --exclude='glob:vendor/**'
+
+Including the Go and Markdown files, matching either pattern:
+> cwc -i 'glob:**/*.go' -i 'glob:**/*.md'
+
 Including 'main.go' files from a specific path:
 > cwc --include='main.go' --paths='./cmd'
 
 Using the output of another command:
 > git diff | cwc "Short commit message for these changes"
 
+Combining the output of another command with matching files:
+> git diff | cwc -i '\.go$' "Review this change"
+
+Asking about the staged changes, with the staged files and diff in the context:
+> cwc --staged "Write a commit message for these changes"
+
+Reviewing the changes of a branch, with the changed files, the diff and the commits since main:
+> cwc --diff main "Review the changes in this branch"
+
+Comparing how the config is loaded in v1.4 and on main, without checking either out:
+> cwc --ref v1.4 --ref main -i 'config' "What changed in how the config is loaded?"
+
+Adding a log file to the context:
+> cwc --context-file build.log "Why does the build fail?"
+
 Using a specific template:
 > cwc --template=tech_writer --template-variables rizz=max
+
+Printing the answer with its model, token usage and finish reason as JSON:
+> cwc --output json "Summarize the README" < README.md
+
+Reviewing and applying the changes proposed in the answer:
+> go vet ./... 2>&1 | cwc --apply "Fix these warnings"
+
+Overriding the model and sampling parameters from the config and template:
+> cwc --model gpt-4o --temperature 0.2 --max-tokens 1000
+
+Printing the request a template and patterns produce, with its token estimate, without sending it:
+> cwc --dry-run --template tech_writer -i 'glob:docs/**' "Improve the introduction"
 `
 )
 
 func CreateRootCommand() *cobra.Command {
 	chatOpts := internal.InteractiveChatOptions{
-		IncludePattern:    "",
-		ExcludePattern:    "",
+		IncludePatterns:   nil,
+		ExcludePatterns:   nil,
 		Paths:             []string{},
 		TemplateName:      "",
 		TemplateVariables: nil,
+		Parameters:        chat.Parameters{},
+		TokenBudget:       0,
+		Compaction:        "",
+		CompactionWindow:  0,
+		Apply:             false,
+		Output:            internal.OutputText,
+		ContextFiles:      nil,
+		GatherFiles:       false,
+		Git:               systemcontext.GitSelection{Staged: false, Ref: "", Diff: false},
+		Refs:              nil,
+		DryRun:            false,
+		IgnoreBudget:      false,
 	}
 
+	var (
+		paramFlags parameterFlags
+		gitFlags   gitFlags
+	)
+
 	loginCmd := createLoginCmd()
 	logoutCmd := createLogoutCmd()
 
@@ -72,18 +136,68 @@ func CreateRootCommand() *cobra.Command {
 				return fmt.Errorf("error getting config provider: %w", err)
 			}
 
-			if isPiped(os.Stdin) {
-				nic := createNonInteractiveCommand(cfgProvider, args, chatOpts.TemplateName, chatOpts.TemplateVariables)
+			cfg, err := cfgProvider.GetConfig()
+			if err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+
+			chatOpts.TokenBudget = cfg.TokenBudget
+			chatOpts.Compaction = cfg.Compaction
+			chatOpts.CompactionWindow = cfg.CompactionWindow
+			resolver := parameterResolver(cfg, getTemplateLocator(cfgProvider), paramFlags.overrides(cobraCmd))
+
+			chatOpts.Parameters, err = resolver(chatOpts.TemplateName)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains(internal.OutputFormats(), chatOpts.Output) {
+				return errors.ArgParseError{Message: fmt.Sprintf("invalid output format %q, expected one of: %s",
+					chatOpts.Output, strings.Join(internal.OutputFormats(), ", "))}
+			}
+
+			err = gitFlags.validate()
+			if err != nil {
+				return err
+			}
+
+			chatOpts.Git = gitFlags.selection()
+			chatOpts.Refs = gitFlags.refs
+			chatOpts.GatherFiles = cobraCmd.Flags().Changed("include") || cobraCmd.Flags().Changed("exclude") ||
+				cobraCmd.Flags().Changed("paths") || chatOpts.Git.Any() || len(chatOpts.Refs) > 0
+
+			// scripts asking for structured output get it without piping anything
+			nonInteractive := isPiped(os.Stdin) || chatOpts.Output != internal.OutputText
+
+			if chatOpts.DryRun {
+				err = createDryRunCommand(cfgProvider, args, chatOpts, nonInteractive).Run()
+				if err != nil {
+					return fmt.Errorf("error running dry run: %w", err)
+				}
+
+				return nil
+			}
+
+			if nonInteractive {
+				nic := createNonInteractiveCommand(cfgProvider, args, chatOpts)
 
 				err = nic.Run()
 				if err != nil {
+					var suppressedError errors.SuppressedError
+					if ok := stdErrors.As(err, &suppressedError); ok {
+						cobraCmd.SilenceUsage = true
+						cobraCmd.SilenceErrors = true
+
+						return err
+					}
+
 					return fmt.Errorf("error running non-interactive command: %w", err)
 				}
 
 				return nil
 			}
 
-			interactiveCmd := createInteractiveCommand(args, chatOpts, cfgProvider)
+			interactiveCmd := createInteractiveCommand(args, chatOpts, cfgProvider, resolver)
 
 			err = interactiveCmd.Run()
 			if err != nil {
@@ -95,11 +209,15 @@ func CreateRootCommand() *cobra.Command {
 	}
 
 	initFlags(rootCmd, &chatOpts)
+	initParameterFlags(rootCmd, &paramFlags)
+	initGitFlags(rootCmd, &gitFlags)
 
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(createTemplatesCmd())
 	rootCmd.AddCommand(createConfigCommand())
+	rootCmd.AddCommand(createSessionsCmd())
+	rootCmd.AddCommand(createAuditCmd())
 
 	return rootCmd
 }
@@ -107,25 +225,49 @@ func CreateRootCommand() *cobra.Command {
 func createNonInteractiveCommand(
 	cfgProvider config.Provider,
 	args []string,
-	templateName string,
-	templateVars map[string]string,
+	opts internal.InteractiveChatOptions,
 ) *internal.NonInteractiveCmd {
-	clientProvider := config.NewOpenAIClientProvider(cfgProvider)
 	templateLocator := getTemplateLocator(cfgProvider)
-	promptResolver := prompting.NewArgsOrTemplatePromptResolver(templateLocator, args, templateName)
+	promptResolver := prompting.NewArgsOrTemplatePromptResolver(templateLocator, args, opts.TemplateName)
 
-	contextRetriever := systemcontext.NewIOReaderContextRetriever(os.Stdin)
+	stdin := systemcontext.NewIOReaderContextRetriever(stdinReader())
+	sections, fileRetrievers, fileSelector := contextSections(cfgProvider, opts, nil, stdin)
+	contextRetriever := systemcontext.NewCompositeContextRetriever(sections...)
+	clientProvider := newClientProvider(cfgProvider, func(entry *audit.Entry) {
+		entry.Template = opts.TemplateName
+		entry.Files = audit.NewFiles(contextRetriever.Files(), contextRetriever.SentFiles())
+		entry.StdinBytes = stdin.Size()
+	})
+	contextRecorder := systemcontext.NewRecordingContextRetriever(contextRetriever)
 	smGenerator := systemcontext.NewTemplatedSystemMessageGenerator(
 		templateLocator,
-		templateName,
-		templateVars,
-		contextRetriever,
+		opts.TemplateName,
+		opts.TemplateVariables,
+		contextRecorder,
 	)
 
+	nonInteractiveOpts := []internal.NonInteractiveOpt{
+		internal.WithIncludedFiles(contextRetriever),
+		internal.WithRecordedContext(contextRecorder),
+	}
+
+	if opts.Apply {
+		// the matched files are the files --apply changes, the changes go to the working tree
+		if len(opts.Refs) > 0 {
+			workingTreeOpts := opts
+			workingTreeOpts.Refs = nil
+			_, fileRetrievers = fileContextSections(cfgProvider, workingTreeOpts, nil, fileSelector, redact.NewRedactor())
+		}
+
+		nonInteractiveOpts = append(nonInteractiveOpts, internal.WithApplyFiles(fileRetrievers[0]))
+	}
+
 	return internal.NewNonInteractiveCmd(
 		clientProvider,
 		promptResolver,
 		smGenerator,
+		opts,
+		nonInteractiveOpts...,
 	)
 }
 
@@ -133,34 +275,250 @@ func createInteractiveCommand(
 	args []string,
 	opts internal.InteractiveChatOptions,
 	cfgProvider config.Provider,
+	resolver internal.ParameterResolver,
 ) *internal.InteractiveCmd {
-	clientProvider := config.NewOpenAIClientProvider(cfgProvider)
 	templateLocator := getTemplateLocator(cfgProvider)
 	promptResolver := prompting.NewArgsOrTemplatePromptResolver(templateLocator, args, opts.TemplateName)
 
-	retrieverConfig := systemcontext.FileContextRetrieverOptions{
-		CfgProvider:    cfgProvider,
-		IncludePattern: opts.IncludePattern,
-		ExcludePattern: opts.ExcludePattern,
-		SearchScopes:   opts.Paths,
-		ContextPrinter: printContext,
-	}
-
-	contextRetriever := systemcontext.NewFileContextRetriever(retrieverConfig)
+	sections, fileRetrievers, _ := contextSections(cfgProvider, opts, printContext, nil)
+	contextRetriever := systemcontext.NewCompositeContextRetriever(sections...)
+	contextRecorder := systemcontext.NewRecordingContextRetriever(contextRetriever)
 
 	smGenerator := systemcontext.NewTemplatedSystemMessageGenerator(
 		templateLocator,
 		opts.TemplateName,
 		opts.TemplateVariables,
-		contextRetriever,
+		contextRecorder,
 	)
 
+	clientProvider := newClientProvider(cfgProvider, func(entry *audit.Entry) {
+		// the template may be switched during the chat
+		entry.Template = smGenerator.TemplateName()
+		entry.Files = audit.NewFiles(contextRetriever.Files(), contextRetriever.SentFiles())
+	})
+
+	interactiveOpts := []internal.InteractiveOpt{
+		internal.WithContextFiles(contextRetriever),
+		internal.WithContextRecorder(contextRecorder),
+		internal.WithContextEditor(fileContextEditor(fileRetrievers)),
+		internal.WithTemplateSwitcher(smGenerator, resolver),
+	}
+
+	sessionStore, err := getSessionStore()
+	if err == nil {
+		interactiveOpts = append(interactiveOpts, internal.WithSessionStore(sessionStore))
+	}
+
 	return internal.NewInteractiveCmd(
 		promptResolver,
 		clientProvider,
 		smGenerator,
 		opts,
+		interactiveOpts...,
+	)
+}
+
+// createDryRunCommand gathers the context like the command would in the same mode, interactive or
+// not, and prints the request instead of sending it.
+func createDryRunCommand(
+	cfgProvider config.Provider,
+	args []string,
+	opts internal.InteractiveChatOptions,
+	nonInteractive bool,
+) *internal.DryRunCmd {
+	templateLocator := getTemplateLocator(cfgProvider)
+	promptResolver := prompting.NewArgsOrTemplatePromptResolver(templateLocator, args, opts.TemplateName)
+
+	var stdin *systemcontext.IOReaderContextRetriever
+	if nonInteractive {
+		stdin = systemcontext.NewIOReaderContextRetriever(stdinReader())
+	}
+
+	sections, _, _ := contextSections(cfgProvider, opts, nil, stdin)
+	contextRetriever := systemcontext.NewCompositeContextRetriever(sections...)
+	contextRecorder := systemcontext.NewRecordingContextRetriever(contextRetriever)
+
+	smGenerator := systemcontext.NewTemplatedSystemMessageGenerator(
+		templateLocator,
+		opts.TemplateName,
+		opts.TemplateVariables,
+		contextRecorder,
 	)
+
+	return internal.NewDryRunCmd(promptResolver, smGenerator, opts, contextRetriever, contextRecorder)
+}
+
+// contextSections returns the sections of the context in order: the piped input, which is only
+// read in non-interactive mode, the matched files, the git changes and the context files. The matched
+// files are only part of the piped context when they are asked for, their retrievers and the git file
+// selector are returned either way.
+func contextSections(
+	cfgProvider config.Provider,
+	opts internal.InteractiveChatOptions,
+	printer func(fileTree string, files []filetree.File),
+	stdin *systemcontext.IOReaderContextRetriever,
+) ([]systemcontext.ContextSection, []*systemcontext.FileContextRetriever, systemcontext.FileSelector) { //nolint:ireturn
+	var sections []systemcontext.ContextSection
+
+	// a secret gets the same placeholder in every section
+	redactor := redact.NewRedactor()
+
+	nonInteractive := stdin != nil
+	if nonInteractive {
+		sections = append(sections, systemcontext.ContextSection{
+			Label:     "Standard input",
+			Retriever: systemcontext.NewRedactingContextRetriever(stdin, "standard input", cfgProvider, redactor),
+		})
+	}
+
+	gitSections, fileSelector := gitContextSections(cfgProvider, opts, redactor)
+	fileSections, fileRetrievers := fileContextSections(cfgProvider, opts, printer, fileSelector, redactor)
+
+	if !nonInteractive || opts.GatherFiles {
+		sections = append(sections, fileSections...)
+	}
+
+	sections = append(sections, gitSections...)
+
+	return append(sections, contextFileSections(cfgProvider, opts, redactor)...), fileRetrievers, fileSelector
+}
+
+// fileContextSections returns the sections of the matched files and their retrievers, one for
+// each revision given with --ref, or a single one for the working tree.
+func fileContextSections(
+	cfgProvider config.Provider,
+	opts internal.InteractiveChatOptions,
+	printer func(fileTree string, files []filetree.File),
+	selector systemcontext.FileSelector,
+	redactor *redact.Redactor,
+) ([]systemcontext.ContextSection, []*systemcontext.FileContextRetriever) {
+	refs := opts.Refs
+	if len(refs) == 0 {
+		refs = []string{""}
+	}
+
+	sections := make([]systemcontext.ContextSection, 0, len(refs))
+	retrievers := make([]*systemcontext.FileContextRetriever, 0, len(refs))
+
+	for _, ref := range refs {
+		label, refPrinter := "Files", printer
+		if ref != "" {
+			label = "Files at " + ref
+			refPrinter = labelledContextPrinter(label, printer)
+		}
+
+		retriever := systemcontext.NewFileContextRetriever(systemcontext.FileContextRetrieverOptions{
+			CfgProvider:     cfgProvider,
+			IncludePatterns: opts.IncludePatterns,
+			ExcludePatterns: opts.ExcludePatterns,
+			SearchScopes:    opts.Paths,
+			ContextPrinter:  refPrinter,
+			FileSelector:    selector,
+			Ref:             ref,
+			Redactor:        redactor,
+		})
+
+		sections = append(sections, systemcontext.ContextSection{Label: label, Retriever: retriever})
+		retrievers = append(retrievers, retriever)
+	}
+
+	return sections, retrievers
+}
+
+// labelledContextPrinter prints the label above the file tree, to tell the revisions apart.
+func labelledContextPrinter(
+	label string,
+	printer func(fileTree string, files []filetree.File),
+) func(fileTree string, files []filetree.File) {
+	if printer == nil {
+		return nil
+	}
+
+	return func(fileTree string, files []filetree.File) {
+		cwcui.NewUI().PrintMessage(label+":\n", cwcui.MessageTypeInfo)
+		printer(fileTree, files)
+	}
+}
+
+// fileContextEditor adds and drops files in the context of each revision given with --ref.
+type fileContextEditor []*systemcontext.FileContextRetriever
+
+func (e fileContextEditor) AddInclude(pathOrPattern string) error {
+	for _, retriever := range e {
+		err := retriever.AddInclude(pathOrPattern)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// Drop drops the file from the revisions that include it.
+func (e fileContextEditor) Drop(path string) error {
+	var err error
+
+	dropped := false
+
+	for _, retriever := range e {
+		if err = retriever.Drop(path); err == nil {
+			dropped = true
+		}
+	}
+
+	if dropped {
+		return nil
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// gitContextSections returns the section with the changes and commits of the files selected
+// from git, and the selector of those files, when files are selected from git.
+func gitContextSections(
+	cfgProvider config.Provider,
+	opts internal.InteractiveChatOptions,
+	redactor *redact.Redactor,
+) ([]systemcontext.ContextSection, systemcontext.FileSelector) { //nolint:ireturn
+	if !opts.Git.Any() {
+		return nil, nil
+	}
+
+	retriever := systemcontext.NewGitContextRetriever(opts.Git, opts.Paths)
+
+	return []systemcontext.ContextSection{{
+		Label:     "Git changes",
+		Retriever: systemcontext.NewRedactingContextRetriever(retriever, "git changes", cfgProvider, redactor),
+	}}, retriever
+}
+
+// contextFileSections returns a section for each file given with --context-file.
+func contextFileSections(
+	cfgProvider config.Provider,
+	opts internal.InteractiveChatOptions,
+	redactor *redact.Redactor,
+) []systemcontext.ContextSection {
+	sections := make([]systemcontext.ContextSection, 0, len(opts.ContextFiles))
+
+	for _, path := range opts.ContextFiles {
+		retriever := systemcontext.NewContextFileRetriever(path)
+
+		sections = append(sections, systemcontext.ContextSection{
+			Label:     "Context file " + path,
+			Retriever: systemcontext.NewRedactingContextRetriever(retriever, path, cfgProvider, redactor),
+		})
+	}
+
+	return sections
+}
+
+// stdinReader returns stdin when something is piped to it, and an empty reader otherwise.
+func stdinReader() io.Reader {
+	if !isPiped(os.Stdin) {
+		return strings.NewReader("")
+	}
+
+	return os.Stdin
 }
 
 func getPlatformSpecificConfigProvider() (config.Provider, error) { //nolint: ireturn
@@ -201,6 +559,15 @@ func printLargeFileWarning(file filetree.File) {
 	}
 }
 
+func getSessionStore() (*sessions.FileStore, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return nil, fmt.Errorf("error getting state directory: %w", err)
+	}
+
+	return sessions.NewFileStore(filepath.Join(stateDir, "sessions")), nil
+}
+
 func getTemplateLocator(cfgProvider config.Provider) *templates.MergedTemplateLocator {
 	var locators []templates.TemplateLocator
 
@@ -216,18 +583,33 @@ func getTemplateLocator(cfgProvider config.Provider) *templates.MergedTemplateLo
 }
 
 func initFlags(cmd *cobra.Command, opts *internal.InteractiveChatOptions) {
-	cmd.Flags().StringVarP(&opts.IncludePattern, "include", "i", ".*", "a regular expression to match files to include")
-	cmd.Flags().StringVarP(&opts.ExcludePattern, "exclude", "x", "", "a regular expression to match files to exclude")
+	cmd.Flags().StringArrayVarP(&opts.IncludePatterns, "include", "i", nil,
+		"a regular expression or glob to match files to include")
+	cmd.Flags().StringArrayVarP(&opts.ExcludePatterns, "exclude", "x", nil,
+		"a regular expression or glob to match files to exclude")
 	cmd.Flags().StringSliceVarP(&opts.Paths, "paths", "p", []string{"."}, "a list of paths to search for files")
 	cmd.Flags().StringVarP(&opts.TemplateName, "template", "t", "default", "the name of the template to use")
 	cmd.Flags().StringToStringVarP(&opts.TemplateVariables,
 		"template-variables", "v", nil, "variables to use in the template")
+	cmd.Flags().StringSliceVar(&opts.ContextFiles, "context-file", nil,
+		"files of any type to add to the context, such as logs or documentation")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", internal.OutputText,
+		"the output format in non-interactive mode: text, json or ndjson")
+	cmd.Flags().BoolVar(&opts.Apply, "apply", false,
+		"review the changes proposed in the answer and apply them, in non-interactive mode")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false,
+		"print the request with its token estimate instead of sending it, as json with --output json")
+	cmd.Flags().BoolVar(&opts.IgnoreBudget, "ignore-budget", false,
+		"send a request that exceeds the token limit without asking")
 
 	cmd.Flag("include").
-		Usage = "Specify a regex pattern to include files. " +
-		"For example, to include only Markdown files, use --include '\\.md$'"
+		Usage = "Specify a regex pattern, or a glob prefixed with glob:, to include files. Repeat it to include " +
+		"files matching any of the patterns. For example, to include Markdown files, use --include '\\.md$' " +
+		"or --include 'glob:**/*.md'"
 	cmd.Flag("exclude").
-		Usage = "Specify a regex pattern to exclude files. For example, to exclude test files, use --exclude '_test\\\\.go$'"
+		Usage = "Specify a regex pattern, or a glob prefixed with glob:, to exclude files. Repeat it to exclude " +
+		"files matching any of the patterns. For example, to exclude test files, use --exclude '_test\\.go$' " +
+		"or --exclude 'glob:**/*_test.go'"
 	cmd.Flag("paths").
 		Usage = "Specify a list of paths to search for files. For example, " +
 		"to search in the 'cmd' and 'pkg' directories, use --paths cmd,pkg"
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/systemcontext"
+)
+
+// maxRefs is the number of revisions that can be compared with --ref.
+const maxRefs = 2
+
+// gitFlags holds the values of the flags selecting the files of the context from git.
+type gitFlags struct {
+	staged       bool
+	changedSince string
+	diff         string
+	refs         []string
+}
+
+func initGitFlags(cmd *cobra.Command, flags *gitFlags) {
+	cmd.Flags().BoolVar(&flags.staged, "staged", false,
+		"include the files with staged changes, with the staged diff")
+	cmd.Flags().StringVar(&flags.changedSince, "changed-since", "",
+		"include the files changed since a git revision, with the commits since it")
+	cmd.Flags().StringVar(&flags.diff, "diff", "",
+		"include the files changed since a git revision, with the diff and the commits since it")
+
+	cmd.Flags().StringArrayVar(&flags.refs, "ref", nil,
+		"read the files from a git revision instead of the working tree, give it twice to compare two revisions")
+
+	cmd.MarkFlagsMutuallyExclusive("staged", "changed-since", "diff", "ref")
+
+	cmd.Flag("changed-since").
+		Usage = "Specify a git revision to include the files changed since it and the commits " +
+		"that changed them. For example, --changed-since main"
+	cmd.Flag("diff").
+		Usage = "Specify a git revision to include the files changed since it, their diff and the commits " +
+		"that changed them. For example, --diff HEAD~3"
+	cmd.Flag("ref").
+		Usage = "Specify a git revision to read the files from instead of the working tree. Give it twice to " +
+		"include the files of both revisions side by side. For example, --ref v1.4 --ref main"
+}
+
+// validate checks that at most two revisions are given with --ref.
+func (f *gitFlags) validate() error {
+	if len(f.refs) > maxRefs {
+		return errors.ArgParseError{Message: fmt.Sprintf("at most %d revisions can be given with --ref", maxRefs)}
+	}
+
+	return nil
+}
+
+// selection returns the files to select from git, --include and --exclude filter them further.
+func (f *gitFlags) selection() systemcontext.GitSelection {
+	switch {
+	case f.staged:
+		return systemcontext.GitSelection{Staged: true, Ref: "", Diff: true}
+	case f.diff != "":
+		return systemcontext.GitSelection{Staged: false, Ref: f.diff, Diff: true}
+	default:
+		return systemcontext.GitSelection{Staged: false, Ref: f.changedSince, Diff: false}
+	}
+}
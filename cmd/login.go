@@ -11,6 +11,7 @@ import (
 )
 
 var (
+	backendFlag         string //nolint:gochecknoglobals
 	apiKeyFlag          string //nolint:gochecknoglobals
 	endpointFlag        string //nolint:gochecknoglobals
 	modelDeploymentFlag string //nolint:gochecknoglobals
@@ -20,28 +21,42 @@ func createLoginCmd() *cobra.Command {
 	ui := cwcui.NewUI() //nolint:varnamelen
 	cmd := &cobra.Command{
 		Use:   "login",
-		Short: "Authenticate with Azure OpenAI",
-		Long: "Login will prompt you to enter your Azure OpenAI API key " +
+		Short: "Authenticate with Azure OpenAI, OpenAI or an OpenAI compatible server",
+		Long: "Login will prompt you to enter the backend, API key " +
 			"and other relevant information required for authentication.\n" +
+			"Supported backends are azure (default), openai and openai-compatible, " +
+			"the latter being any server implementing the OpenAI API such as llama.cpp or vLLM.\n" +
 			"Your credentials will be stored securely in your keyring and will never be exposed on the file system directly.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if backendFlag == "" {
+				ui.PrintMessage("Enter the backend (azure, openai, openai-compatible) [azure]: ", cwcui.MessageTypeInfo)
+				backendFlag = config.SanitizeInput(ui.ReadUserInput())
+			}
+
+			if backendFlag == "" {
+				backendFlag = config.BackendAzure
+			}
+
+			prompts := loginPromptsForBackend(backendFlag)
+
 			// Prompt for other required authentication details (apiKey, endpoint, version, and deployment)
 			if apiKeyFlag == "" {
-				ui.PrintMessage("Enter the Azure OpenAI API Key: ", cwcui.MessageTypeInfo)
+				ui.PrintMessage(prompts.apiKey, cwcui.MessageTypeInfo)
 				apiKeyFlag = config.SanitizeInput(ui.ReadUserInput())
 			}
 
 			if endpointFlag == "" {
-				ui.PrintMessage("Enter the Azure OpenAI API Endpoint: ", cwcui.MessageTypeInfo)
+				ui.PrintMessage(prompts.endpoint, cwcui.MessageTypeInfo)
 				endpointFlag = config.SanitizeInput(ui.ReadUserInput())
 			}
 
 			if modelDeploymentFlag == "" {
-				ui.PrintMessage("Enter the Azure OpenAI Model Deployment: ", cwcui.MessageTypeInfo)
+				ui.PrintMessage(prompts.modelDeployment, cwcui.MessageTypeInfo)
 				modelDeploymentFlag = config.SanitizeInput(ui.ReadUserInput())
 			}
 
 			cfg := config.NewConfig(endpointFlag, modelDeploymentFlag)
+			cfg.Backend = backendFlag
 			cfg.SetAPIKey(apiKeyFlag)
 
 			provider := config.NewDefaultProvider()
@@ -64,9 +79,40 @@ func createLoginCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&apiKeyFlag, "api-key", "k", "", "Azure OpenAI API Key")
-	cmd.Flags().StringVarP(&endpointFlag, "endpoint", "e", "", "Azure OpenAI API Endpoint")
-	cmd.Flags().StringVarP(&modelDeploymentFlag, "deployment-name", "d", "", "Azure OpenAI Deployment Name")
+	cmd.Flags().StringVarP(&backendFlag, "backend", "b", "", "Backend: azure, openai or openai-compatible")
+	cmd.Flags().StringVarP(&apiKeyFlag, "api-key", "k", "", "API Key")
+	cmd.Flags().StringVarP(&endpointFlag, "endpoint", "e", "", "API Endpoint")
+	cmd.Flags().StringVarP(&modelDeploymentFlag, "deployment-name", "d", "",
+		"Azure OpenAI Deployment Name, or the model name for other backends")
 
 	return cmd
 }
+
+type loginPrompts struct {
+	apiKey          string
+	endpoint        string
+	modelDeployment string
+}
+
+func loginPromptsForBackend(backend string) loginPrompts {
+	switch backend {
+	case config.BackendOpenAI:
+		return loginPrompts{
+			apiKey:          "Enter the OpenAI API Key: ",
+			endpoint:        "Enter the OpenAI API Endpoint (leave empty for https://api.openai.com/v1): ",
+			modelDeployment: "Enter the model name, e.g. gpt-4-turbo-preview: ",
+		}
+	case config.BackendOpenAICompatible:
+		return loginPrompts{
+			apiKey:          "Enter the API Key (leave empty if not required): ",
+			endpoint:        "Enter the base URL of the server, e.g. http://localhost:8080/v1: ",
+			modelDeployment: "Enter the model name: ",
+		}
+	default:
+		return loginPrompts{
+			apiKey:          "Enter the Azure OpenAI API Key: ",
+			endpoint:        "Enter the Azure OpenAI API Endpoint: ",
+			modelDeployment: "Enter the Azure OpenAI Model Deployment: ",
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/intility/cwc/internal"
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/templates"
+)
+
+// parameterFlags holds the values of the flags overriding the model and sampling parameters.
+type parameterFlags struct {
+	model       string
+	temperature float32
+	topP        float32
+	maxTokens   int
+	stop        []string
+	seed        int
+}
+
+func initParameterFlags(cmd *cobra.Command, flags *parameterFlags) {
+	cmd.Flags().StringVarP(&flags.model, "model", "m", "", "the model to use")
+	cmd.Flags().Float32Var(&flags.temperature, "temperature", 0, "the sampling temperature")
+	cmd.Flags().Float32Var(&flags.topP, "top-p", 0, "the nucleus sampling probability mass")
+	cmd.Flags().IntVar(&flags.maxTokens, "max-tokens", 0, "the maximum number of tokens in each answer")
+	cmd.Flags().StringSliceVar(&flags.stop, "stop", nil, "sequences where the model stops generating")
+	cmd.Flags().IntVar(&flags.seed, "seed", 0, "a seed for best effort deterministic sampling")
+
+	cmd.Flag("model").
+		Usage = "Specify the model to use, overriding the template and config. For example, --model gpt-4o"
+	cmd.Flag("temperature").
+		Usage = "Specify the sampling temperature between 0 and 2, overriding the template and config"
+	cmd.Flag("top-p").
+		Usage = "Specify the nucleus sampling probability mass between 0 and 1, overriding the template and config"
+	cmd.Flag("max-tokens").
+		Usage = "Specify the maximum number of tokens in each answer, overriding the template and config"
+}
+
+// overrides returns the parameters for the flags that were explicitly set.
+func (f *parameterFlags) overrides(cmd *cobra.Command) chat.Parameters {
+	var params chat.Parameters
+
+	flags := cmd.Flags()
+
+	if flags.Changed("model") {
+		params.Model = f.model
+	}
+
+	if flags.Changed("temperature") {
+		params.Temperature = &f.temperature
+	}
+
+	if flags.Changed("top-p") {
+		params.TopP = &f.topP
+	}
+
+	if flags.Changed("max-tokens") {
+		params.MaxTokens = f.maxTokens
+	}
+
+	if flags.Changed("stop") {
+		params.Stop = f.stop
+	}
+
+	if flags.Changed("seed") {
+		params.Seed = &f.seed
+	}
+
+	return params
+}
+
+// resolveParameters merges the parameters in order of precedence:
+// built-in defaults, then config, then template, then flags.
+func resolveParameters(
+	cfg *config.Config,
+	templateLocator templates.TemplateLocator,
+	templateName string,
+	flagOverrides chat.Parameters,
+) (chat.Parameters, error) {
+	params := chat.Parameters{Model: cfg.DefaultModel()}.Merge(cfg.Parameters)
+
+	tmpl, err := templateLocator.GetTemplate(templateName)
+	if err != nil && !errors.IsTemplateNotFoundError(err) {
+		return chat.Parameters{}, fmt.Errorf("error getting template: %w", err)
+	}
+
+	if tmpl != nil {
+		params = params.Merge(tmpl.Parameters)
+	}
+
+	return params.Merge(flagOverrides), nil
+}
+
+// parameterResolver resolves the parameters of any template with the same config and flag overrides.
+func parameterResolver(
+	cfg *config.Config,
+	templateLocator templates.TemplateLocator,
+	flagOverrides chat.Parameters,
+) internal.ParameterResolver {
+	return func(templateName string) (chat.Parameters, error) {
+		return resolveParameters(cfg, templateLocator, templateName, flagOverrides)
+	}
+}
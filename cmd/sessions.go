@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/spf13/cobra"
+
+	"github.com/intility/cwc/internal"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/sessions"
+	cwcui "github.com/intility/cwc/pkg/ui"
+)
+
+const (
+	sessionTimeFormat     = "2006-01-02 15:04"
+	sessionPromptMaxWidth = 50
+)
+
+func createSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List, show, resume or delete saved chat sessions",
+		Long: `Every interactive chat session is saved to the XDG state directory,
+typically ~/.local/state/cwc/sessions, including the messages, template and context patterns.
+Use the subcommands to pick up a previous conversation where you left off.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := cmd.Usage()
+			if err != nil {
+				return fmt.Errorf("failed to print usage: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(createListSessionsCmd())
+	cmd.AddCommand(createShowSessionCmd())
+	cmd.AddCommand(createResumeSessionCmd())
+	cmd.AddCommand(createDeleteSessionCmd())
+
+	return cmd
+}
+
+func createListSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved sessions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ui := cwcui.NewUI() //nolint:varnamelen
+
+			store, err := getSessionStore()
+			if err != nil {
+				return err
+			}
+
+			sessionList, err := store.List()
+			if err != nil {
+				return fmt.Errorf("error listing sessions: %w", err)
+			}
+
+			if len(sessionList) == 0 {
+				ui.PrintMessage("No saved sessions\n", cwcui.MessageTypeInfo)
+				return nil
+			}
+
+			table := [][]string{{"ID", "Updated", "Template", "Files", "Messages", "First prompt"}}
+			for _, session := range sessionList {
+				table = append(table, []string{
+					session.ID,
+					session.UpdatedAt.Local().Format(sessionTimeFormat),
+					session.TemplateName,
+					strconv.Itoa(len(session.Files)),
+					strconv.Itoa(len(session.Messages)),
+					truncate(session.FirstUserMessage(), sessionPromptMaxWidth),
+				})
+			}
+
+			printTable(table)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func createShowSessionCmd() *cobra.Command {
+	var showSystemMessage bool
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Print the messages of a saved session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ui := cwcui.NewUI() //nolint:varnamelen
+
+			session, err := loadSession(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			ui.PrintMessage("session: "+session.ID+"\n", cwcui.MessageTypeNotice)
+			ui.PrintMessage("template: "+session.TemplateName+"\n", cwcui.MessageTypeInfo)
+			ui.PrintMessage("include: "+strings.Join(session.IncludePatterns, ", ")+"\n", cwcui.MessageTypeInfo)
+			ui.PrintMessage("exclude: "+strings.Join(session.ExcludePatterns, ", ")+"\n", cwcui.MessageTypeInfo)
+			ui.PrintMessage("paths: "+strings.Join(session.Paths, ", ")+"\n", cwcui.MessageTypeInfo)
+			ui.PrintMessage("files:\n", cwcui.MessageTypeInfo)
+
+			for _, file := range session.Files {
+				ui.PrintMessage("  - "+file+"\n", cwcui.MessageTypeInfo)
+			}
+
+			ui.PrintMessage("\n", cwcui.MessageTypeInfo)
+
+			for _, message := range session.Messages {
+				switch message.Role {
+				case openai.ChatMessageRoleSystem:
+					if showSystemMessage {
+						ui.PrintMessage("⚙️: "+message.Content+"\n\n", cwcui.MessageTypeNotice)
+					}
+				case openai.ChatMessageRoleUser:
+					ui.PrintMessage("👤: "+message.Content+"\n\n", cwcui.MessageTypeInfo)
+				default:
+					ui.PrintMessage("🤖: "+message.Content+"\n\n", cwcui.MessageTypeInfo)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&showSystemMessage, "system", "s", false, "also print the system message")
+
+	return cmd
+}
+
+func createResumeSessionCmd() *cobra.Command {
+	var ignoreBudget bool
+
+	cmd := &cobra.Command{
+		Use:   "resume <id>",
+		Short: "Continue a saved session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			session, err := loadSession(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			cfgProvider, err := getPlatformSpecificConfigProvider()
+			if err != nil {
+				return fmt.Errorf("error getting config provider: %w", err)
+			}
+
+			cfg, err := cfgProvider.GetConfig()
+			if err != nil {
+				return fmt.Errorf("error loading config: %w", err)
+			}
+
+			// the parameters in effect when the session was saved take precedence
+			resolver := parameterResolver(cfg, getTemplateLocator(cfgProvider), session.Parameters)
+
+			params, err := resolver(session.TemplateName)
+			if err != nil {
+				return err
+			}
+
+			opts := internal.InteractiveChatOptions{
+				IncludePatterns:   session.IncludePatterns,
+				ExcludePatterns:   session.ExcludePatterns,
+				Paths:             session.Paths,
+				TemplateName:      session.TemplateName,
+				TemplateVariables: session.TemplateVariables,
+				Parameters:        params,
+				TokenBudget:       cfg.TokenBudget,
+				Compaction:        cfg.Compaction,
+				CompactionWindow:  cfg.CompactionWindow,
+				IgnoreBudget:      ignoreBudget,
+			}
+
+			interactiveCmd := createInteractiveCommand(nil, opts, cfgProvider, resolver)
+
+			err = interactiveCmd.Resume(session)
+			if err != nil {
+				return fmt.Errorf("error resuming session: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&ignoreBudget, "ignore-budget", false,
+		"send a request that exceeds the token limit without asking")
+
+	return cmd
+}
+
+func createDeleteSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id>...",
+		Short: "Delete one or more saved sessions",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ui := cwcui.NewUI() //nolint:varnamelen
+
+			store, err := getSessionStore()
+			if err != nil {
+				return err
+			}
+
+			for _, id := range args {
+				err = store.Delete(id)
+				if err != nil {
+					return suppressSessionNotFound(cmd, err)
+				}
+
+				ui.PrintMessage("deleted session "+id+"\n", cwcui.MessageTypeSuccess)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func loadSession(cmd *cobra.Command, id string) (*sessions.Session, error) {
+	store, err := getSessionStore()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := store.Load(id)
+	if err != nil {
+		return nil, suppressSessionNotFound(cmd, err)
+	}
+
+	return session, nil
+}
+
+// suppressSessionNotFound prints a friendly message for unknown session ids
+// instead of the usage text and the raw error.
+func suppressSessionNotFound(cmd *cobra.Command, err error) error {
+	if !errors.IsSessionNotFoundError(err) {
+		return fmt.Errorf("error loading session: %w", err)
+	}
+
+	ui := cwcui.NewUI() //nolint:varnamelen
+	ui.PrintMessage(err.Error()+"\n", cwcui.MessageTypeError)
+	ui.PrintMessage("use `cwc sessions list` to see the saved sessions\n", cwcui.MessageTypeInfo)
+
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	return errors.SuppressedError{Err: err}
+}
+
+func truncate(text string, width int) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+
+	return string(runes[:width-3]) + "..."
+}
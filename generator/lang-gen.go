@@ -1,15 +1,33 @@
 package main
 
 import (
+	"bytes"
+	"flag"
+	"go/format"
 	"gopkg.in/yaml.v3"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 )
 
+const defaultSource = "https://raw.githubusercontent.com/github/linguist/master/lib/linguist/languages.yml"
+
+// preferredLanguages win the extensions, filenames and interpreters they share with other
+// languages, in this order. Linguist tells them apart with heuristics on the contents instead.
+var preferredLanguages = []string{
+	"Markdown", "C", "PHP", "C++", "TypeScript", "JavaScript", "JSON", "YAML", "XML", "HTML", "Text", "SQL",
+	"Perl", "Objective-C", "MATLAB", "C#", "Rust", "F#", "R", "D", "Verilog", "Apex", "Makefile", "Lua",
+	"OCaml", "Raku", "Shell", "Python", "Ruby", "Go", "Java", "Kotlin", "Scala", "Assembly",
+}
+
+// fenceName matches the language names usable as they are after the opening fence of a code block.
+var fenceName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
 type Language struct {
 	Type               string   `yaml:"type"`
 	TmScope            string   `yaml:"tm_scope,omitempty"`
@@ -27,6 +45,15 @@ type Language struct {
 
 type Languages map[string]Language
 
+// Indexes are the languages by extension, filename and interpreter, and their code fence names.
+type Indexes struct {
+	Languages    Languages
+	Extensions   map[string]string
+	Filenames    map[string]string
+	Interpreters map[string]string
+	Fences       map[string]string
+}
+
 var languageTemplate = `// Code generated with lang-gen. DO NOT EDIT.
 
 package filetree
@@ -47,7 +74,7 @@ type Language struct {
 }
 
 var languages = map[string]Language{
-	{{- range $key, $value := .}}
+	{{- range $key, $value := .Languages}}
 	"{{ $key }}": {
 		Type: "{{ $value.Type }}",
 		TmScope: "{{ $value.TmScope }}",
@@ -64,6 +91,34 @@ var languages = map[string]Language{
 	},
 	{{- end}}
 }
+
+// languagesByExtension maps the extensions to the name of their language.
+var languagesByExtension = map[string]string{
+	{{- range $key, $value := .Extensions}}
+	"{{ $key }}": "{{ $value }}",
+	{{- end}}
+}
+
+// languagesByFilename maps the filenames to the name of their language.
+var languagesByFilename = map[string]string{
+	{{- range $key, $value := .Filenames}}
+	"{{ $key }}": "{{ $value }}",
+	{{- end}}
+}
+
+// languagesByInterpreter maps the interpreters of shebang lines to the name of their language.
+var languagesByInterpreter = map[string]string{
+	{{- range $key, $value := .Interpreters}}
+	"{{ $key }}": "{{ $value }}",
+	{{- end}}
+}
+
+// languageFences maps the names of the languages to their name in markdown code fences.
+var languageFences = map[string]string{
+	{{- range $key, $value := .Fences}}
+	"{{ $key }}": "{{ $value }}",
+	{{- end}}
+}
 `
 
 // join function will join the string slice with a comma
@@ -77,22 +132,102 @@ func join(sep string, s []string, surroundingStr string) string {
 	return surroundingStr + strings.Join(s, sep) + surroundingStr
 }
 
-func main() {
-
-	var languages Languages
+// readSource reads the languages.yml of linguist from a url or a local file.
+func readSource(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
 
 	// read the file from web
 	client := &http.Client{}
-	resp, err := client.Get("https://raw.githubusercontent.com/github/linguist/master/lib/linguist/languages.yml")
-
+	resp, err := client.Get(source)
 	if err != nil {
-		log.Fatalf("error: %v", err)
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
+	return io.ReadAll(resp.Body)
+}
+
+// rank orders the languages claiming the same key: the preferred languages first, then the
+// languages for which the key is the primary extension, then by name.
+func rank(languages Languages, key string) func(a, b string) bool {
+	preference := func(name string) int {
+		for i, preferred := range preferredLanguages {
+			if preferred == name {
+				return i
+			}
+		}
+
+		return len(preferredLanguages)
+	}
+
+	primary := func(name string) bool {
+		extensions := languages[name].Extensions
+		return len(extensions) > 0 && extensions[0] == key
+	}
+
+	return func(a, b string) bool {
+		if preference(a) != preference(b) {
+			return preference(a) < preference(b)
+		}
+
+		if primary(a) != primary(b) {
+			return primary(a)
+		}
+
+		return a < b
+	}
+}
+
+// index maps each key of the languages to the best ranked language claiming it.
+func index(languages Languages, keys func(Language) []string) map[string]string {
+	claims := map[string][]string{}
+
+	for name, language := range languages {
+		for _, key := range keys(language) {
+			claims[key] = append(claims[key], name)
+		}
+	}
 
+	indexed := make(map[string]string, len(claims))
+
+	for key, names := range claims {
+		sort.Slice(names, func(i, j int) bool { return rank(languages, key)(names[i], names[j]) })
+		indexed[key] = names[0]
+	}
+
+	return indexed
+}
+
+// fence returns the name of the language in code fences: its lower case name when it is a single
+// word, or else its first alias that is.
+func fence(name string, language Language) string {
+	candidates := append([]string{strings.ToLower(name)}, language.Aliases...)
+
+	for _, candidate := range candidates {
+		if fenceName.MatchString(candidate) {
+			return candidate
+		}
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == ' ' {
+			return '-'
+		}
+
+		return r
+	}, strings.ToLower(name))
+}
+
+func main() {
+	source := flag.String("source", defaultSource, "the url or path of the languages.yml of linguist")
+	flag.Parse()
+
+	var languages Languages
+
+	data, err := readSource(*source)
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
@@ -118,7 +253,32 @@ func main() {
 		log.Fatalf("error: %v", err)
 	}
 
-	err = tmpl.Execute(file, languages)
+	fences := make(map[string]string, len(languages))
+	for name, language := range languages {
+		fences[name] = fence(name, language)
+	}
+
+	indexes := Indexes{
+		Languages:    languages,
+		Extensions:   index(languages, func(l Language) []string { return l.Extensions }),
+		Filenames:    index(languages, func(l Language) []string { return l.Filenames }),
+		Interpreters: index(languages, func(l Language) []string { return l.Interpreters }),
+		Fences:       fences,
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, indexes)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	_, err = file.Write(formatted)
 	if err != nil {
 		log.Fatalf("error: %v", err)
 	}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/patch"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/ui"
+)
+
+const newFilePermissions = 0o644
+
+var (
+	errDeleteNotSupported = stderrors.New("deleting files is not supported, delete it yourself")
+	errOutsideWorkingDir  = stderrors.New("the file is outside the working directory")
+	errNotInContext       = stderrors.New("the file is not in the context")
+	errFileExists         = stderrors.New("the diff creates the file, but it already exists")
+	errNoApplyFiles       = stderrors.New("no files to apply the changes to")
+	errRedactedSecret     = stderrors.New("the change contains a redacted secret, edit the file yourself")
+)
+
+// ContextFilesRetriever gathers the files that proposed changes may be applied to.
+type ContextFilesRetriever interface {
+	RetrieveContext() (string, error)
+	ContextFilesProvider
+}
+
+// changeReviewer shows the changes proposed in an answer hunk by hunk, asks
+// the user about each of them and writes the accepted hunks to the files.
+type changeReviewer struct {
+	// prints the hunks and asks the questions
+	ui ui.UI
+	// the files in the context, which are the only existing files changes are applied to
+	files map[string]bool
+}
+
+func newChangeReviewer(u ui.UI, files []filetree.File) *changeReviewer {
+	contextFiles := make(map[string]bool, len(files))
+	for _, file := range files {
+		contextFiles[path.Clean(filepath.ToSlash(file.Path))] = true
+	}
+
+	return &changeReviewer{
+		ui:    u,
+		files: contextFiles,
+	}
+}
+
+// review applies the changes proposed in the answer that the user accepts.
+// Hunks that do not apply cleanly are refused. It returns the number of applied hunks.
+func (r *changeReviewer) review(answer string) (int, error) {
+	changes := patch.Parse(answer)
+	if len(changes) == 0 {
+		r.ui.PrintMessage("no changes found in the answer\n", ui.MessageTypeNotice)
+		return 0, nil
+	}
+
+	applied := 0
+
+	for _, change := range changes {
+		count, err := r.reviewChange(change)
+		if err != nil {
+			return applied, err
+		}
+
+		applied += count
+	}
+
+	return applied, nil
+}
+
+func (r *changeReviewer) reviewChange(change patch.Change) (int, error) {
+	// the context files are looked up by their clean paths, as ./pkg/a.go and pkg//a.go are pkg/a.go
+	change.Path = path.Clean(filepath.ToSlash(change.Path))
+
+	// the model only saw the placeholders, applying the change would write them over the secrets
+	if hasPlaceholder(change) {
+		r.ui.PrintMessage(fmt.Sprintf("skipping %s: %s\n", change.Path, errRedactedSecret), ui.MessageTypeWarning)
+		return 0, nil
+	}
+
+	original, err := r.originalContent(change)
+	if err != nil {
+		r.ui.PrintMessage(fmt.Sprintf("skipping %s: %s\n", change.Path, err), ui.MessageTypeWarning)
+		return 0, nil
+	}
+
+	hunks := change.Hunks
+	if change.WholeFile {
+		hunks = patch.Diff(original, change.Content)
+	}
+
+	if len(hunks) == 0 {
+		r.ui.PrintMessage(change.Path+" is unchanged\n", ui.MessageTypeNotice)
+		return 0, nil
+	}
+
+	content := original
+	applied := 0
+
+	for i, hunk := range hunks {
+		patched, err := patch.Apply(change.Path, content, hunk)
+		if err != nil {
+			r.ui.PrintMessage(fmt.Sprintf("refusing hunk %d of %d: %s\n", i+1, len(hunks), err), ui.MessageTypeWarning)
+			continue
+		}
+
+		r.printHunk(change.Path, hunk, i, len(hunks))
+
+		if r.ui.AskYesNo("Apply this hunk?", false) {
+			content = patched
+			applied++
+		}
+	}
+
+	if applied == 0 {
+		return 0, nil
+	}
+
+	err = writeChangedFile(change.Path, content)
+	if err != nil {
+		return 0, err
+	}
+
+	r.ui.PrintMessage(fmt.Sprintf("applied %d of %d hunks to %s\n", applied, len(hunks), change.Path),
+		ui.MessageTypeSuccess)
+
+	return applied, nil
+}
+
+// originalContent returns the current content of the file the change applies to,
+// refusing files outside the working directory and existing files outside the context.
+func (r *changeReviewer) originalContent(change patch.Change) (string, error) {
+	if change.Deleted {
+		return "", errDeleteNotSupported
+	}
+
+	if filepath.IsAbs(change.Path) || !filepath.IsLocal(filepath.FromSlash(change.Path)) {
+		return "", errOutsideWorkingDir
+	}
+
+	data, err := os.ReadFile(filepath.FromSlash(change.Path))
+
+	switch {
+	case stderrors.Is(err, fs.ErrNotExist):
+		// proposed new files are created
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("error reading file: %w", err)
+	case !r.files[change.Path]:
+		return "", errNotInContext
+	case change.Created:
+		return "", errFileExists
+	}
+
+	return string(data), nil
+}
+
+// hasPlaceholder reports whether the change contains the placeholder of a redacted secret.
+func hasPlaceholder(change patch.Change) bool {
+	if redact.HasPlaceholder(change.Content) {
+		return true
+	}
+
+	for _, hunk := range change.Hunks {
+		if redact.HasPlaceholder(hunk.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *changeReviewer) printHunk(path string, hunk patch.Hunk, index, count int) {
+	r.ui.PrintMessage(fmt.Sprintf("\n%s (hunk %d of %d)\n", path, index+1, count), ui.MessageTypeNotice)
+
+	for _, line := range strings.SplitAfter(hunk.String(), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			r.ui.PrintMessage(line, ui.MessageTypeNotice)
+		case strings.HasPrefix(line, "+"):
+			r.ui.PrintMessage(line, ui.MessageTypeSuccess)
+		case strings.HasPrefix(line, "-"):
+			r.ui.PrintMessage(line, ui.MessageTypeError)
+		default:
+			r.ui.PrintMessage(line, ui.MessageTypeInfo)
+		}
+	}
+}
+
+func writeChangedFile(path, content string) error {
+	name := filepath.FromSlash(path)
+
+	perm := fs.FileMode(newFilePermissions)
+	if info, err := os.Stat(name); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	err := os.MkdirAll(filepath.Dir(name), 0o755) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	err = os.WriteFile(name, []byte(content), perm)
+	if err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+
+	return nil
+}
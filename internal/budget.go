@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/tokens"
+	"github.com/intility/cwc/pkg/ui"
+)
+
+// ContextRecorder exposes the context that was rendered into the system message.
+type ContextRecorder interface {
+	LastContext() string
+}
+
+// tokenEstimate is the estimated size of the first request of a conversation.
+type tokenEstimate struct {
+	model         string
+	systemMessage int
+	context       int
+	// history is the size of the earlier messages of a resumed conversation
+	history       int
+	prompt        int
+	total         int
+	answerReserve int
+	limit         int
+	limitKnown    bool
+}
+
+func estimateTokens(
+	params chat.Parameters,
+	budget int,
+	systemMessage string,
+	ctx string,
+	history []openai.ChatCompletionMessage,
+	prompt string,
+) (tokenEstimate, error) {
+	counter, err := tokens.NewCounter(params.Model)
+	if err != nil {
+		return tokenEstimate{}, fmt.Errorf("error creating token counter: %w", err)
+	}
+
+	limit, limitKnown := tokens.Limit(params.Model, budget)
+
+	messages := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: systemMessage}}
+	messages = append(messages, history...)
+	messages = append(messages, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: prompt})
+
+	var historySize int
+	for _, message := range history {
+		historySize += counter.Count(message.Content)
+	}
+
+	return tokenEstimate{
+		model:         params.Model,
+		systemMessage: counter.Count(systemMessage),
+		context:       counter.Count(ctx),
+		history:       historySize,
+		prompt:        counter.Count(prompt),
+		total:         tokens.CountMessages(counter, messages),
+		answerReserve: params.MaxTokens,
+		limit:         limit,
+		limitKnown:    limitKnown,
+	}, nil
+}
+
+// exceedsLimit reports whether the request and the reserved answer tokens do not fit within the limit.
+func (e tokenEstimate) exceedsLimit() bool {
+	return e.limitKnown && e.total+e.answerReserve > e.limit
+}
+
+func (e tokenEstimate) err() error {
+	return errors.TokenBudgetExceededError{Tokens: e.total + e.answerReserve, Limit: e.limit}
+}
+
+func (e tokenEstimate) print(u ui.UI) {
+	limit := "unknown limit"
+	if e.limitKnown {
+		limit = fmt.Sprintf("limit %d", e.limit)
+	}
+
+	message := fmt.Sprintf("tokens: system message %d (context %d), ", e.systemMessage, e.context)
+	if e.history > 0 {
+		message += fmt.Sprintf("history %d, ", e.history)
+	}
+
+	message += fmt.Sprintf("prompt %d, total %d of %s for %s", e.prompt, e.total, limit, e.model)
+
+	if e.answerReserve > 0 {
+		message += fmt.Sprintf(", %d reserved for the answer", e.answerReserve)
+	}
+
+	messageType := ui.MessageTypeNotice
+	if e.exceedsLimit() {
+		messageType = ui.MessageTypeWarning
+	}
+
+	u.PrintMessage(message+"\n", messageType)
+}
@@ -0,0 +1,380 @@
+package internal
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/slashcommand"
+	"github.com/intility/cwc/pkg/ui"
+)
+
+var (
+	errNothingToRetry   = stderrors.New("there is no message to retry")
+	errNothingToUndo    = stderrors.New("there is no message to undo")
+	errContextNotEdited = stderrors.New("the context can not be changed in this session")
+	errTemplateFixed    = stderrors.New("the template can not be changed in this session")
+	errNothingToApply   = stderrors.New("there is no answer to apply")
+)
+
+// ContextEditor changes which files are included in the context during the chat.
+type ContextEditor interface {
+	AddInclude(pathOrPattern string) error
+	Drop(path string) error
+}
+
+// TemplateSwitcher changes the template used to generate the system message.
+type TemplateSwitcher interface {
+	SetTemplateName(templateName string)
+	TemplateName() string
+}
+
+// ParameterResolver resolves the model parameters for a template.
+type ParameterResolver func(templateName string) (chat.Parameters, error)
+
+// newCommandRegistry registers the slash commands available at the chat prompt.
+func (c *InteractiveCmd) newCommandRegistry(conversation *chat.Conversation) *slashcommand.Registry { //nolint:funlen
+	registry := slashcommand.NewRegistry()
+
+	registry.Register(slashcommand.Command{
+		Name:        "exit",
+		Args:        "",
+		Description: "end the chat",
+		Run: func(string) error {
+			return slashcommand.ErrExit
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "help",
+		Args:        "",
+		Description: "list the available commands",
+		Run: func(string) error {
+			c.printHelp(registry)
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "edit",
+		Args:        "[text]",
+		Description: "compose a message in $EDITOR, starting from the optional text",
+		Run: func(args string) error {
+			return c.composeMessage(conversation, args)
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "clear",
+		Args:        "",
+		Description: "forget the conversation, keeping the system message and context",
+		Run: func(string) error {
+			conversation.Clear()
+			c.ui.PrintMessage("conversation cleared\n", ui.MessageTypeSuccess)
+
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "retry",
+		Args:        "",
+		Description: "discard the last answer and ask again",
+		Run: func(string) error {
+			if !conversation.Retry() {
+				return errNothingToRetry
+			}
+
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "undo",
+		Args:        "",
+		Description: "remove your last message and its answer",
+		Run: func(string) error {
+			if !conversation.Undo() {
+				return errNothingToUndo
+			}
+
+			c.ui.PrintMessage("removed the last message and its answer\n", ui.MessageTypeSuccess)
+
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "system",
+		Args:        "",
+		Description: "show the system message",
+		Run: func(string) error {
+			c.ui.PrintMessage(conversation.SystemMessage()+"\n", ui.MessageTypeInfo)
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "context",
+		Args:        "",
+		Description: "list the files in the context",
+		Run: func(string) error {
+			c.printContextFiles()
+			return nil
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "add",
+		Args:        "<path|regex>",
+		Description: "add a file, or the files matching a regular expression, to the context",
+		Run: func(args string) error {
+			return c.editContext(conversation, "/add <path|regex>", args, func(pathOrPattern string) error {
+				return c.contextEditor.AddInclude(pathOrPattern)
+			})
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "drop",
+		Args:        "<path>",
+		Description: "remove a file from the context",
+		Run: func(args string) error {
+			return c.editContext(conversation, "/drop <path>", args, func(path string) error {
+				return c.contextEditor.Drop(path)
+			})
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "template",
+		Args:        "<name>",
+		Description: "switch to another template",
+		Run: func(args string) error {
+			return c.switchTemplate(conversation, args)
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "model",
+		Args:        "<name>",
+		Description: "switch to another model",
+		Run: func(args string) error {
+			return c.switchModel(conversation, args)
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "apply",
+		Args:        "",
+		Description: "review the changes proposed in the last answer and apply them",
+		Run: func(string) error {
+			return c.applyChanges(conversation)
+		},
+	})
+	registry.Register(slashcommand.Command{
+		Name:        "save",
+		Args:        "<file>",
+		Description: "save the conversation as markdown",
+		Run: func(args string) error {
+			return c.saveTranscript(conversation, args)
+		},
+	})
+
+	return registry
+}
+
+// completeCommand completes the command names at the prompt.
+func (c *InteractiveCmd) completeCommand(text string) []string {
+	if c.commands == nil {
+		return nil
+	}
+
+	return c.commands.Complete(text)
+}
+
+func (c *InteractiveCmd) printHelp(registry *slashcommand.Registry) {
+	for _, command := range registry.Commands() {
+		c.ui.PrintMessage(fmt.Sprintf("  %-22s %s\n", command.Usage(), command.Description), ui.MessageTypeInfo)
+	}
+}
+
+func (c *InteractiveCmd) printContextFiles() {
+	if c.contextFiles == nil || len(c.contextFiles.Files()) == 0 {
+		c.ui.PrintMessage("no files in context\n", ui.MessageTypeInfo)
+		return
+	}
+
+	files := c.contextFiles.Files()
+
+	c.ui.PrintMessage(fmt.Sprintf("%d files in context:\n", len(files)), ui.MessageTypeInfo)
+
+	for _, file := range files {
+		c.ui.PrintMessage(fmt.Sprintf("  - %s (%d bytes)\n", file.Path, len(file.Data)), ui.MessageTypeInfo)
+	}
+}
+
+// editContext changes the context with edit and regenerates the system message.
+func (c *InteractiveCmd) editContext(
+	conversation *chat.Conversation,
+	usage string,
+	args string,
+	edit func(string) error,
+) error {
+	if args == "" {
+		return errors.ArgParseError{Message: "missing argument, usage: " + usage}
+	}
+
+	if c.contextEditor == nil {
+		return errContextNotEdited
+	}
+
+	err := edit(args)
+	if err != nil {
+		return fmt.Errorf("error changing the context: %w", err)
+	}
+
+	return c.regenerateSystemMessage(conversation)
+}
+
+// composeMessage sends the message written in the user's editor.
+func (c *InteractiveCmd) composeMessage(conversation *chat.Conversation, initial string) error {
+	message, err := ui.OpenEditor(initial)
+	if err != nil {
+		return fmt.Errorf("error composing message: %w", err)
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		c.ui.PrintMessage("empty message, nothing sent\n", ui.MessageTypeNotice)
+		return nil
+	}
+
+	c.ui.PrintMessage(message+"\n", ui.MessageTypeInfo)
+	c.send(conversation, message)
+
+	return nil
+}
+
+func (c *InteractiveCmd) switchTemplate(conversation *chat.Conversation, templateName string) error {
+	if c.templateSwitcher == nil {
+		return errTemplateFixed
+	}
+
+	if templateName == "" {
+		c.ui.PrintMessage("template: "+c.templateSwitcher.TemplateName()+"\n", ui.MessageTypeInfo)
+		return nil
+	}
+
+	previous := c.templateSwitcher.TemplateName()
+	c.templateSwitcher.SetTemplateName(templateName)
+
+	err := c.regenerateSystemMessage(conversation)
+	if err != nil {
+		c.templateSwitcher.SetTemplateName(previous)
+		return err
+	}
+
+	if c.resolveParameters != nil {
+		params, err := c.resolveParameters(templateName)
+		if err != nil {
+			// the parameters were not changed, go back to the system message of the previous template
+			c.templateSwitcher.SetTemplateName(previous)
+			if regenerateErr := c.regenerateSystemMessage(conversation); regenerateErr != nil {
+				return stderrors.Join(err, regenerateErr)
+			}
+
+			return err
+		}
+
+		conversation.SetParameters(params)
+	}
+
+	if c.session != nil {
+		c.session.TemplateName = templateName
+		c.session.Parameters = conversation.Parameters()
+	}
+
+	c.ui.PrintMessage("switched to template "+templateName+"\n", ui.MessageTypeSuccess)
+
+	return nil
+}
+
+func (c *InteractiveCmd) switchModel(conversation *chat.Conversation, model string) error {
+	params := conversation.Parameters()
+
+	if model == "" {
+		c.ui.PrintMessage("model: "+params.Model+"\n", ui.MessageTypeInfo)
+		return nil
+	}
+
+	params.Model = model
+	conversation.SetParameters(params)
+
+	if c.session != nil {
+		c.session.Parameters = params
+	}
+
+	c.ui.PrintMessage("switched to model "+model+"\n", ui.MessageTypeSuccess)
+
+	return nil
+}
+
+// applyChanges reviews the diffs and the code blocks annotated with a path in the last answer.
+func (c *InteractiveCmd) applyChanges(conversation *chat.Conversation) error {
+	messages := conversation.Messages()
+	if len(messages) == 0 || messages[len(messages)-1].Role != openai.ChatMessageRoleAssistant {
+		return errNothingToApply
+	}
+
+	var files []filetree.File
+	if c.contextFiles != nil {
+		files = c.contextFiles.Files()
+	}
+
+	_, err := newChangeReviewer(c.ui, files).review(messages[len(messages)-1].Content)
+	if err != nil {
+		return fmt.Errorf("error applying changes: %w", err)
+	}
+
+	return nil
+}
+
+func (c *InteractiveCmd) saveTranscript(conversation *chat.Conversation, path string) error {
+	if path == "" {
+		return errors.ArgParseError{Message: "missing argument, usage: /save <file>"}
+	}
+
+	var transcript strings.Builder
+
+	for _, message := range conversation.Messages() {
+		switch message.Role {
+		case openai.ChatMessageRoleUser:
+			transcript.WriteString("## 👤 User\n\n" + message.Content + "\n\n")
+		case openai.ChatMessageRoleAssistant:
+			transcript.WriteString("## 🤖 Assistant\n\n" + message.Content + "\n\n")
+		}
+	}
+
+	err := os.WriteFile(path, []byte(transcript.String()), 0o600)
+	if err != nil {
+		return fmt.Errorf("error saving conversation: %w", err)
+	}
+
+	c.ui.PrintMessage("saved conversation to "+path+"\n", ui.MessageTypeSuccess)
+
+	return nil
+}
+
+// regenerateSystemMessage rebuilds the system message after the context or template changed.
+func (c *InteractiveCmd) regenerateSystemMessage(conversation *chat.Conversation) error {
+	systemMessage, err := c.smGenerator.GenerateSystemMessage()
+	if err != nil {
+		return fmt.Errorf("error creating system message: %w", err)
+	}
+
+	conversation.SetSystemMessage(systemMessage)
+
+	if c.session != nil && c.contextFiles != nil {
+		c.session.Files = nil
+		for _, file := range c.contextFiles.Files() {
+			c.session.Files = append(c.session.Files, file.Path)
+		}
+	}
+
+	return nil
+}
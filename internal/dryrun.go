@@ -0,0 +1,231 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/prompting"
+	"github.com/intility/cwc/pkg/systemcontext"
+	"github.com/intility/cwc/pkg/tokens"
+	"github.com/intility/cwc/pkg/ui"
+)
+
+// DryRunCmd prints the request the chat would send for the prompt, without calling the API.
+type DryRunCmd struct {
+	ui             ui.UI
+	writer         io.Writer
+	promptResolver prompting.PromptResolver
+	smGenerator    systemcontext.SystemMessageGenerator
+	chatOptions    InteractiveChatOptions
+	// the files included in the context and the context rendered into the system message
+	includedFiles ContextFilesProvider
+	contextRecord ContextRecorder
+}
+
+// dryRunReport is the request printed with the json and ndjson output formats. The request
+// is the body sent to the API, the token counts are estimated locally.
+type dryRunReport struct {
+	Request  openai.ChatCompletionRequest `json:"request"`
+	Tokens   *dryRunTokens                `json:"tokens,omitempty"`
+	Files    []string                     `json:"files"`
+	Template string                       `json:"template"`
+}
+
+type dryRunTokens struct {
+	// Messages holds the tokens of each message of the request, in order
+	Messages      []int `json:"messages"`
+	SystemMessage int   `json:"system_message"`
+	Context       int   `json:"context"`
+	Prompt        int   `json:"prompt"`
+	Total         int   `json:"total"`
+	AnswerReserve int   `json:"answer_reserve"`
+	// Limit is the context window of the model or the token budget, whichever is smaller, when known
+	Limit        *int `json:"limit,omitempty"`
+	ExceedsLimit bool `json:"exceeds_limit"`
+}
+
+func NewDryRunCmd(
+	promptResolver prompting.PromptResolver,
+	smGenerator systemcontext.SystemMessageGenerator,
+	chatOptions InteractiveChatOptions,
+	includedFiles ContextFilesProvider,
+	contextRecord ContextRecorder,
+) *DryRunCmd {
+	return &DryRunCmd{
+		ui:             ui.NewUI(),
+		writer:         os.Stdout,
+		promptResolver: promptResolver,
+		smGenerator:    smGenerator,
+		chatOptions:    chatOptions,
+		includedFiles:  includedFiles,
+		contextRecord:  contextRecord,
+	}
+}
+
+// Run gathers the context, resolves the prompt and prints the request that would be sent.
+func (c *DryRunCmd) Run() error {
+	systemMessage, err := c.smGenerator.GenerateSystemMessage()
+	if err != nil {
+		return fmt.Errorf("error creating system message: %w", err)
+	}
+
+	prompt := c.promptResolver.ResolvePrompt()
+	request := chat.NewChat(nil, systemMessage, nil, chat.WithParameters(c.chatOptions.Parameters)).Request(prompt)
+
+	if prompt == "" {
+		// the chat asks for the prompt, an empty user message is not what would be sent
+		request.Messages = request.Messages[:len(request.Messages)-1]
+	}
+
+	var files []string
+	if c.includedFiles != nil {
+		files = filePaths(c.includedFiles.Files())
+	}
+
+	var ctx string
+	if c.contextRecord != nil {
+		ctx = c.contextRecord.LastContext()
+	}
+
+	estimate, err := estimateTokens(c.chatOptions.Parameters, c.chatOptions.TokenBudget, systemMessage, ctx, nil, prompt)
+	if err != nil {
+		c.ui.PrintMessage(fmt.Sprintf("warning: could not estimate tokens: %s\n", err), ui.MessageTypeWarning)
+	}
+
+	if c.chatOptions.Output != OutputText {
+		return c.printJSON(request, files, estimate, err == nil)
+	}
+
+	c.printText(request, files, estimate, err == nil)
+
+	return nil
+}
+
+func (c *DryRunCmd) printJSON(
+	request openai.ChatCompletionRequest,
+	files []string,
+	estimate tokenEstimate,
+	estimated bool,
+) error {
+	report := dryRunReport{
+		Request:  request,
+		Tokens:   nil,
+		Files:    files,
+		Template: c.chatOptions.TemplateName,
+	}
+
+	if report.Files == nil {
+		report.Files = []string{}
+	}
+
+	if estimated {
+		report.Tokens = &dryRunTokens{
+			Messages:      countEachMessage(request.Model, request.Messages),
+			SystemMessage: estimate.systemMessage,
+			Context:       estimate.context,
+			Prompt:        estimate.prompt,
+			Total:         estimate.total,
+			AnswerReserve: estimate.answerReserve,
+			Limit:         nil,
+			ExceedsLimit:  estimate.exceedsLimit(),
+		}
+
+		if estimate.limitKnown {
+			report.Tokens.Limit = &estimate.limit
+		}
+	}
+
+	encoder := json.NewEncoder(c.writer)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(report)
+	if err != nil {
+		return fmt.Errorf("error printing request: %w", err)
+	}
+
+	return nil
+}
+
+func (c *DryRunCmd) printText(request openai.ChatCompletionRequest, files []string, estimate tokenEstimate, estimated bool) {
+	c.ui.PrintMessage(fmt.Sprintf("model: %s\n", request.Model), ui.MessageTypeInfo)
+
+	if parameters := formatParameters(c.chatOptions.Parameters); parameters != "" {
+		c.ui.PrintMessage(fmt.Sprintf("parameters: %s\n", parameters), ui.MessageTypeInfo)
+	}
+
+	c.ui.PrintMessage(fmt.Sprintf("files: %d\n", len(files)), ui.MessageTypeInfo)
+
+	for _, file := range files {
+		c.ui.PrintMessage("  "+file+"\n", ui.MessageTypeInfo)
+	}
+
+	if estimated {
+		estimate.print(c.ui)
+	}
+
+	counts := countEachMessage(request.Model, request.Messages)
+
+	for i, message := range request.Messages {
+		header := "--- " + message.Role
+		if counts != nil {
+			header += fmt.Sprintf(" (%d tokens)", counts[i])
+		}
+
+		c.ui.PrintMessage("\n"+header+" ---\n", ui.MessageTypeNotice)
+		c.ui.PrintMessage(message.Content+"\n", ui.MessageTypeInfo)
+	}
+
+	if request.Messages[len(request.Messages)-1].Role != openai.ChatMessageRoleUser {
+		c.ui.PrintMessage("\n--- user ---\n", ui.MessageTypeNotice)
+		c.ui.PrintMessage("(the prompt typed when the chat starts)\n", ui.MessageTypeNotice)
+	}
+}
+
+// countEachMessage counts the tokens of the content of each message, or returns nil without a tokenizer.
+func countEachMessage(model string, messages []openai.ChatCompletionMessage) []int {
+	counter, err := tokens.NewCounter(model)
+	if err != nil {
+		return nil
+	}
+
+	counts := make([]int, 0, len(messages))
+	for _, message := range messages {
+		counts = append(counts, counter.Count(message.Content))
+	}
+
+	return counts
+}
+
+// formatParameters lists the sampling parameters that are set, named like in the config.
+func formatParameters(params chat.Parameters) string {
+	var parts []string
+
+	if params.Temperature != nil {
+		parts = append(parts, "temperature="+strconv.FormatFloat(float64(*params.Temperature), 'g', -1, 32))
+	}
+
+	if params.TopP != nil {
+		parts = append(parts, "topP="+strconv.FormatFloat(float64(*params.TopP), 'g', -1, 32))
+	}
+
+	if params.MaxTokens != 0 {
+		parts = append(parts, "maxTokens="+strconv.Itoa(params.MaxTokens))
+	}
+
+	if len(params.Stop) > 0 {
+		parts = append(parts, "stop="+strings.Join(params.Stop, ","))
+	}
+
+	if params.Seed != nil {
+		parts = append(parts, "seed="+strconv.Itoa(*params.Seed))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -1,23 +1,56 @@
 package internal
 
 import (
+	stderrors "errors"
 	"fmt"
+	"os"
+	"os/signal"
 
 	"github.com/sashabaranov/go-openai"
 
 	"github.com/intility/cwc/pkg/chat"
 	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
 	"github.com/intility/cwc/pkg/prompting"
+	"github.com/intility/cwc/pkg/sessions"
+	"github.com/intility/cwc/pkg/slashcommand"
 	"github.com/intility/cwc/pkg/systemcontext"
 	"github.com/intility/cwc/pkg/ui"
 )
 
 type InteractiveChatOptions struct {
-	IncludePattern    string
-	ExcludePattern    string
+	IncludePatterns   []string
+	ExcludePatterns   []string
 	Paths             []string
 	TemplateName      string
 	TemplateVariables map[string]string
+	Parameters        chat.Parameters
+	TokenBudget       int
+	Compaction        string
+	CompactionWindow  int
+	// Apply reviews and applies the changes proposed in the answer in non-interactive mode
+	Apply bool
+	// Output is the format of the answer in non-interactive mode, one of OutputFormats
+	Output string
+	// ContextFiles are added to the context as they are, whatever their type
+	ContextFiles []string
+	// GatherFiles adds the matched files to the piped context in non-interactive mode,
+	// it is set when the files to include are given explicitly
+	GatherFiles bool
+	// Git selects the files of the context from git and adds their changes and commits
+	Git systemcontext.GitSelection
+	// Refs are git revisions to read the files from instead of the working tree, side by side
+	Refs []string
+	// DryRun prints the request instead of sending it
+	DryRun bool
+	// IgnoreBudget sends a first request exceeding the token limit without asking or failing
+	IgnoreBudget bool
+}
+
+// ContextFilesProvider exposes the files that were included in the chat context.
+type ContextFilesProvider interface {
+	Files() []filetree.File
 }
 
 type InteractiveCmd struct {
@@ -26,6 +59,67 @@ type InteractiveCmd struct {
 	promptResolver prompting.PromptResolver
 	smGenerator    systemcontext.SystemMessageGenerator
 	chatOptions    InteractiveChatOptions
+	contextFiles   ContextFilesProvider
+	contextRecord  ContextRecorder
+	sessionStore   sessions.Store
+	session        *sessions.Session
+	// used by the slash commands
+	contextEditor     ContextEditor
+	templateSwitcher  TemplateSwitcher
+	resolveParameters ParameterResolver
+	commands          *slashcommand.Registry
+	input             LineReader
+	// set once the size of the first request was reported and accepted
+	budgetChecked bool
+	// renders the answers as markdown, nil when stdout is not a terminal
+	renderer *ui.MarkdownRenderer
+}
+
+const userPrompt = "👤: "
+
+// LineReader reads the user's messages, returning io.EOF when the user ends the input.
+type LineReader interface {
+	ReadLine() (string, error)
+}
+
+// InteractiveOpt configures optional behaviour of the InteractiveCmd.
+type InteractiveOpt func(*InteractiveCmd)
+
+// WithContextFiles sets the provider used to list the files in the chat context.
+func WithContextFiles(provider ContextFilesProvider) InteractiveOpt {
+	return func(c *InteractiveCmd) {
+		c.contextFiles = provider
+	}
+}
+
+// WithContextRecorder sets the recorder used to measure the context in the token estimate.
+func WithContextRecorder(recorder ContextRecorder) InteractiveOpt {
+	return func(c *InteractiveCmd) {
+		c.contextRecord = recorder
+	}
+}
+
+// WithSessionStore enables persisting the conversation to the given store after each turn.
+func WithSessionStore(store sessions.Store) InteractiveOpt {
+	return func(c *InteractiveCmd) {
+		c.sessionStore = store
+	}
+}
+
+// WithContextEditor enables the /add and /drop commands.
+func WithContextEditor(editor ContextEditor) InteractiveOpt {
+	return func(c *InteractiveCmd) {
+		c.contextEditor = editor
+	}
+}
+
+// WithTemplateSwitcher enables the /template command, the resolver provides
+// the model parameters for the new template.
+func WithTemplateSwitcher(switcher TemplateSwitcher, resolver ParameterResolver) InteractiveOpt {
+	return func(c *InteractiveCmd) {
+		c.templateSwitcher = switcher
+		c.resolveParameters = resolver
+	}
 }
 
 func NewInteractiveCmd(
@@ -33,20 +127,41 @@ func NewInteractiveCmd(
 	clientProvider config.ClientProvider,
 	smGenerator systemcontext.SystemMessageGenerator,
 	chatOptions InteractiveChatOptions,
+	opts ...InteractiveOpt,
 ) *InteractiveCmd {
-	return &InteractiveCmd{
+	cmd := &InteractiveCmd{
 		ui:             ui.NewUI(),
 		promptResolver: promptResolver,
 		clientProvider: clientProvider,
 		chatOptions:    chatOptions,
 		smGenerator:    smGenerator,
+		contextFiles:   nil,
+		contextRecord:  nil,
+		sessionStore:   nil,
+		session:        nil,
+
+		contextEditor:     nil,
+		templateSwitcher:  nil,
+		resolveParameters: nil,
+		commands:          nil,
+		input:             nil,
+		budgetChecked:     false,
+		renderer:          ui.NewTerminalMarkdownRenderer(os.Stdout),
 	}
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	cmd.input = ui.NewLineEditor(userPrompt, cmd.completeCommand)
+
+	return cmd
 }
 
 func (c *InteractiveCmd) Run() error {
-	openaiClient, err := c.clientProvider.NewClientFromConfig()
+	backend, err := c.clientProvider.NewClientFromConfig()
 	if err != nil {
-		return fmt.Errorf("error creating openaiClient: %w", err)
+		return fmt.Errorf("error creating chat backend: %w", err)
 	}
 
 	generatedSystemMessage, err := c.smGenerator.GenerateSystemMessage()
@@ -54,41 +169,297 @@ func (c *InteractiveCmd) Run() error {
 		return fmt.Errorf("error creating system message: %w", err)
 	}
 
-	c.ui.PrintMessage("Type '/exit' to end the chat.\n", ui.MessageTypeNotice)
+	c.ui.PrintMessage("Type '/help' to list the commands, Alt-Enter for a new line and Ctrl-C to stop an answer.\n", ui.MessageTypeNotice)
 
-	userPrompt := c.promptResolver.ResolvePrompt()
+	c.session, err = c.newSession()
+	if err != nil {
+		return err
+	}
 
-	if userPrompt == "" {
-		c.ui.PrintMessage("👤: ", ui.MessageTypeInfo)
-		userPrompt = c.ui.ReadUserInput()
-	} else {
-		c.ui.PrintMessage(fmt.Sprintf("👤: %s\n", userPrompt), ui.MessageTypeInfo)
+	chatOpts, err := c.chatOpts(backend)
+	if err != nil {
+		return err
 	}
 
-	if userPrompt == "/exit" {
+	chatInstance := chat.NewChat(backend, generatedSystemMessage, c.printMessageChunk, chatOpts...)
+	// nothing is sent until the first message that is not a command
+	conversation := chatInstance.ResumeConversation([]openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: generatedSystemMessage},
+	})
+	c.commands = c.newCommandRegistry(conversation)
+
+	userPrompt, ok := c.readFirstMessage(conversation)
+	if !ok {
 		return nil
 	}
 
-	c.handleChat(openaiClient, generatedSystemMessage, userPrompt)
+	// a command such as /edit may have sent the first message already
+	if userPrompt != "" {
+		c.send(conversation, userPrompt)
+	}
+
+	c.handleChat(conversation)
+
+	return nil
+}
+
+// readFirstMessage returns the prompt given on the command line, or reads the first message,
+// running the commands typed before it. It reports false when the chat ends before anything was
+// asked, and returns an empty message when a command sent the first message.
+func (c *InteractiveCmd) readFirstMessage(conversation *chat.Conversation) (string, bool) {
+	userPrompt := c.promptResolver.ResolvePrompt()
+	if userPrompt != "" {
+		c.ui.PrintMessage(fmt.Sprintf("👤: %s\n", userPrompt), ui.MessageTypeInfo)
+	}
+
+	for userPrompt == "" || slashcommand.IsCommand(userPrompt) {
+		if userPrompt != "" {
+			err := c.commands.Execute(userPrompt)
+			if stderrors.Is(err, slashcommand.ErrExit) {
+				return "", false
+			}
+
+			c.printCommandError(err)
+
+			if len(conversation.Messages()) > 1 {
+				return "", true
+			}
+		}
+
+		var err error
+
+		userPrompt, err = c.input.ReadLine()
+		if err != nil {
+			return "", false // the user ended the input before asking anything
+		}
+	}
+
+	return userPrompt, true
+}
+
+// Resume continues a previously saved session.
+func (c *InteractiveCmd) Resume(session *sessions.Session) error {
+	backend, err := c.clientProvider.NewClientFromConfig()
+	if err != nil {
+		return fmt.Errorf("error creating chat backend: %w", err)
+	}
+
+	c.session = session
+
+	c.ui.PrintMessage(fmt.Sprintf("Resuming session %s with %d files in context.\n",
+		session.ID, len(session.Files)), ui.MessageTypeNotice)
+	c.ui.PrintMessage("Type '/help' to list the commands, Alt-Enter for a new line and Ctrl-C to stop an answer.\n", ui.MessageTypeNotice)
+
+	for _, message := range session.Messages {
+		printHistoryMessage(c.ui, message)
+	}
+
+	chatOpts, err := c.chatOpts(backend)
+	if err != nil {
+		return err
+	}
+
+	chatInstance := chat.NewChat(backend, "", c.printMessageChunk, chatOpts...)
+	conversation := chatInstance.ResumeConversation(toChatMessages(session.Messages))
+
+	c.handleChat(conversation)
 
 	return nil
 }
 
-func (c *InteractiveCmd) handleChat(client *openai.Client, systemMessage string, prompt string) {
-	chatInstance := chat.NewChat(client, systemMessage, c.printMessageChunk)
-	conversation := chatInstance.BeginConversation(prompt)
+// chatOpts configures the parameters and the history compaction of the chat.
+func (c *InteractiveCmd) chatOpts(backend chat.Backend) ([]chat.Option, error) {
+	compactor, err := chat.NewCompactor(c.chatOptions.Compaction, c.chatOptions.CompactionWindow, backend)
+	if err != nil {
+		return nil, fmt.Errorf("error creating compactor: %w", err)
+	}
+
+	return []chat.Option{
+		chat.WithParameters(c.chatOptions.Parameters),
+		chat.WithCompactor(compactor, c.chatOptions.TokenBudget),
+		chat.WithNoticeHandler(func(message string) {
+			c.ui.PrintMessage(message+"\n", ui.MessageTypeNotice)
+		}),
+	}, nil
+}
+
+// send sends the message. The first request of the chat, however the message was written, is
+// only sent once its estimated size is reported and accepted.
+func (c *InteractiveCmd) send(conversation *chat.Conversation, message string) {
+	if !c.budgetChecked {
+		if !c.confirmTokenBudget(conversation, message) {
+			c.ui.PrintMessage("nothing sent, /drop files or switch to a /model with a larger context\n",
+				ui.MessageTypeNotice)
+
+			return
+		}
+
+		c.budgetChecked = true
+	}
+
+	conversation.Reply(message)
+}
+
+// confirmTokenBudget reports the estimated size of the first request, with the history of a resumed
+// chat, and asks the user for confirmation if it exceeds the model's context window or the budget.
+func (c *InteractiveCmd) confirmTokenBudget(conversation *chat.Conversation, prompt string) bool {
+	var ctx string
+	if c.contextRecord != nil {
+		ctx = c.contextRecord.LastContext()
+	}
+
+	var history []openai.ChatCompletionMessage
+	if messages := conversation.Messages(); len(messages) > 1 {
+		history = messages[1:]
+	}
+
+	estimate, err := estimateTokens(conversation.Parameters(), c.chatOptions.TokenBudget,
+		conversation.SystemMessage(), ctx, history, prompt)
+	if err != nil {
+		c.ui.PrintMessage(fmt.Sprintf("warning: could not estimate tokens: %s\n", err), ui.MessageTypeWarning)
+		return true
+	}
+
+	estimate.print(c.ui)
+
+	if !estimate.exceedsLimit() || c.chatOptions.IgnoreBudget {
+		return true
+	}
+
+	return c.ui.AskYesNo("The request exceeds the token limit and will likely fail, send it anyway?", false)
+}
+
+func (c *InteractiveCmd) handleChat(conversation *chat.Conversation) {
+	// Ctrl-C cancels the answer in progress, or ends the chat at the prompt
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	defer signal.Stop(interrupts)
+
+	if c.commands == nil {
+		c.commands = c.newCommandRegistry(conversation)
+	}
 
 	for {
+		waitForAnswer(conversation, interrupts)
+		c.saveSession(conversation)
+
+		userMessage, interrupted := c.readUserInput(interrupts)
+		if interrupted {
+			c.ui.PrintMessage("\n", ui.MessageTypeInfo)
+			break
+		}
+
+		if userMessage == "" {
+			continue
+		}
+
+		if slashcommand.IsCommand(userMessage) {
+			err := c.commands.Execute(userMessage)
+			if stderrors.Is(err, slashcommand.ErrExit) {
+				break
+			}
+
+			c.printCommandError(err)
+
+			continue
+		}
+
+		c.send(conversation, userMessage)
+	}
+
+	if c.sessionStore != nil && c.session != nil {
+		c.ui.PrintMessage(fmt.Sprintf("Session saved, resume it with: cwc sessions resume %s\n", c.session.ID),
+			ui.MessageTypeNotice)
+	}
+}
+
+func (c *InteractiveCmd) printCommandError(err error) {
+	if err == nil {
+		return
+	}
+
+	c.ui.PrintMessage(err.Error()+"\n", ui.MessageTypeError)
+
+	if errors.IsUnknownCommandError(err) {
+		c.ui.PrintMessage("type /help to list the commands\n", ui.MessageTypeInfo)
+	}
+}
+
+// waitForAnswer waits until the answer is complete, cancelling it on interrupt.
+func waitForAnswer(conversation *chat.Conversation, interrupts <-chan os.Signal) {
+	done := make(chan struct{})
+
+	go func() {
 		conversation.WaitMyTurn()
-		c.ui.PrintMessage("👤: ", ui.MessageTypeInfo)
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-interrupts:
+			conversation.Cancel()
+		}
+	}
+}
 
-		userMessage := c.ui.ReadUserInput()
+// readUserInput reads the next message, or reports that the user pressed Ctrl-C
+// or otherwise ended the input instead.
+func (c *InteractiveCmd) readUserInput(interrupts <-chan os.Signal) (string, bool) {
+	type line struct {
+		text string
+		err  error
+	}
 
-		if userMessage == "/exit" {
-			break
+	input := make(chan line, 1)
+
+	go func() {
+		text, err := c.input.ReadLine()
+		input <- line{text: text, err: err}
+	}()
+
+	select {
+	case message := <-input:
+		return message.text, message.err != nil
+	case <-interrupts:
+		return "", true
+	}
+}
+
+func (c *InteractiveCmd) newSession() (*sessions.Session, error) {
+	session, err := sessions.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+
+	session.TemplateName = c.chatOptions.TemplateName
+	session.TemplateVariables = c.chatOptions.TemplateVariables
+	session.IncludePatterns = c.chatOptions.IncludePatterns
+	session.ExcludePatterns = c.chatOptions.ExcludePatterns
+	session.Paths = c.chatOptions.Paths
+	session.Parameters = c.chatOptions.Parameters
+
+	if c.contextFiles != nil {
+		for _, file := range c.contextFiles.Files() {
+			session.Files = append(session.Files, file.Path)
 		}
+	}
+
+	return session, nil
+}
 
-		conversation.Reply(userMessage)
+func (c *InteractiveCmd) saveSession(conversation *chat.Conversation) {
+	if c.sessionStore == nil || c.session == nil {
+		return
+	}
+
+	c.session.Messages = toSessionMessages(conversation.Messages())
+
+	err := c.sessionStore.Save(c.session)
+	if err != nil {
+		c.ui.PrintMessage(fmt.Sprintf("warning: could not save session: %s\n", err), ui.MessageTypeWarning)
 	}
 }
 
@@ -98,6 +469,17 @@ func (c *InteractiveCmd) printMessageChunk(chunk *chat.ConversationChunk) {
 		return
 	}
 
+	if c.renderer != nil && !chunk.IsErrorChunk {
+		c.renderer.Write(chunk.Content)
+
+		if chunk.IsFinalChunk {
+			c.renderer.Flush()
+			c.ui.PrintMessage("\n", ui.MessageTypeInfo)
+		}
+
+		return
+	}
+
 	if chunk.IsErrorChunk {
 		c.ui.PrintMessage(chunk.Content, ui.MessageTypeError)
 	}
@@ -108,3 +490,38 @@ func (c *InteractiveCmd) printMessageChunk(chunk *chat.ConversationChunk) {
 
 	c.ui.PrintMessage(chunk.Content, ui.MessageTypeInfo)
 }
+
+func printHistoryMessage(u ui.UI, message sessions.Message) {
+	switch message.Role {
+	case openai.ChatMessageRoleUser:
+		u.PrintMessage(fmt.Sprintf("👤: %s\n", message.Content), ui.MessageTypeInfo)
+	case openai.ChatMessageRoleAssistant:
+		u.PrintMessage(fmt.Sprintf("🤖: %s\n", message.Content), ui.MessageTypeInfo)
+	}
+}
+
+func toSessionMessages(messages []openai.ChatCompletionMessage) []sessions.Message {
+	sessionMessages := make([]sessions.Message, 0, len(messages))
+
+	for _, message := range messages {
+		sessionMessages = append(sessionMessages, sessions.Message{
+			Role:    message.Role,
+			Content: message.Content,
+		})
+	}
+
+	return sessionMessages
+}
+
+func toChatMessages(messages []sessions.Message) []openai.ChatCompletionMessage {
+	chatMessages := make([]openai.ChatCompletionMessage, 0, len(messages))
+
+	for _, message := range messages {
+		chatMessages = append(chatMessages, openai.ChatCompletionMessage{
+			Role:    message.Role,
+			Content: message.Content,
+		})
+	}
+
+	return chatMessages
+}
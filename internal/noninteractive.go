@@ -2,10 +2,12 @@ package internal
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/intility/cwc/pkg/chat"
 	"github.com/intility/cwc/pkg/config"
 	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
 	"github.com/intility/cwc/pkg/prompting"
 	"github.com/intility/cwc/pkg/systemcontext"
 	"github.com/intility/cwc/pkg/ui"
@@ -13,28 +15,96 @@ import (
 
 type NonInteractiveCmd struct {
 	ui             ui.UI
+	noticeUI       ui.UI
 	clientProvider config.ClientProvider
 	promptResolver prompting.PromptResolver
 	smGenerator    systemcontext.SystemMessageGenerator
+	chatOptions    InteractiveChatOptions
+	// renders the answer as markdown, nil when stdout is not a terminal
+	renderer *ui.MarkdownRenderer
+	// the files the changes proposed in the answer are applied to with --apply
+	applyFiles ContextFilesRetriever
+	// the files included in the context, reported with the json and ndjson output formats
+	includedFiles ContextFilesProvider
+	// the context rendered into the system message, counted in the token estimate
+	contextRecord ContextRecorder
+	// prints the answer for scripts, nil with the text output format
+	output *structuredOutput
+}
+
+// NonInteractiveOpt configures optional behaviour of the NonInteractiveCmd.
+type NonInteractiveOpt func(*NonInteractiveCmd)
+
+// WithIncludedFiles sets the provider of the files in the context, which are listed in the structured output.
+func WithIncludedFiles(provider ContextFilesProvider) NonInteractiveOpt {
+	return func(c *NonInteractiveCmd) {
+		c.includedFiles = provider
+	}
+}
+
+// WithRecordedContext sets the recorder of the context, whose size is reported in the token estimate.
+func WithRecordedContext(recorder ContextRecorder) NonInteractiveOpt {
+	return func(c *NonInteractiveCmd) {
+		c.contextRecord = recorder
+	}
+}
+
+// WithApplyFiles sets the retriever gathering the files that --apply changes.
+func WithApplyFiles(retriever ContextFilesRetriever) NonInteractiveOpt {
+	return func(c *NonInteractiveCmd) {
+		c.applyFiles = retriever
+	}
 }
 
 func NewNonInteractiveCmd(
 	clientProvider config.ClientProvider,
 	promptResolver prompting.PromptResolver,
 	smGenerator systemcontext.SystemMessageGenerator,
+	chatOptions InteractiveChatOptions,
+	opts ...NonInteractiveOpt,
 ) *NonInteractiveCmd {
-	return &NonInteractiveCmd{
+	cmd := &NonInteractiveCmd{
 		ui:             ui.NewUI(),
+		noticeUI:       ui.NewUI(ui.WithWriter(os.Stderr)),
 		clientProvider: clientProvider,
 		promptResolver: promptResolver,
 		smGenerator:    smGenerator,
+		chatOptions:    chatOptions,
+		renderer:       ui.NewTerminalMarkdownRenderer(os.Stdout),
+		applyFiles:     nil,
+		includedFiles:  nil,
+		contextRecord:  nil,
+		output:         nil,
 	}
+
+	if chatOptions.Output == OutputJSON || chatOptions.Output == OutputNDJSON {
+		cmd.output = newStructuredOutput(os.Stdout, chatOptions.Output,
+			chatOptions.Parameters.Model, chatOptions.TemplateName)
+	}
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	return cmd
 }
 
+// Run asks the question and prints the answer. With a structured output format, failures
+// are reported in the output too, and the returned error only determines the exit status.
 func (c *NonInteractiveCmd) Run() error {
-	openaiClient, err := c.clientProvider.NewClientFromConfig()
+	err := c.run()
+	if err != nil && c.output != nil {
+		c.output.fail(err)
+		return errors.SuppressedError{Err: err}
+	}
+
+	return err
+}
+
+func (c *NonInteractiveCmd) run() error {
+	backend, err := c.clientProvider.NewClientFromConfig()
 	if err != nil {
-		return fmt.Errorf("error creating openaiClient: %w", err)
+		return fmt.Errorf("error creating chat backend: %w", err)
 	}
 
 	generateSystemMessage, err := c.smGenerator.GenerateSystemMessage()
@@ -48,14 +118,128 @@ func (c *NonInteractiveCmd) Run() error {
 		return errors.NoPromptProvidedError{Message: "non-interactive mode requires a prompt"}
 	}
 
-	chatInstance := chat.NewChat(openaiClient, generateSystemMessage, c.printChunk)
+	err = c.checkTokenBudget(generateSystemMessage, userPrompt)
+	if err != nil {
+		return err
+	}
+
+	chunkHandler, noticeHandler := c.printChunk, c.printNotice
+
+	if c.output != nil {
+		chunkHandler, noticeHandler = c.output.handleChunk, c.output.handleNotice
+
+		if c.includedFiles != nil {
+			c.output.setFiles(filePaths(c.includedFiles.Files()))
+		}
+	}
+
+	chatInstance := chat.NewChat(backend, generateSystemMessage, chunkHandler,
+		chat.WithParameters(c.chatOptions.Parameters),
+		chat.WithNoticeHandler(noticeHandler))
 	conversation := chatInstance.BeginConversation(userPrompt)
 
 	conversation.WaitMyTurn()
 
+	err = conversation.Err()
+	if err != nil {
+		return errors.AnswerFailedError{Err: err}
+	}
+
+	if c.output != nil {
+		c.output.finish(conversation.Messages())
+	}
+
+	if c.chatOptions.Apply {
+		messages := conversation.Messages()
+
+		return c.applyChanges(messages[len(messages)-1].Content)
+	}
+
 	return nil
 }
 
+// checkTokenBudget reports the estimated size of the request on stderr, which keeps the answer on
+// stdout clean. There is nobody to ask for confirmation, so a request that exceeds the limit is
+// refused unless the budget is ignored.
+func (c *NonInteractiveCmd) checkTokenBudget(systemMessage, prompt string) error {
+	var ctx string
+	if c.contextRecord != nil {
+		ctx = c.contextRecord.LastContext()
+	}
+
+	estimate, err := estimateTokens(c.chatOptions.Parameters, c.chatOptions.TokenBudget, systemMessage, ctx, nil, prompt)
+	if err != nil {
+		c.noticeUI.PrintMessage(fmt.Sprintf("warning: could not estimate tokens: %s\n", err), ui.MessageTypeWarning)
+		return nil
+	}
+
+	estimate.print(c.noticeUI)
+
+	if estimate.exceedsLimit() && !c.chatOptions.IgnoreBudget {
+		return fmt.Errorf("%w, send it anyway with --ignore-budget", estimate.err())
+	}
+
+	return nil
+}
+
+// applyChanges reviews the changes proposed in the answer. Stdin holds the piped
+// context, so the questions are asked on the terminal and printed to stderr.
+func (c *NonInteractiveCmd) applyChanges(answer string) error {
+	if c.applyFiles == nil {
+		return errNoApplyFiles
+	}
+
+	_, err := c.applyFiles.RetrieveContext()
+	if err != nil {
+		return fmt.Errorf("error gathering files: %w", err)
+	}
+
+	terminal, err := ui.OpenTerminal()
+	if err != nil {
+		return fmt.Errorf("error applying changes, a terminal is needed to review them: %w", err)
+	}
+
+	defer terminal.Close()
+
+	reviewUI := ui.NewUI(ui.WithReader(terminal), ui.WithWriter(os.Stderr))
+
+	_, err = newChangeReviewer(reviewUI, c.applyFiles.Files()).review(answer)
+	if err != nil {
+		return fmt.Errorf("error applying changes: %w", err)
+	}
+
+	return nil
+}
+
+// printNotice writes notices such as retries to stderr to keep the answer on stdout clean.
+func (c *NonInteractiveCmd) printNotice(message string) {
+	c.noticeUI.PrintMessage(message+"\n", ui.MessageTypeNotice)
+}
+
 func (c *NonInteractiveCmd) printChunk(chunk *chat.ConversationChunk) {
+	// the error is returned from Run instead
+	if chunk.IsErrorChunk {
+		return
+	}
+
+	if c.renderer != nil {
+		c.renderer.Write(chunk.Content)
+
+		if chunk.IsFinalChunk {
+			c.renderer.Flush()
+		}
+
+		return
+	}
+
 	c.ui.PrintMessage(chunk.Content, ui.MessageTypeInfo)
 }
+
+func filePaths(files []filetree.File) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	return paths
+}
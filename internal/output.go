@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/tokens"
+)
+
+// Output formats of the non-interactive mode.
+const (
+	OutputText   = "text"
+	OutputJSON   = "json"
+	OutputNDJSON = "ndjson"
+)
+
+// Types of the events streamed with the ndjson output format.
+const (
+	eventStart  = "start"
+	eventChunk  = "chunk"
+	eventNotice = "notice"
+	eventEnd    = "end"
+	eventError  = "error"
+)
+
+// OutputFormats returns the supported output formats.
+func OutputFormats() []string {
+	return []string{OutputText, OutputJSON, OutputNDJSON}
+}
+
+// answerReport is the result of a non-interactive run, printed as a single object with
+// the json output format. With ndjson, its fields are spread over the start and end events.
+type answerReport struct {
+	Answer       string      `json:"answer"`
+	Model        string      `json:"model"`
+	Usage        *tokenUsage `json:"usage,omitempty"`
+	FinishReason string      `json:"finish_reason"`
+	Files        []string    `json:"files"`
+	Template     string      `json:"template"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// tokenUsage is counted locally, since the API does not report the usage of streamed answers.
+type tokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// outputEvent is a line of the ndjson output format.
+type outputEvent struct {
+	Type         string      `json:"type"`
+	Content      string      `json:"content,omitempty"`
+	Model        string      `json:"model,omitempty"`
+	Template     string      `json:"template,omitempty"`
+	Files        []string    `json:"files,omitempty"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+	Usage        *tokenUsage `json:"usage,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+func newOutputEvent(eventType string) outputEvent {
+	return outputEvent{
+		Type:         eventType,
+		Content:      "",
+		Model:        "",
+		Template:     "",
+		Files:        nil,
+		FinishReason: "",
+		Usage:        nil,
+		Error:        "",
+	}
+}
+
+// structuredOutput prints the answer as json or ndjson for scripts. Either format ends with the error
+// when the run fails, so that the output of a failed run can be parsed like that of a successful one.
+type structuredOutput struct {
+	format  string
+	encoder *json.Encoder
+	report  answerReport
+}
+
+func newStructuredOutput(writer io.Writer, format string, model string, templateName string) *structuredOutput {
+	return &structuredOutput{
+		format:  format,
+		encoder: json.NewEncoder(writer),
+		report: answerReport{
+			Answer:       "",
+			Model:        model,
+			Usage:        nil,
+			FinishReason: "",
+			Files:        []string{},
+			Template:     templateName,
+			Error:        "",
+		},
+	}
+}
+
+// setFiles records the files included in the context.
+func (o *structuredOutput) setFiles(files []string) {
+	if files != nil {
+		o.report.Files = files
+	}
+}
+
+// handleChunk streams the chunks of the answer with ndjson and records the model
+// and the finish reason of the complete answer.
+func (o *structuredOutput) handleChunk(chunk *chat.ConversationChunk) {
+	// the error is printed once the run fails
+	if chunk.IsErrorChunk {
+		return
+	}
+
+	if chunk.Model != "" {
+		o.report.Model = chunk.Model
+	}
+
+	if chunk.FinishReason != "" {
+		o.report.FinishReason = chunk.FinishReason
+	}
+
+	if o.format != OutputNDJSON {
+		return
+	}
+
+	switch {
+	case chunk.IsInitialChunk:
+		// a retry starts the answer again
+		event := newOutputEvent(eventStart)
+		event.Model = o.report.Model
+		event.Template = o.report.Template
+		event.Files = o.report.Files
+
+		o.emit(event)
+	case chunk.Content != "":
+		event := newOutputEvent(eventChunk)
+		event.Content = chunk.Content
+
+		o.emit(event)
+	}
+}
+
+func (o *structuredOutput) handleNotice(message string) {
+	if o.format == OutputNDJSON {
+		event := newOutputEvent(eventNotice)
+		event.Content = message
+
+		o.emit(event)
+	}
+}
+
+// finish prints the answer with json, or the end event with ndjson.
+func (o *structuredOutput) finish(messages []openai.ChatCompletionMessage) {
+	if len(messages) > 0 {
+		o.report.Answer = messages[len(messages)-1].Content
+		o.report.Usage = countUsage(o.report.Model, messages)
+	}
+
+	if o.format == OutputJSON {
+		o.emit(o.report)
+		return
+	}
+
+	event := newOutputEvent(eventEnd)
+	event.Model = o.report.Model
+	event.FinishReason = o.report.FinishReason
+	event.Usage = o.report.Usage
+
+	o.emit(event)
+}
+
+// fail prints the error of a failed run.
+func (o *structuredOutput) fail(err error) {
+	if o.format == OutputJSON {
+		o.report.Error = err.Error()
+		o.emit(o.report)
+
+		return
+	}
+
+	event := newOutputEvent(eventError)
+	event.Error = err.Error()
+
+	o.emit(event)
+}
+
+func (o *structuredOutput) emit(value any) {
+	// there is nowhere left to report a failing stdout
+	_ = o.encoder.Encode(value)
+}
+
+// countUsage counts the tokens of the request, all messages but the last, and of the answer.
+func countUsage(model string, messages []openai.ChatCompletionMessage) *tokenUsage {
+	counter, err := tokens.NewCounter(model)
+	if err != nil {
+		return nil
+	}
+
+	prompt := tokens.CountMessages(counter, messages[:len(messages)-1])
+	completion := counter.Count(messages[len(messages)-1].Content)
+
+	return &tokenUsage{
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		TotalTokens:      prompt + completion,
+	}
+}
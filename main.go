@@ -24,6 +24,6 @@ func main() {
 			ui.PrintMessage(fmt.Sprintf("Error: %s\n", err), cwcui.MessageTypeError)
 		}
 
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
@@ -0,0 +1,99 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	chat "github.com/intility/cwc/pkg/chat"
+
+	mock "github.com/stretchr/testify/mock"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Backend is an autogenerated mock type for the Backend type
+type Backend struct {
+	mock.Mock
+}
+
+type Backend_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Backend) EXPECT() *Backend_Expecter {
+	return &Backend_Expecter{mock: &_m.Mock}
+}
+
+// CreateChatCompletionStream provides a mock function with given fields: ctx, req
+func (_m *Backend) CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (chat.Stream, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateChatCompletionStream")
+	}
+
+	var r0 chat.Stream
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, openai.ChatCompletionRequest) (chat.Stream, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, openai.ChatCompletionRequest) chat.Stream); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(chat.Stream)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, openai.ChatCompletionRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_CreateChatCompletionStream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateChatCompletionStream'
+type Backend_CreateChatCompletionStream_Call struct {
+	*mock.Call
+}
+
+// CreateChatCompletionStream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req openai.ChatCompletionRequest
+func (_e *Backend_Expecter) CreateChatCompletionStream(ctx interface{}, req interface{}) *Backend_CreateChatCompletionStream_Call {
+	return &Backend_CreateChatCompletionStream_Call{Call: _e.mock.On("CreateChatCompletionStream", ctx, req)}
+}
+
+func (_c *Backend_CreateChatCompletionStream_Call) Run(run func(ctx context.Context, req openai.ChatCompletionRequest)) *Backend_CreateChatCompletionStream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(openai.ChatCompletionRequest))
+	})
+	return _c
+}
+
+func (_c *Backend_CreateChatCompletionStream_Call) Return(_a0 chat.Stream, _a1 error) *Backend_CreateChatCompletionStream_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_CreateChatCompletionStream_Call) RunAndReturn(run func(context.Context, openai.ChatCompletionRequest) (chat.Stream, error)) *Backend_CreateChatCompletionStream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBackend creates a new instance of Backend. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackend(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Backend {
+	mock := &Backend{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
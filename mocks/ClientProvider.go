@@ -3,7 +3,8 @@
 package mocks
 
 import (
-	openai "github.com/sashabaranov/go-openai"
+	chat "github.com/intility/cwc/pkg/chat"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -21,23 +22,23 @@ func (_m *ClientProvider) EXPECT() *ClientProvider_Expecter {
 }
 
 // NewClientFromConfig provides a mock function with given fields:
-func (_m *ClientProvider) NewClientFromConfig() (*openai.Client, error) {
+func (_m *ClientProvider) NewClientFromConfig() (chat.Backend, error) {
 	ret := _m.Called()
 
 	if len(ret) == 0 {
 		panic("no return value specified for NewClientFromConfig")
 	}
 
-	var r0 *openai.Client
+	var r0 chat.Backend
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (*openai.Client, error)); ok {
+	if rf, ok := ret.Get(0).(func() (chat.Backend, error)); ok {
 		return rf()
 	}
-	if rf, ok := ret.Get(0).(func() *openai.Client); ok {
+	if rf, ok := ret.Get(0).(func() chat.Backend); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*openai.Client)
+			r0 = ret.Get(0).(chat.Backend)
 		}
 	}
 
@@ -67,12 +68,12 @@ func (_c *ClientProvider_NewClientFromConfig_Call) Run(run func()) *ClientProvid
 	return _c
 }
 
-func (_c *ClientProvider_NewClientFromConfig_Call) Return(_a0 *openai.Client, _a1 error) *ClientProvider_NewClientFromConfig_Call {
+func (_c *ClientProvider_NewClientFromConfig_Call) Return(_a0 chat.Backend, _a1 error) *ClientProvider_NewClientFromConfig_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *ClientProvider_NewClientFromConfig_Call) RunAndReturn(run func() (*openai.Client, error)) *ClientProvider_NewClientFromConfig_Call {
+func (_c *ClientProvider_NewClientFromConfig_Call) RunAndReturn(run func() (chat.Backend, error)) *ClientProvider_NewClientFromConfig_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,149 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	chat "github.com/intility/cwc/pkg/chat"
+
+	mock "github.com/stretchr/testify/mock"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	tokens "github.com/intility/cwc/pkg/tokens"
+)
+
+// Compactor is an autogenerated mock type for the Compactor type
+type Compactor struct {
+	mock.Mock
+}
+
+type Compactor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Compactor) EXPECT() *Compactor_Expecter {
+	return &Compactor_Expecter{mock: &_m.Mock}
+}
+
+// Compact provides a mock function with given fields: ctx, messages, limit, counter, params
+func (_m *Compactor) Compact(ctx context.Context, messages []openai.ChatCompletionMessage, limit int, counter tokens.Counter, params chat.Parameters) ([]openai.ChatCompletionMessage, error) {
+	ret := _m.Called(ctx, messages, limit, counter, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Compact")
+	}
+
+	var r0 []openai.ChatCompletionMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []openai.ChatCompletionMessage, int, tokens.Counter, chat.Parameters) ([]openai.ChatCompletionMessage, error)); ok {
+		return rf(ctx, messages, limit, counter, params)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []openai.ChatCompletionMessage, int, tokens.Counter, chat.Parameters) []openai.ChatCompletionMessage); ok {
+		r0 = rf(ctx, messages, limit, counter, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]openai.ChatCompletionMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []openai.ChatCompletionMessage, int, tokens.Counter, chat.Parameters) error); ok {
+		r1 = rf(ctx, messages, limit, counter, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Compactor_Compact_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Compact'
+type Compactor_Compact_Call struct {
+	*mock.Call
+}
+
+// Compact is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []openai.ChatCompletionMessage
+//   - limit int
+//   - counter tokens.Counter
+//   - params chat.Parameters
+func (_e *Compactor_Expecter) Compact(ctx interface{}, messages interface{}, limit interface{}, counter interface{}, params interface{}) *Compactor_Compact_Call {
+	return &Compactor_Compact_Call{Call: _e.mock.On("Compact", ctx, messages, limit, counter, params)}
+}
+
+func (_c *Compactor_Compact_Call) Run(run func(ctx context.Context, messages []openai.ChatCompletionMessage, limit int, counter tokens.Counter, params chat.Parameters)) *Compactor_Compact_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]openai.ChatCompletionMessage), args[2].(int), args[3].(tokens.Counter), args[4].(chat.Parameters))
+	})
+	return _c
+}
+
+func (_c *Compactor_Compact_Call) Return(_a0 []openai.ChatCompletionMessage, _a1 error) *Compactor_Compact_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Compactor_Compact_Call) RunAndReturn(run func(context.Context, []openai.ChatCompletionMessage, int, tokens.Counter, chat.Parameters) ([]openai.ChatCompletionMessage, error)) *Compactor_Compact_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function with given fields:
+func (_m *Compactor) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Compactor_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type Compactor_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *Compactor_Expecter) Name() *Compactor_Name_Call {
+	return &Compactor_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *Compactor_Name_Call) Run(run func()) *Compactor_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Compactor_Name_Call) Return(_a0 string) *Compactor_Name_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Compactor_Name_Call) RunAndReturn(run func() string) *Compactor_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCompactor creates a new instance of Compactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCompactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Compactor {
+	mock := &Compactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// ContextEditor is an autogenerated mock type for the ContextEditor type
+type ContextEditor struct {
+	mock.Mock
+}
+
+type ContextEditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ContextEditor) EXPECT() *ContextEditor_Expecter {
+	return &ContextEditor_Expecter{mock: &_m.Mock}
+}
+
+// AddInclude provides a mock function with given fields: pathOrPattern
+func (_m *ContextEditor) AddInclude(pathOrPattern string) error {
+	ret := _m.Called(pathOrPattern)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddInclude")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(pathOrPattern)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ContextEditor_AddInclude_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddInclude'
+type ContextEditor_AddInclude_Call struct {
+	*mock.Call
+}
+
+// AddInclude is a helper method to define mock.On call
+//   - pathOrPattern string
+func (_e *ContextEditor_Expecter) AddInclude(pathOrPattern interface{}) *ContextEditor_AddInclude_Call {
+	return &ContextEditor_AddInclude_Call{Call: _e.mock.On("AddInclude", pathOrPattern)}
+}
+
+func (_c *ContextEditor_AddInclude_Call) Run(run func(pathOrPattern string)) *ContextEditor_AddInclude_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextEditor_AddInclude_Call) Return(_a0 error) *ContextEditor_AddInclude_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextEditor_AddInclude_Call) RunAndReturn(run func(string) error) *ContextEditor_AddInclude_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Drop provides a mock function with given fields: path
+func (_m *ContextEditor) Drop(path string) error {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Drop")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ContextEditor_Drop_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Drop'
+type ContextEditor_Drop_Call struct {
+	*mock.Call
+}
+
+// Drop is a helper method to define mock.On call
+//   - path string
+func (_e *ContextEditor_Expecter) Drop(path interface{}) *ContextEditor_Drop_Call {
+	return &ContextEditor_Drop_Call{Call: _e.mock.On("Drop", path)}
+}
+
+func (_c *ContextEditor_Drop_Call) Run(run func(path string)) *ContextEditor_Drop_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ContextEditor_Drop_Call) Return(_a0 error) *ContextEditor_Drop_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextEditor_Drop_Call) RunAndReturn(run func(string) error) *ContextEditor_Drop_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewContextEditor creates a new instance of ContextEditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContextEditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContextEditor {
+	mock := &ContextEditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	filetree "github.com/intility/cwc/pkg/filetree"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContextFilesProvider is an autogenerated mock type for the ContextFilesProvider type
+type ContextFilesProvider struct {
+	mock.Mock
+}
+
+type ContextFilesProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ContextFilesProvider) EXPECT() *ContextFilesProvider_Expecter {
+	return &ContextFilesProvider_Expecter{mock: &_m.Mock}
+}
+
+// Files provides a mock function with given fields:
+func (_m *ContextFilesProvider) Files() []filetree.File {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Files")
+	}
+
+	var r0 []filetree.File
+	if rf, ok := ret.Get(0).(func() []filetree.File); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]filetree.File)
+		}
+	}
+
+	return r0
+}
+
+// ContextFilesProvider_Files_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Files'
+type ContextFilesProvider_Files_Call struct {
+	*mock.Call
+}
+
+// Files is a helper method to define mock.On call
+func (_e *ContextFilesProvider_Expecter) Files() *ContextFilesProvider_Files_Call {
+	return &ContextFilesProvider_Files_Call{Call: _e.mock.On("Files")}
+}
+
+func (_c *ContextFilesProvider_Files_Call) Run(run func()) *ContextFilesProvider_Files_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextFilesProvider_Files_Call) Return(_a0 []filetree.File) *ContextFilesProvider_Files_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextFilesProvider_Files_Call) RunAndReturn(run func() []filetree.File) *ContextFilesProvider_Files_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewContextFilesProvider creates a new instance of ContextFilesProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContextFilesProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContextFilesProvider {
+	mock := &ContextFilesProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,138 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	filetree "github.com/intility/cwc/pkg/filetree"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContextFilesRetriever is an autogenerated mock type for the ContextFilesRetriever type
+type ContextFilesRetriever struct {
+	mock.Mock
+}
+
+type ContextFilesRetriever_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ContextFilesRetriever) EXPECT() *ContextFilesRetriever_Expecter {
+	return &ContextFilesRetriever_Expecter{mock: &_m.Mock}
+}
+
+// Files provides a mock function with given fields:
+func (_m *ContextFilesRetriever) Files() []filetree.File {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Files")
+	}
+
+	var r0 []filetree.File
+	if rf, ok := ret.Get(0).(func() []filetree.File); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]filetree.File)
+		}
+	}
+
+	return r0
+}
+
+// ContextFilesRetriever_Files_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Files'
+type ContextFilesRetriever_Files_Call struct {
+	*mock.Call
+}
+
+// Files is a helper method to define mock.On call
+func (_e *ContextFilesRetriever_Expecter) Files() *ContextFilesRetriever_Files_Call {
+	return &ContextFilesRetriever_Files_Call{Call: _e.mock.On("Files")}
+}
+
+func (_c *ContextFilesRetriever_Files_Call) Run(run func()) *ContextFilesRetriever_Files_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextFilesRetriever_Files_Call) Return(_a0 []filetree.File) *ContextFilesRetriever_Files_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextFilesRetriever_Files_Call) RunAndReturn(run func() []filetree.File) *ContextFilesRetriever_Files_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetrieveContext provides a mock function with given fields:
+func (_m *ContextFilesRetriever) RetrieveContext() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetrieveContext")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ContextFilesRetriever_RetrieveContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetrieveContext'
+type ContextFilesRetriever_RetrieveContext_Call struct {
+	*mock.Call
+}
+
+// RetrieveContext is a helper method to define mock.On call
+func (_e *ContextFilesRetriever_Expecter) RetrieveContext() *ContextFilesRetriever_RetrieveContext_Call {
+	return &ContextFilesRetriever_RetrieveContext_Call{Call: _e.mock.On("RetrieveContext")}
+}
+
+func (_c *ContextFilesRetriever_RetrieveContext_Call) Run(run func()) *ContextFilesRetriever_RetrieveContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextFilesRetriever_RetrieveContext_Call) Return(_a0 string, _a1 error) *ContextFilesRetriever_RetrieveContext_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ContextFilesRetriever_RetrieveContext_Call) RunAndReturn(run func() (string, error)) *ContextFilesRetriever_RetrieveContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewContextFilesRetriever creates a new instance of ContextFilesRetriever. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContextFilesRetriever(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContextFilesRetriever {
+	mock := &ContextFilesRetriever{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
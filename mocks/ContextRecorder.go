@@ -0,0 +1,77 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// ContextRecorder is an autogenerated mock type for the ContextRecorder type
+type ContextRecorder struct {
+	mock.Mock
+}
+
+type ContextRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ContextRecorder) EXPECT() *ContextRecorder_Expecter {
+	return &ContextRecorder_Expecter{mock: &_m.Mock}
+}
+
+// LastContext provides a mock function with given fields:
+func (_m *ContextRecorder) LastContext() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastContext")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ContextRecorder_LastContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LastContext'
+type ContextRecorder_LastContext_Call struct {
+	*mock.Call
+}
+
+// LastContext is a helper method to define mock.On call
+func (_e *ContextRecorder_Expecter) LastContext() *ContextRecorder_LastContext_Call {
+	return &ContextRecorder_LastContext_Call{Call: _e.mock.On("LastContext")}
+}
+
+func (_c *ContextRecorder_LastContext_Call) Run(run func()) *ContextRecorder_LastContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ContextRecorder_LastContext_Call) Return(_a0 string) *ContextRecorder_LastContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ContextRecorder_LastContext_Call) RunAndReturn(run func() string) *ContextRecorder_LastContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewContextRecorder creates a new instance of ContextRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContextRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContextRecorder {
+	mock := &ContextRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
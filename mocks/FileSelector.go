@@ -0,0 +1,89 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// FileSelector is an autogenerated mock type for the FileSelector type
+type FileSelector struct {
+	mock.Mock
+}
+
+type FileSelector_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *FileSelector) EXPECT() *FileSelector_Expecter {
+	return &FileSelector_Expecter{mock: &_m.Mock}
+}
+
+// SelectFiles provides a mock function with given fields:
+func (_m *FileSelector) SelectFiles() ([]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectFiles")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FileSelector_SelectFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectFiles'
+type FileSelector_SelectFiles_Call struct {
+	*mock.Call
+}
+
+// SelectFiles is a helper method to define mock.On call
+func (_e *FileSelector_Expecter) SelectFiles() *FileSelector_SelectFiles_Call {
+	return &FileSelector_SelectFiles_Call{Call: _e.mock.On("SelectFiles")}
+}
+
+func (_c *FileSelector_SelectFiles_Call) Run(run func()) *FileSelector_SelectFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *FileSelector_SelectFiles_Call) Return(_a0 []string, _a1 error) *FileSelector_SelectFiles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FileSelector_SelectFiles_Call) RunAndReturn(run func() ([]string, error)) *FileSelector_SelectFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewFileSelector creates a new instance of FileSelector. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFileSelector(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FileSelector {
+	mock := &FileSelector{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
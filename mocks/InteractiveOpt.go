@@ -0,0 +1,68 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	internal "github.com/intility/cwc/internal"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InteractiveOpt is an autogenerated mock type for the InteractiveOpt type
+type InteractiveOpt struct {
+	mock.Mock
+}
+
+type InteractiveOpt_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *InteractiveOpt) EXPECT() *InteractiveOpt_Expecter {
+	return &InteractiveOpt_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *InteractiveOpt) Execute(_a0 *internal.InteractiveCmd) {
+	_m.Called(_a0)
+}
+
+// InteractiveOpt_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type InteractiveOpt_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *internal.InteractiveCmd
+func (_e *InteractiveOpt_Expecter) Execute(_a0 interface{}) *InteractiveOpt_Execute_Call {
+	return &InteractiveOpt_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *InteractiveOpt_Execute_Call) Run(run func(_a0 *internal.InteractiveCmd)) *InteractiveOpt_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*internal.InteractiveCmd))
+	})
+	return _c
+}
+
+func (_c *InteractiveOpt_Execute_Call) Return() *InteractiveOpt_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *InteractiveOpt_Execute_Call) RunAndReturn(run func(*internal.InteractiveCmd)) *InteractiveOpt_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewInteractiveOpt creates a new instance of InteractiveOpt. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInteractiveOpt(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InteractiveOpt {
+	mock := &InteractiveOpt{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
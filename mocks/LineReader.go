@@ -0,0 +1,87 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// LineReader is an autogenerated mock type for the LineReader type
+type LineReader struct {
+	mock.Mock
+}
+
+type LineReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LineReader) EXPECT() *LineReader_Expecter {
+	return &LineReader_Expecter{mock: &_m.Mock}
+}
+
+// ReadLine provides a mock function with given fields:
+func (_m *LineReader) ReadLine() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadLine")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LineReader_ReadLine_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadLine'
+type LineReader_ReadLine_Call struct {
+	*mock.Call
+}
+
+// ReadLine is a helper method to define mock.On call
+func (_e *LineReader_Expecter) ReadLine() *LineReader_ReadLine_Call {
+	return &LineReader_ReadLine_Call{Call: _e.mock.On("ReadLine")}
+}
+
+func (_c *LineReader_ReadLine_Call) Run(run func()) *LineReader_ReadLine_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *LineReader_ReadLine_Call) Return(_a0 string, _a1 error) *LineReader_ReadLine_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LineReader_ReadLine_Call) RunAndReturn(run func() (string, error)) *LineReader_ReadLine_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLineReader creates a new instance of LineReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLineReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LineReader {
+	mock := &LineReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
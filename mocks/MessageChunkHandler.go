@@ -0,0 +1,68 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	chat "github.com/intility/cwc/pkg/chat"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessageChunkHandler is an autogenerated mock type for the MessageChunkHandler type
+type MessageChunkHandler struct {
+	mock.Mock
+}
+
+type MessageChunkHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MessageChunkHandler) EXPECT() *MessageChunkHandler_Expecter {
+	return &MessageChunkHandler_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: chunk
+func (_m *MessageChunkHandler) Execute(chunk *chat.ConversationChunk) {
+	_m.Called(chunk)
+}
+
+// MessageChunkHandler_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MessageChunkHandler_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - chunk *chat.ConversationChunk
+func (_e *MessageChunkHandler_Expecter) Execute(chunk interface{}) *MessageChunkHandler_Execute_Call {
+	return &MessageChunkHandler_Execute_Call{Call: _e.mock.On("Execute", chunk)}
+}
+
+func (_c *MessageChunkHandler_Execute_Call) Run(run func(chunk *chat.ConversationChunk)) *MessageChunkHandler_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*chat.ConversationChunk))
+	})
+	return _c
+}
+
+func (_c *MessageChunkHandler_Execute_Call) Return() *MessageChunkHandler_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MessageChunkHandler_Execute_Call) RunAndReturn(run func(*chat.ConversationChunk)) *MessageChunkHandler_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMessageChunkHandler creates a new instance of MessageChunkHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessageChunkHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessageChunkHandler {
+	mock := &MessageChunkHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	internal "github.com/intility/cwc/internal"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NonInteractiveOpt is an autogenerated mock type for the NonInteractiveOpt type
+type NonInteractiveOpt struct {
+	mock.Mock
+}
+
+type NonInteractiveOpt_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NonInteractiveOpt) EXPECT() *NonInteractiveOpt_Expecter {
+	return &NonInteractiveOpt_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *NonInteractiveOpt) Execute(_a0 *internal.NonInteractiveCmd) {
+	_m.Called(_a0)
+}
+
+// NonInteractiveOpt_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type NonInteractiveOpt_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *internal.NonInteractiveCmd
+func (_e *NonInteractiveOpt_Expecter) Execute(_a0 interface{}) *NonInteractiveOpt_Execute_Call {
+	return &NonInteractiveOpt_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *NonInteractiveOpt_Execute_Call) Run(run func(_a0 *internal.NonInteractiveCmd)) *NonInteractiveOpt_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*internal.NonInteractiveCmd))
+	})
+	return _c
+}
+
+func (_c *NonInteractiveOpt_Execute_Call) Return() *NonInteractiveOpt_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *NonInteractiveOpt_Execute_Call) RunAndReturn(run func(*internal.NonInteractiveCmd)) *NonInteractiveOpt_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewNonInteractiveOpt creates a new instance of NonInteractiveOpt. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNonInteractiveOpt(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NonInteractiveOpt {
+	mock := &NonInteractiveOpt{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
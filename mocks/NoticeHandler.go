@@ -0,0 +1,65 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// NoticeHandler is an autogenerated mock type for the NoticeHandler type
+type NoticeHandler struct {
+	mock.Mock
+}
+
+type NoticeHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *NoticeHandler) EXPECT() *NoticeHandler_Expecter {
+	return &NoticeHandler_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: message
+func (_m *NoticeHandler) Execute(message string) {
+	_m.Called(message)
+}
+
+// NoticeHandler_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type NoticeHandler_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - message string
+func (_e *NoticeHandler_Expecter) Execute(message interface{}) *NoticeHandler_Execute_Call {
+	return &NoticeHandler_Execute_Call{Call: _e.mock.On("Execute", message)}
+}
+
+func (_c *NoticeHandler_Execute_Call) Run(run func(message string)) *NoticeHandler_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *NoticeHandler_Execute_Call) Return() *NoticeHandler_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *NoticeHandler_Execute_Call) RunAndReturn(run func(string)) *NoticeHandler_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewNoticeHandler creates a new instance of NoticeHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNoticeHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NoticeHandler {
+	mock := &NoticeHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	chat "github.com/intility/cwc/pkg/chat"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ParameterResolver is an autogenerated mock type for the ParameterResolver type
+type ParameterResolver struct {
+	mock.Mock
+}
+
+type ParameterResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ParameterResolver) EXPECT() *ParameterResolver_Expecter {
+	return &ParameterResolver_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: templateName
+func (_m *ParameterResolver) Execute(templateName string) (chat.Parameters, error) {
+	ret := _m.Called(templateName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 chat.Parameters
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (chat.Parameters, error)); ok {
+		return rf(templateName)
+	}
+	if rf, ok := ret.Get(0).(func(string) chat.Parameters); ok {
+		r0 = rf(templateName)
+	} else {
+		r0 = ret.Get(0).(chat.Parameters)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(templateName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParameterResolver_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ParameterResolver_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - templateName string
+func (_e *ParameterResolver_Expecter) Execute(templateName interface{}) *ParameterResolver_Execute_Call {
+	return &ParameterResolver_Execute_Call{Call: _e.mock.On("Execute", templateName)}
+}
+
+func (_c *ParameterResolver_Execute_Call) Run(run func(templateName string)) *ParameterResolver_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ParameterResolver_Execute_Call) Return(_a0 chat.Parameters, _a1 error) *ParameterResolver_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ParameterResolver_Execute_Call) RunAndReturn(run func(string) (chat.Parameters, error)) *ParameterResolver_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewParameterResolver creates a new instance of ParameterResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewParameterResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ParameterResolver {
+	mock := &ParameterResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
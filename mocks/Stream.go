@@ -0,0 +1,122 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	openai "github.com/sashabaranov/go-openai"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Stream is an autogenerated mock type for the Stream type
+type Stream struct {
+	mock.Mock
+}
+
+type Stream_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Stream) EXPECT() *Stream_Expecter {
+	return &Stream_Expecter{mock: &_m.Mock}
+}
+
+// Close provides a mock function with given fields:
+func (_m *Stream) Close() {
+	_m.Called()
+}
+
+// Stream_Close_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Close'
+type Stream_Close_Call struct {
+	*mock.Call
+}
+
+// Close is a helper method to define mock.On call
+func (_e *Stream_Expecter) Close() *Stream_Close_Call {
+	return &Stream_Close_Call{Call: _e.mock.On("Close")}
+}
+
+func (_c *Stream_Close_Call) Run(run func()) *Stream_Close_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Stream_Close_Call) Return() *Stream_Close_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Stream_Close_Call) RunAndReturn(run func()) *Stream_Close_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Recv provides a mock function with given fields:
+func (_m *Stream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recv")
+	}
+
+	var r0 openai.ChatCompletionStreamResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (openai.ChatCompletionStreamResponse, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() openai.ChatCompletionStreamResponse); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(openai.ChatCompletionStreamResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stream_Recv_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Recv'
+type Stream_Recv_Call struct {
+	*mock.Call
+}
+
+// Recv is a helper method to define mock.On call
+func (_e *Stream_Expecter) Recv() *Stream_Recv_Call {
+	return &Stream_Recv_Call{Call: _e.mock.On("Recv")}
+}
+
+func (_c *Stream_Recv_Call) Run(run func()) *Stream_Recv_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Stream_Recv_Call) Return(_a0 openai.ChatCompletionStreamResponse, _a1 error) *Stream_Recv_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Stream_Recv_Call) RunAndReturn(run func() (openai.ChatCompletionStreamResponse, error)) *Stream_Recv_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStream creates a new instance of Stream. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStream(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Stream {
+	mock := &Stream{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// TemplateSwitcher is an autogenerated mock type for the TemplateSwitcher type
+type TemplateSwitcher struct {
+	mock.Mock
+}
+
+type TemplateSwitcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TemplateSwitcher) EXPECT() *TemplateSwitcher_Expecter {
+	return &TemplateSwitcher_Expecter{mock: &_m.Mock}
+}
+
+// SetTemplateName provides a mock function with given fields: templateName
+func (_m *TemplateSwitcher) SetTemplateName(templateName string) {
+	_m.Called(templateName)
+}
+
+// TemplateSwitcher_SetTemplateName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTemplateName'
+type TemplateSwitcher_SetTemplateName_Call struct {
+	*mock.Call
+}
+
+// SetTemplateName is a helper method to define mock.On call
+//   - templateName string
+func (_e *TemplateSwitcher_Expecter) SetTemplateName(templateName interface{}) *TemplateSwitcher_SetTemplateName_Call {
+	return &TemplateSwitcher_SetTemplateName_Call{Call: _e.mock.On("SetTemplateName", templateName)}
+}
+
+func (_c *TemplateSwitcher_SetTemplateName_Call) Run(run func(templateName string)) *TemplateSwitcher_SetTemplateName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *TemplateSwitcher_SetTemplateName_Call) Return() *TemplateSwitcher_SetTemplateName_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TemplateSwitcher_SetTemplateName_Call) RunAndReturn(run func(string)) *TemplateSwitcher_SetTemplateName_Call {
+	_c.Run(run)
+	return _c
+}
+
+// TemplateName provides a mock function with given fields:
+func (_m *TemplateSwitcher) TemplateName() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for TemplateName")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// TemplateSwitcher_TemplateName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TemplateName'
+type TemplateSwitcher_TemplateName_Call struct {
+	*mock.Call
+}
+
+// TemplateName is a helper method to define mock.On call
+func (_e *TemplateSwitcher_Expecter) TemplateName() *TemplateSwitcher_TemplateName_Call {
+	return &TemplateSwitcher_TemplateName_Call{Call: _e.mock.On("TemplateName")}
+}
+
+func (_c *TemplateSwitcher_TemplateName_Call) Run(run func()) *TemplateSwitcher_TemplateName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *TemplateSwitcher_TemplateName_Call) Return(_a0 string) *TemplateSwitcher_TemplateName_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplateSwitcher_TemplateName_Call) RunAndReturn(run func() string) *TemplateSwitcher_TemplateName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTemplateSwitcher creates a new instance of TemplateSwitcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTemplateSwitcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TemplateSwitcher {
+	mock := &TemplateSwitcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,220 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/intility/cwc/pkg/filetree"
+	cwcui "github.com/intility/cwc/pkg/ui"
+)
+
+const (
+	logDirPermissions  = 0o700
+	logFilePermissions = 0o600
+)
+
+// Entry records a request sent to the model. The contents of the files and the prompt are
+// not recorded, only their hashes, so that the log can be shared without leaking them.
+//
+// Files are the matched files, the files whose changes are in the git section and the context
+// files. Their hashes are of the bytes on disk, or in the revision they were read from, so that
+// they can be checked against the files themselves. A file sent with secrets redacted also has
+// the hash of the bytes that were sent.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Endpoint   string    `json:"endpoint"`
+	Deployment string    `json:"deployment"`
+	Model      string    `json:"model"`
+	Template   string    `json:"template"`
+	// Repository is the root of the git repository cwc ran in, or the working directory outside one
+	Repository string `json:"repository"`
+	Files      []File `json:"files"`
+	StdinBytes int    `json:"stdin_bytes"`
+	// PromptSHA256 is the hash of the last user message of the request
+	PromptSHA256 string `json:"prompt_sha256"`
+	// Messages is the number of messages in the request, the whole conversation so far
+	Messages int `json:"messages"`
+	// PromptTokens is the estimated size of the request and MaxTokens the tokens reserved for the answer
+	PromptTokens int `json:"prompt_tokens"`
+	MaxTokens    int `json:"max_tokens,omitempty"`
+}
+
+// File is a file in the context of a request, as it was read.
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+	// SentSHA256 is the hash of the bytes sent, when they differ from the file as secrets were redacted
+	SentSHA256 string `json:"sent_sha256,omitempty"`
+}
+
+// NewFiles returns the records of the files in the context, given the files as they were sent,
+// with the secrets redacted. The files whose changes are sent as a diff are not sent themselves.
+func NewFiles(files []filetree.File, sent []filetree.File) []File {
+	sentData := make(map[string][]byte, len(sent))
+	for _, file := range sent {
+		sentData[filepath.ToSlash(file.Path)] = file.Data
+	}
+
+	records := make([]File, 0, len(files))
+
+	for _, file := range files {
+		record := File{
+			Path:       filepath.ToSlash(file.Path),
+			SHA256:     Hash(file.Data),
+			Size:       len(file.Data),
+			SentSHA256: "",
+		}
+
+		if data, ok := sentData[record.Path]; ok && !bytes.Equal(data, file.Data) {
+			record.SentSHA256 = Hash(data)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// Hash returns the hex encoded SHA-256 of the data.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter selects entries of the log, the zero value selects all of them.
+type Filter struct {
+	// Since and Until bound the time of the entries, when set
+	Since time.Time
+	Until time.Time
+	// File is a path or a glob matched against the paths of the files in the context
+	File string
+	// Repository is the root of the repository or its name
+	Repository string
+}
+
+// Match reports whether the entry is selected by the filter.
+func (f Filter) Match(entry Entry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && !entry.Time.Before(f.Until) {
+		return false
+	}
+
+	if f.Repository != "" && f.Repository != entry.Repository && f.Repository != filepath.Base(entry.Repository) {
+		return false
+	}
+
+	if f.File == "" {
+		return true
+	}
+
+	pattern := filepath.ToSlash(filepath.Clean(f.File))
+
+	for _, file := range entry.Files {
+		matched, _ := path.Match(pattern, file.Path)
+		if matched || file.Path == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Log is an append only log of the requests, with an entry as json on each line.
+type Log struct {
+	// Path is the path of the log file
+	Path string
+}
+
+func NewLog(path string) *Log {
+	return &Log{Path: path}
+}
+
+// Append adds the entry at the end of the log.
+func (l *Log) Append(entry Entry) error {
+	err := os.MkdirAll(filepath.Dir(l.Path), logDirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating audit log directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error marshalling audit log entry: %w", err)
+	}
+
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFilePermissions)
+	if err != nil {
+		return fmt.Errorf("error opening audit log: %w", err)
+	}
+
+	// a single write keeps the line whole when several runs append at the same time
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns the entries selected by the filter, oldest first. Lines that can not be decoded, such as
+// a line cut short when cwc was killed while appending it, are skipped with a warning.
+func (l *Log) Entries(filter Filter) ([]Entry, error) {
+	file, err := os.Open(l.Path)
+	if stderrors.Is(err, fs.ErrNotExist) {
+		// nothing has been logged yet
+		return []Entry{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+
+	defer file.Close()
+
+	entries := []Entry{}
+	reader := bufio.NewReader(file)
+	ui := cwcui.NewUI(cwcui.WithWriter(os.Stderr)) //nolint:varnamelen
+
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !stderrors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error reading audit log: %w", err)
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry Entry
+
+			decodeErr := json.Unmarshal(line, &entry)
+			if decodeErr != nil {
+				ui.PrintMessage(fmt.Sprintf("skipping line %d of the audit log: %s\n", lineNumber, decodeErr),
+					cwcui.MessageTypeWarning)
+			} else if filter.Match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+
+		if err != nil {
+			return entries, nil
+		}
+	}
+}
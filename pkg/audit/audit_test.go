@@ -0,0 +1,193 @@
+package audit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/audit"
+	"github.com/intility/cwc/pkg/filetree"
+)
+
+func newEntry(at time.Time, repository string, paths ...string) audit.Entry {
+	files := make([]filetree.File, 0, len(paths))
+	for _, path := range paths {
+		files = append(files, filetree.File{Path: path, Data: []byte(path), Type: "go"})
+	}
+
+	return audit.Entry{
+		Time:         at,
+		Endpoint:     "https://example.openai.azure.com/",
+		Deployment:   "gpt-4",
+		Model:        "gpt-4",
+		Template:     "default",
+		Repository:   repository,
+		Files:        audit.NewFiles(files, files),
+		StdinBytes:   0,
+		PromptSHA256: audit.Hash([]byte("prompt")),
+		Messages:     2,
+		PromptTokens: 10,
+		MaxTokens:    0,
+	}
+}
+
+func TestLog_Entries(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	log := audit.NewLog(filepath.Join(t.TempDir(), "cwc", "audit.jsonl"))
+
+	entries := []audit.Entry{
+		newEntry(day, "/src/cwc", "main.go", "pkg/audit/audit.go"),
+		newEntry(day.Add(24*time.Hour), "/src/other", "README.md"),
+		newEntry(day.Add(48*time.Hour), "/src/cwc", "cmd/audit.go"),
+	}
+
+	for _, entry := range entries {
+		require.NoError(t, log.Append(entry))
+	}
+
+	tests := []struct {
+		name   string
+		filter audit.Filter
+		want   []audit.Entry
+	}{
+		{name: "all", filter: audit.Filter{}, want: entries},
+		{name: "since", filter: audit.Filter{Since: day.Add(time.Hour)}, want: entries[1:]},
+		{name: "until", filter: audit.Filter{Until: day.Add(24 * time.Hour)}, want: entries[:1]},
+		{name: "file", filter: audit.Filter{File: "./main.go"}, want: entries[:1]},
+		{name: "file glob", filter: audit.Filter{File: "*/*.go"}, want: []audit.Entry{entries[2]}},
+		{name: "repository name", filter: audit.Filter{Repository: "cwc"}, want: []audit.Entry{entries[0], entries[2]}},
+		{name: "repository root", filter: audit.Filter{Repository: "/src/other"}, want: entries[1:2]},
+		{name: "no match", filter: audit.Filter{File: "missing.go"}, want: []audit.Entry{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := log.Entries(tt.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLog_EntriesMissingLog(t *testing.T) {
+	entries, err := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl")).Entries(audit.Filter{})
+
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLog_EntriesSkipsUndecodableLines(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	first, last := newEntry(day, "/src/cwc", "main.go"), newEntry(day.Add(time.Hour), "/src/cwc", "go.mod")
+	require.NoError(t, log.Append(first))
+
+	// a line cut short by a crash while it was appended
+	file, err := os.OpenFile(log.Path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2024-05-01T12:30:00Z","endp` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, log.Append(last))
+
+	entries, err := log.Entries(audit.Filter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Entry{first, last}, entries)
+}
+
+func TestNewFiles(t *testing.T) {
+	files := []filetree.File{
+		{Path: "config.yaml", Data: []byte("key: secret\n"), Type: "yaml"},
+		{Path: "main.go", Data: []byte("package main\n"), Type: "go"},
+		{Path: "changed.go", Data: []byte("package changed\n"), Type: "go"},
+	}
+	sent := []filetree.File{
+		{Path: "config.yaml", Data: []byte("key: [REDACTED:generic-secret:1]\n"), Type: "yaml"},
+		files[1],
+	}
+
+	records := audit.NewFiles(files, sent)
+
+	assert.Equal(t, []audit.File{
+		{
+			Path:       "config.yaml",
+			SHA256:     audit.Hash(files[0].Data),
+			Size:       len(files[0].Data),
+			SentSHA256: audit.Hash(sent[0].Data),
+		},
+		{Path: "main.go", SHA256: audit.Hash(files[1].Data), Size: len(files[1].Data), SentSHA256: ""},
+		{Path: "changed.go", SHA256: audit.Hash(files[2].Data), Size: len(files[2].Data), SentSHA256: ""},
+	}, records)
+}
+
+func TestLog_AppendPermissions(t *testing.T) {
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, log.Append(newEntry(time.Now().UTC(), "/src/cwc")))
+
+	info, err := os.Stat(log.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestBackend_CreateChatCompletionStream(t *testing.T) {
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	stream := mocks.NewStream(t)
+
+	req := openai.ChatCompletionRequest{
+		Model: "gpt-4",
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "system"},
+			{Role: openai.ChatMessageRoleUser, Content: "explain this"},
+		},
+		MaxTokens: 100,
+	}
+
+	next := mocks.NewBackend(t)
+	next.EXPECT().CreateChatCompletionStream(mock.Anything, req).Return(stream, nil).Once()
+
+	backend := audit.NewBackend(next, log, func(entry *audit.Entry) {
+		entry.Template = "explain"
+		entry.StdinBytes = 42
+	})
+
+	got, err := backend.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, stream, got)
+
+	entries, err := log.Entries(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "gpt-4", entry.Model)
+	assert.Equal(t, "explain", entry.Template)
+	assert.Equal(t, 42, entry.StdinBytes)
+	assert.Equal(t, audit.Hash([]byte("explain this")), entry.PromptSHA256)
+	assert.Equal(t, 2, entry.Messages)
+	assert.Equal(t, 100, entry.MaxTokens)
+	assert.Positive(t, entry.PromptTokens)
+}
+
+func TestBackend_NotSentWhenNotRecorded(t *testing.T) {
+	// a file where the directory of the log should be
+	parent := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, os.WriteFile(parent, nil, 0o600))
+
+	backend := audit.NewBackend(mocks.NewBackend(t), audit.NewLog(filepath.Join(parent, "audit.jsonl")), nil)
+
+	_, err := backend.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{Model: "gpt-4"})
+
+	require.ErrorContains(t, err, "it was not sent")
+}
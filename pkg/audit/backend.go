@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/tokens"
+)
+
+// Describer fills in the parts of an entry the request does not tell, such as the files in the context.
+type Describer func(entry *Entry)
+
+// Backend records each request in the audit log before passing it on, retries and summaries
+// of the history included. A request that cannot be recorded is not sent.
+type Backend struct {
+	backend  chat.Backend
+	log      *Log
+	describe Describer
+}
+
+func NewBackend(backend chat.Backend, log *Log, describe Describer) *Backend {
+	return &Backend{
+		backend:  backend,
+		log:      log,
+		describe: describe,
+	}
+}
+
+func (b *Backend) CreateChatCompletionStream( //nolint:ireturn
+	ctx context.Context,
+	req openai.ChatCompletionRequest,
+) (chat.Stream, error) {
+	entry := Entry{
+		Time:         time.Now().UTC(),
+		Endpoint:     "",
+		Deployment:   "",
+		Model:        req.Model,
+		Template:     "",
+		Repository:   "",
+		Files:        []File{},
+		StdinBytes:   0,
+		PromptSHA256: Hash([]byte(lastUserMessage(req.Messages))),
+		Messages:     len(req.Messages),
+		PromptTokens: 0,
+		MaxTokens:    req.MaxTokens,
+	}
+
+	counter, err := tokens.NewCounter(req.Model)
+	if err == nil {
+		entry.PromptTokens = tokens.CountMessages(counter, req.Messages)
+	}
+
+	if b.describe != nil {
+		b.describe(&entry)
+	}
+
+	err = b.log.Append(entry)
+	if err != nil {
+		return nil, fmt.Errorf("error recording the request, it was not sent: %w", err)
+	}
+
+	return b.backend.CreateChatCompletionStream(ctx, req) //nolint:wrapcheck
+}
+
+func lastUserMessage(messages []openai.ChatCompletionMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == openai.ChatMessageRoleUser {
+			return messages[i].Content
+		}
+	}
+
+	return ""
+}
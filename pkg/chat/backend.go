@@ -0,0 +1,63 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Backend creates streaming chat completions. All supported backends speak the
+// OpenAI chat completion protocol, so the request and response types are shared.
+type Backend interface {
+	CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (Stream, error)
+}
+
+// Stream yields the chunks of a streaming chat completion until io.EOF is returned.
+type Stream interface {
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close()
+}
+
+// OpenAIBackend is a Backend for Azure OpenAI, OpenAI and any server
+// implementing the OpenAI chat completion API, such as llama.cpp or vLLM.
+type OpenAIBackend struct {
+	client       *openai.Client
+	defaultModel string
+}
+
+// NewOpenAIBackend creates a backend using the given client. The default model
+// is used for requests that do not specify a model.
+func NewOpenAIBackend(client *openai.Client, defaultModel string) *OpenAIBackend {
+	return &OpenAIBackend{
+		client:       client,
+		defaultModel: defaultModel,
+	}
+}
+
+func (b *OpenAIBackend) CreateChatCompletionStream(
+	ctx context.Context,
+	req openai.ChatCompletionRequest,
+) (Stream, error) {
+	if req.Model == "" {
+		req.Model = b.defaultModel
+	}
+
+	// filled in by the RetryAfterTransport when the client uses it
+	var retryAfter time.Duration
+
+	ctx = context.WithValue(ctx, retryAfterKey{}, &retryAfter)
+
+	stream, err := b.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("error calling chat completion API: %w", err)
+		if retryAfter > 0 {
+			return nil, &RetryAfterError{Err: err, RetryAfter: retryAfter}
+		}
+
+		return nil, err
+	}
+
+	return stream, nil
+}
@@ -7,31 +7,95 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/tokens"
 )
 
+// TruncatedMarker ends answers in the history that were cancelled before they were complete.
+const TruncatedMarker = "[answer truncated]"
+
 type Chat struct {
-	client        *openai.Client
+	backend       Backend
 	systemMessage string
 	chunkHandler  MessageChunkHandler
+	noticeHandler NoticeHandler
+	parameters    Parameters
+	compactor     Compactor
+	tokenBudget   int
+	retryPolicy   RetryPolicy
 }
 
 type MessageChunkHandler func(chunk *ConversationChunk)
 
-func NewChat(client *openai.Client, systemMessage string, onChunk MessageChunkHandler) *Chat {
-	return &Chat{
-		client:        client,
+// NoticeHandler receives informational messages about what the conversation does behind the scenes.
+type NoticeHandler func(message string)
+
+// Option configures optional behaviour of the Chat.
+type Option func(*Chat)
+
+// WithParameters sets the model and sampling parameters sent with every request.
+func WithParameters(parameters Parameters) Option {
+	return func(c *Chat) {
+		c.parameters = parameters
+	}
+}
+
+// WithCompactor compacts the history with the given compactor whenever the next
+// request would exceed the model's context window or the token budget.
+func WithCompactor(compactor Compactor, tokenBudget int) Option {
+	return func(c *Chat) {
+		c.compactor = compactor
+		c.tokenBudget = tokenBudget
+	}
+}
+
+// WithRetryPolicy overrides the DefaultRetryPolicy for failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Chat) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithNoticeHandler sets the handler for informational messages.
+func WithNoticeHandler(onNotice NoticeHandler) Option {
+	return func(c *Chat) {
+		c.noticeHandler = onNotice
+	}
+}
+
+func NewChat(backend Backend, systemMessage string, onChunk MessageChunkHandler, opts ...Option) *Chat {
+	chat := &Chat{
+		backend:       backend,
 		systemMessage: systemMessage,
 		chunkHandler:  onChunk,
+		noticeHandler: func(string) {},
+		parameters:    Parameters{},
+		compactor:     nil,
+		tokenBudget:   0,
+		retryPolicy:   DefaultRetryPolicy(),
 	}
+
+	for _, opt := range opts {
+		opt(chat)
+	}
+
+	return chat
 }
 
 func (c *Chat) BeginConversation(initialMessage string) *Conversation {
 	conversation := &Conversation{
-		client:  c.client,
-		wg:      sync.WaitGroup{},
-		onChunk: c.chunkHandler,
+		backend:     c.backend,
+		parameters:  c.parameters,
+		compactor:   c.compactor,
+		tokenBudget: c.tokenBudget,
+		retryPolicy: c.retryPolicy,
+		wg:          sync.WaitGroup{},
+		onChunk:     c.chunkHandler,
+		onNotice:    c.noticeHandler,
 		messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
@@ -45,11 +109,46 @@ func (c *Chat) BeginConversation(initialMessage string) *Conversation {
 	return conversation
 }
 
+// Request returns the request BeginConversation sends for the initial message, without sending it.
+func (c *Chat) Request(initialMessage string) openai.ChatCompletionRequest {
+	return newRequest([]openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: c.systemMessage},
+		{Role: openai.ChatMessageRoleUser, Content: initialMessage},
+	}, c.parameters)
+}
+
+// ResumeConversation continues a conversation from a previously recorded
+// message history. The history is expected to start with the system message.
+func (c *Chat) ResumeConversation(messages []openai.ChatCompletionMessage) *Conversation {
+	history := make([]openai.ChatCompletionMessage, len(messages))
+	copy(history, messages)
+
+	return &Conversation{
+		backend:     c.backend,
+		parameters:  c.parameters,
+		compactor:   c.compactor,
+		tokenBudget: c.tokenBudget,
+		retryPolicy: c.retryPolicy,
+		wg:          sync.WaitGroup{},
+		onChunk:     c.chunkHandler,
+		onNotice:    c.noticeHandler,
+		messages:    history,
+	}
+}
+
 type Conversation struct {
-	client   *openai.Client
-	messages []openai.ChatCompletionMessage
-	wg       sync.WaitGroup
-	onChunk  func(chunk *ConversationChunk)
+	backend     Backend
+	parameters  Parameters
+	compactor   Compactor
+	tokenBudget int
+	retryPolicy RetryPolicy
+	messages    []openai.ChatCompletionMessage
+	err         error
+	cancel      context.CancelFunc
+	mu          sync.Mutex
+	wg          sync.WaitGroup
+	onChunk     func(chunk *ConversationChunk)
+	onNotice    NoticeHandler
 }
 
 func (c *Conversation) addMessage(role string, message string) {
@@ -65,25 +164,147 @@ type ConversationChunk struct {
 	IsInitialChunk bool
 	IsFinalChunk   bool
 	IsErrorChunk   bool
+	// Model and FinishReason are reported by the API, they are set on the final chunk of a complete answer
+	Model        string
+	FinishReason string
 }
 
 func (c *Conversation) OnMessageChunk(onChunk func(chunk *ConversationChunk)) {
 	c.onChunk = onChunk
 }
 
+// Parameters returns the parameters used for the next request.
+func (c *Conversation) Parameters() Parameters {
+	return c.parameters
+}
+
+// SetParameters changes the parameters used for subsequent requests.
+func (c *Conversation) SetParameters(parameters Parameters) {
+	c.parameters = parameters
+}
+
+// Err returns the error of the last reply, or nil when it succeeded.
+func (c *Conversation) Err() error {
+	return c.err
+}
+
+// Messages returns a copy of the conversation history.
+func (c *Conversation) Messages() []openai.ChatCompletionMessage {
+	messages := make([]openai.ChatCompletionMessage, len(c.messages))
+	copy(messages, c.messages)
+
+	return messages
+}
+
 func (c *Conversation) WaitMyTurn() {
 	c.wg.Wait()
 }
 
+// Cancel stops the answer in progress. The partial answer is kept in the
+// history, marked with TruncatedMarker. It does nothing when no answer is in progress.
+func (c *Conversation) Cancel() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
 func (c *Conversation) Reply(message string) {
 	c.wg.Add(1)
 
 	c.addMessage(openai.ChatMessageRoleUser, message)
 
-	ctx := context.Background()
+	c.send()
+}
+
+// Retry discards the last answer and asks for a new one. It reports false
+// when there is no user message to answer.
+func (c *Conversation) Retry() bool {
+	last := c.lastUserMessage()
+	if last < 0 {
+		return false
+	}
+
+	c.wg.Add(1)
+
+	c.messages = c.messages[:last+1]
+
+	c.send()
+
+	return true
+}
+
+// Undo removes the last user message and its answer. It reports false when
+// there is nothing to undo.
+func (c *Conversation) Undo() bool {
+	last := c.lastUserMessage()
+	if last < 0 {
+		return false
+	}
+
+	c.messages = c.messages[:last]
+
+	return true
+}
+
+// Clear removes every message except the system message.
+func (c *Conversation) Clear() {
+	if len(c.messages) > 0 && c.messages[0].Role == openai.ChatMessageRoleSystem {
+		c.messages = c.messages[:1]
+		return
+	}
+
+	c.messages = nil
+}
+
+// SystemMessage returns the system message, or an empty string when there is none.
+func (c *Conversation) SystemMessage() string {
+	if len(c.messages) > 0 && c.messages[0].Role == openai.ChatMessageRoleSystem {
+		return c.messages[0].Content
+	}
+
+	return ""
+}
+
+// SetSystemMessage replaces the system message used for subsequent requests.
+func (c *Conversation) SetSystemMessage(systemMessage string) {
+	message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: systemMessage}
+
+	if len(c.messages) > 0 && c.messages[0].Role == openai.ChatMessageRoleSystem {
+		c.messages[0] = message
+		return
+	}
+
+	c.messages = append([]openai.ChatCompletionMessage{message}, c.messages...)
+}
+
+func (c *Conversation) lastUserMessage() int {
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		if c.messages[i].Role == openai.ChatMessageRoleUser {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// send requests an answer to the conversation in the background, the caller
+// must have added to the wait group.
+func (c *Conversation) send() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c.mu.Lock()
+	c.cancel = cancel
+	c.mu.Unlock()
 
 	go func() {
+		defer cancel()
+
 		err := c.processMessages(ctx)
+
+		c.err = err
 		if err != nil {
 			c.onChunk(&ConversationChunk{
 				Role:           openai.ChatMessageRoleAssistant,
@@ -91,6 +312,8 @@ func (c *Conversation) Reply(message string) {
 				IsInitialChunk: false,
 				IsFinalChunk:   true,
 				IsErrorChunk:   true,
+				Model:          "",
+				FinishReason:   "",
 			})
 		}
 
@@ -99,23 +322,122 @@ func (c *Conversation) Reply(message string) {
 }
 
 func (c *Conversation) processMessages(ctx context.Context) error {
-	req := openai.ChatCompletionRequest{
-		Model:    openai.GPT4TurboPreview,
-		Messages: c.messages,
-		Stream:   true,
+	c.compactIfNeeded(ctx)
+
+	for attempt := 1; ; attempt++ {
+		err := c.requestReply(ctx)
+		if err != nil && ctx.Err() != nil {
+			c.truncateReply("")
+			return nil
+		}
+
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		if attempt >= c.retryPolicy.MaxAttempts {
+			return errors.RetriesExhaustedError{Attempts: attempt, Err: err}
+		}
+
+		delay := c.retryPolicy.delay(attempt, retryAfter(err))
+		c.onNotice(fmt.Sprintf("%s\nretrying in %s (attempt %d of %d)",
+			err, delay.Round(time.Millisecond), attempt+1, c.retryPolicy.MaxAttempts))
+
+		select {
+		case <-ctx.Done():
+			c.truncateReply("")
+			return nil
+		case <-time.After(delay):
+		}
 	}
+}
 
-	stream, err := c.client.CreateChatCompletionStream(ctx, req)
+// requestReply sends the conversation and streams the answer.
+func (c *Conversation) requestReply(ctx context.Context) error {
+	req := newRequest(c.messages, c.parameters)
+
+	stream, err := c.backend.CreateChatCompletionStream(ctx, req)
 	if err != nil {
 		return fmt.Errorf("error creating chat completion stream: %w", err)
 	}
 
 	defer stream.Close()
 
-	return c.handleStream(stream)
+	return c.handleStream(ctx, stream)
+}
+
+func newRequest(messages []openai.ChatCompletionMessage, parameters Parameters) openai.ChatCompletionRequest {
+	req := openai.ChatCompletionRequest{
+		Messages: messages,
+		Stream:   true,
+	}
+	parameters.apply(&req)
+
+	return req
 }
 
-func (c *Conversation) handleStream(stream *openai.ChatCompletionStream) error {
+// compactIfNeeded runs the compactor when the history and the tokens reserved
+// for the answer no longer fit within the limit for the current model.
+func (c *Conversation) compactIfNeeded(ctx context.Context) {
+	if c.compactor == nil {
+		return
+	}
+
+	limit, known := tokens.Limit(c.parameters.Model, c.tokenBudget)
+	if !known {
+		return
+	}
+
+	counter, err := tokens.NewCounter(c.parameters.Model)
+	if err != nil {
+		return
+	}
+
+	// leave room for the answer
+	limit -= c.parameters.MaxTokens
+
+	before := tokens.CountMessages(counter, c.messages)
+	if before <= limit {
+		return
+	}
+
+	compacted, err := c.compactor.Compact(ctx, c.messages, limit, counter, c.parameters)
+	if err != nil {
+		c.onNotice(fmt.Sprintf("could not compact the conversation history: %s", err))
+		return
+	}
+
+	after := tokens.CountMessages(counter, compacted)
+	if after >= before {
+		c.onNotice(fmt.Sprintf("the conversation exceeds %d tokens with %d tokens and could not be compacted with %s, "+
+			"the request may be refused", limit, before, c.compactor.Name()))
+
+		return
+	}
+
+	notice := fmt.Sprintf("the conversation exceeded %d tokens, compacted the history with %s: %d → %d messages, %d → %d tokens",
+		limit, c.compactor.Name(), len(c.messages), len(compacted), before, after)
+	if after > limit {
+		notice += ", still over the limit, the request may be refused"
+	}
+
+	c.onNotice(notice)
+
+	c.messages = compacted
+}
+
+// truncateReply keeps the partial answer of a cancelled request in the history.
+func (c *Conversation) truncateReply(partial string) {
+	content := TruncatedMarker
+	if partial != "" {
+		content = partial + "\n\n" + TruncatedMarker
+	}
+
+	c.addMessage(openai.ChatMessageRoleAssistant, content)
+	c.onNotice("answer cancelled")
+}
+
+func (c *Conversation) handleStream(ctx context.Context, stream Stream) error {
 	var reply strings.Builder
 
 	c.onChunk(&ConversationChunk{
@@ -124,8 +446,12 @@ func (c *Conversation) handleStream(stream *openai.ChatCompletionStream) error {
 		IsInitialChunk: true,
 		IsFinalChunk:   false,
 		IsErrorChunk:   false,
+		Model:          "",
+		FinishReason:   "",
 	})
 
+	var model, finishReason string
+
 answer:
 	for {
 		response, err := stream.Recv()
@@ -136,19 +462,45 @@ answer:
 				IsInitialChunk: false,
 				IsFinalChunk:   true,
 				IsErrorChunk:   false,
+				Model:          model,
+				FinishReason:   finishReason,
 			})
 
 			break answer
 		}
 
 		if err != nil {
+			// end the partial answer, a retry starts a new one
+			c.onChunk(&ConversationChunk{
+				Role:           openai.ChatMessageRoleAssistant,
+				Content:        "",
+				IsInitialChunk: false,
+				IsFinalChunk:   true,
+				IsErrorChunk:   false,
+				Model:          "",
+				FinishReason:   "",
+			})
+
+			if ctx.Err() != nil {
+				c.truncateReply(reply.String())
+				return nil
+			}
+
 			return fmt.Errorf("error receiving chat completion response: %w", err)
 		}
 
+		if response.Model != "" {
+			model = response.Model
+		}
+
 		if len(response.Choices) == 0 {
 			continue answer
 		}
 
+		if response.Choices[0].FinishReason != "" {
+			finishReason = string(response.Choices[0].FinishReason)
+		}
+
 		reply.WriteString(response.Choices[0].Delta.Content)
 
 		c.onChunk(&ConversationChunk{
@@ -157,6 +509,8 @@ answer:
 			IsInitialChunk: false,
 			IsFinalChunk:   false,
 			IsErrorChunk:   false,
+			Model:          "",
+			FinishReason:   "",
 		})
 	}
 
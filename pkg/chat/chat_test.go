@@ -0,0 +1,207 @@
+package chat_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/chat"
+)
+
+func TestConversation_Cancel(t *testing.T) {
+	received := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().RunAndReturn(func() (openai.ChatCompletionStreamResponse, error) {
+		close(received)
+
+		return openai.ChatCompletionStreamResponse{
+			Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: "partial"}}},
+		}, nil
+	}).Once()
+	stream.EXPECT().Recv().RunAndReturn(func() (openai.ChatCompletionStreamResponse, error) {
+		// the HTTP response body fails like this once the request context is cancelled
+		<-cancelled
+		return openai.ChatCompletionStreamResponse{}, context.Canceled
+	}).Once()
+	stream.EXPECT().Close().Return()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(stream, nil).Once()
+
+	var errorChunks int
+
+	chatInstance := chat.NewChat(backend, "system", func(chunk *chat.ConversationChunk) {
+		if chunk.IsErrorChunk {
+			errorChunks++
+		}
+	})
+
+	conversation := chatInstance.BeginConversation("write a long story")
+
+	<-received
+	conversation.Cancel()
+	close(cancelled)
+	conversation.WaitMyTurn()
+
+	require.NoError(t, conversation.Err())
+	assert.Zero(t, errorChunks)
+
+	messages := conversation.Messages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, openai.ChatMessageRoleAssistant, messages[2].Role)
+	assert.Equal(t, "partial\n\n"+chat.TruncatedMarker, messages[2].Content)
+
+	// cancelling without an answer in progress does nothing
+	conversation.Cancel()
+}
+
+func TestConversation_EditHistory(t *testing.T) {
+	history := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: "system"},
+		{Role: openai.ChatMessageRoleUser, Content: "first"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "first answer"},
+		{Role: openai.ChatMessageRoleUser, Content: "second"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "second answer"},
+	}
+
+	tests := []struct {
+		name   string
+		edit   func(conversation *chat.Conversation) bool
+		wantOK bool
+		want   []string
+	}{
+		{
+			name:   "undo removes the last turn",
+			edit:   func(conversation *chat.Conversation) bool { return conversation.Undo() },
+			wantOK: true,
+			want:   []string{"system", "first", "first answer"},
+		},
+		{
+			name:   "clear keeps the system message",
+			edit:   func(conversation *chat.Conversation) bool { conversation.Clear(); return true },
+			wantOK: true,
+			want:   []string{"system"},
+		},
+		{
+			name: "set system message replaces it",
+			edit: func(conversation *chat.Conversation) bool {
+				conversation.SetSystemMessage("new system")
+				return conversation.SystemMessage() == "new system"
+			},
+			wantOK: true,
+			want:   []string{"new system", "first", "first answer", "second", "second answer"},
+		},
+		{
+			name: "nothing to undo after clear",
+			edit: func(conversation *chat.Conversation) bool {
+				conversation.Clear()
+				return conversation.Undo() || conversation.Retry()
+			},
+			wantOK: false,
+			want:   []string{"system"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatInstance := chat.NewChat(mocks.NewBackend(t), "", func(*chat.ConversationChunk) {})
+			conversation := chatInstance.ResumeConversation(history)
+
+			assert.Equal(t, tt.wantOK, tt.edit(conversation))
+
+			var contents []string
+			for _, message := range conversation.Messages() {
+				contents = append(contents, message.Content)
+			}
+
+			assert.Equal(t, tt.want, contents)
+		})
+	}
+}
+
+func TestConversation_Retry(t *testing.T) {
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).
+		Return(answerStream(t, "another answer"), nil).Once()
+
+	chatInstance := chat.NewChat(backend, "", func(*chat.ConversationChunk) {})
+	conversation := chatInstance.ResumeConversation([]openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: "system"},
+		{Role: openai.ChatMessageRoleUser, Content: "question"},
+		{Role: openai.ChatMessageRoleAssistant, Content: "answer"},
+	})
+
+	require.True(t, conversation.Retry())
+	conversation.WaitMyTurn()
+
+	messages := conversation.Messages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, "another answer", messages[2].Content)
+}
+
+func TestConversation_FinalChunkMetadata(t *testing.T) {
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{
+		Model:   "gpt-4o-2024-05-13",
+		Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: "answer"}}},
+	}, nil).Once()
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{
+		Model:   "gpt-4o-2024-05-13",
+		Choices: []openai.ChatCompletionStreamChoice{{FinishReason: openai.FinishReasonLength}},
+	}, nil).Once()
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(stream, nil).Once()
+
+	var final *chat.ConversationChunk
+
+	chatInstance := chat.NewChat(backend, "system", func(chunk *chat.ConversationChunk) {
+		if chunk.IsFinalChunk {
+			final = chunk
+		}
+	})
+
+	chatInstance.BeginConversation("question").WaitMyTurn()
+
+	require.NotNil(t, final)
+	assert.Equal(t, "gpt-4o-2024-05-13", final.Model)
+	assert.Equal(t, string(openai.FinishReasonLength), final.FinishReason)
+}
+
+func TestChat_Request(t *testing.T) {
+	temperature := float32(0)
+	params := chat.Parameters{Model: "gpt-4o", Temperature: &temperature, TopP: nil, MaxTokens: 100, Stop: nil, Seed: nil}
+
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	var sent openai.ChatCompletionRequest
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, req openai.ChatCompletionRequest) (chat.Stream, error) {
+			sent = req
+			return stream, nil
+		}).Once()
+
+	chatInstance := chat.NewChat(backend, "system", func(*chat.ConversationChunk) {}, chat.WithParameters(params))
+	request := chatInstance.Request("hello")
+
+	chatInstance.BeginConversation("hello").WaitMyTurn()
+
+	assert.Equal(t, sent, request)
+	assert.Equal(t, "gpt-4o", request.Model)
+	require.Len(t, request.Messages, 2)
+	assert.Equal(t, "hello", request.Messages[1].Content)
+}
@@ -0,0 +1,335 @@
+package chat
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/tokens"
+)
+
+// Supported compaction strategies.
+const (
+	CompactionNone           = "none"             // never compact, requests may fail once the history is too long
+	CompactionSlidingWindow  = "sliding-window"   // drop the oldest turns
+	CompactionSummarize      = "summarize"        // replace the oldest turns with a summary written by the model
+	CompactionDropCodeBlocks = "drop-code-blocks" // remove code blocks from older assistant replies
+
+	// DefaultCompactionWindow is the number of most recent turns every strategy keeps untouched.
+	DefaultCompactionWindow = 4
+)
+
+// CompactionStrategies lists the valid compaction strategy names.
+func CompactionStrategies() []string {
+	return []string{CompactionNone, CompactionSlidingWindow, CompactionSummarize, CompactionDropCodeBlocks}
+}
+
+// Compactor shrinks a conversation history so the next request fits within the token limit.
+// The first message is the system message and must be preserved. The parameters are those of
+// the conversation, which may have switched to another model since it started.
+type Compactor interface {
+	Name() string
+	Compact(
+		ctx context.Context,
+		messages []openai.ChatCompletionMessage,
+		limit int,
+		counter tokens.Counter,
+		params Parameters,
+	) ([]openai.ChatCompletionMessage, error)
+}
+
+// NewCompactor creates the compactor for the named strategy. It returns nil for CompactionNone.
+func NewCompactor(strategy string, window int, backend Backend) (Compactor, error) { //nolint:ireturn
+	if window <= 0 {
+		window = DefaultCompactionWindow
+	}
+
+	switch strategy {
+	case CompactionNone:
+		return nil, nil
+	case "", CompactionSlidingWindow:
+		return &SlidingWindowCompactor{Window: window}, nil
+	case CompactionSummarize:
+		return &SummarizingCompactor{Window: window, backend: backend}, nil
+	case CompactionDropCodeBlocks:
+		return &CodeBlockDroppingCompactor{Window: window}, nil
+	default:
+		return nil, fmt.Errorf("unknown compaction strategy %q, expected one of: %s",
+			strategy, strings.Join(CompactionStrategies(), ", "))
+	}
+}
+
+// SlidingWindowCompactor keeps the system message and the most recent turns.
+type SlidingWindowCompactor struct {
+	// Window is the number of most recent turns to keep
+	Window int
+}
+
+func (s *SlidingWindowCompactor) Name() string {
+	return CompactionSlidingWindow
+}
+
+func (s *SlidingWindowCompactor) Compact(
+	_ context.Context,
+	messages []openai.ChatCompletionMessage,
+	limit int,
+	counter tokens.Counter,
+	_ Parameters,
+) ([]openai.ChatCompletionMessage, error) {
+	system, turns := splitTurns(messages)
+
+	if len(turns) > s.Window {
+		turns = turns[len(turns)-s.Window:]
+	}
+
+	return fitLimit(joinTurns(system, turns), limit, counter), nil
+}
+
+// SummarizingCompactor asks the model to summarize the turns outside the window. The oldest turns
+// are dropped as well when the history does not fit within the limit with the summary.
+type SummarizingCompactor struct {
+	// Window is the number of most recent turns to keep verbatim
+	Window  int
+	backend Backend
+}
+
+func (s *SummarizingCompactor) Name() string {
+	return CompactionSummarize
+}
+
+func (s *SummarizingCompactor) Compact(
+	ctx context.Context,
+	messages []openai.ChatCompletionMessage,
+	limit int,
+	counter tokens.Counter,
+	params Parameters,
+) ([]openai.ChatCompletionMessage, error) {
+	system, turns := splitTurns(messages)
+
+	if len(turns) <= s.Window {
+		return fitLimit(messages, limit, counter), nil
+	}
+
+	older, recent := turns[:len(turns)-s.Window], turns[len(turns)-s.Window:]
+
+	summary, err := s.summarize(ctx, older, limit, counter, params)
+	if err != nil {
+		return nil, err
+	}
+
+	summaryTurn := []openai.ChatCompletionMessage{{
+		Role:    openai.ChatMessageRoleSystem,
+		Content: "Summary of the earlier conversation:\n" + summary,
+	}}
+
+	compacted := joinTurns(system, append([][]openai.ChatCompletionMessage{summaryTurn}, recent...))
+
+	return fitLimit(compacted, limit, counter), nil
+}
+
+// summarize summarizes the turns a chunk at a time, each chunk taking up to half the limit so that
+// the request fits along with the summary of the chunks before it.
+func (s *SummarizingCompactor) summarize(
+	ctx context.Context,
+	turns [][]openai.ChatCompletionMessage,
+	limit int,
+	counter tokens.Counter,
+	params Parameters,
+) (string, error) {
+	var summary string
+
+	for _, chunk := range transcriptChunks(turns, max(limit/2, 1), counter) { //nolint:mnd
+		if summary != "" {
+			chunk = "Summary of the conversation before:\n" + summary + "\n\n" + chunk
+		}
+
+		var err error
+
+		summary, err = s.requestSummary(ctx, chunk, params)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *SummarizingCompactor) requestSummary(ctx context.Context, transcript string, params Parameters) (string, error) {
+	req := openai.ChatCompletionRequest{
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role: openai.ChatMessageRoleSystem,
+				Content: "Summarize the following conversation between a user and a coding assistant. " +
+					"Keep decisions, conclusions, file names, identifiers and open questions. Be concise.",
+			},
+			{Role: openai.ChatMessageRoleUser, Content: transcript},
+		},
+		Stream: true,
+	}
+	params.apply(&req)
+	// the summary should not be cut short by a limit meant for answers
+	req.MaxTokens = 0
+
+	stream, err := s.backend.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("error creating summary: %w", err)
+	}
+
+	defer stream.Close()
+
+	var summary strings.Builder
+
+	for {
+		response, err := stream.Recv()
+		if stderrors.Is(err, io.EOF) {
+			return summary.String(), nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("error receiving summary: %w", err)
+		}
+
+		if len(response.Choices) > 0 {
+			summary.WriteString(response.Choices[0].Delta.Content)
+		}
+	}
+}
+
+// CodeBlockDroppingCompactor replaces fenced code blocks in assistant replies outside the window.
+// The oldest turns are dropped as well when the history still does not fit within the limit.
+type CodeBlockDroppingCompactor struct {
+	// Window is the number of most recent turns to keep untouched
+	Window int
+}
+
+var codeBlockPattern = regexp.MustCompile("(?s)```[^\n]*\n.*?```") //nolint:gochecknoglobals
+
+const droppedCodeBlock = "```\n[code block removed to save tokens]\n```"
+
+// truncatedText ends a message cut short to fit in the summary request.
+const truncatedText = " [truncated]"
+
+func (d *CodeBlockDroppingCompactor) Name() string {
+	return CompactionDropCodeBlocks
+}
+
+func (d *CodeBlockDroppingCompactor) Compact(
+	_ context.Context,
+	messages []openai.ChatCompletionMessage,
+	limit int,
+	counter tokens.Counter,
+	_ Parameters,
+) ([]openai.ChatCompletionMessage, error) {
+	system, turns := splitTurns(messages)
+
+	for i := 0; i < len(turns)-d.Window; i++ {
+		for j, message := range turns[i] {
+			if message.Role == openai.ChatMessageRoleAssistant {
+				turns[i][j].Content = codeBlockPattern.ReplaceAllString(message.Content, droppedCodeBlock)
+			}
+		}
+	}
+
+	return fitLimit(joinTurns(system, turns), limit, counter), nil
+}
+
+// fitLimit drops the oldest turns while the history is too large, but always keeps the latest turn.
+func fitLimit(messages []openai.ChatCompletionMessage, limit int, counter tokens.Counter) []openai.ChatCompletionMessage {
+	system, turns := splitTurns(messages)
+
+	for len(turns) > 1 && tokens.CountMessages(counter, joinTurns(system, turns)) > limit {
+		turns = turns[1:]
+	}
+
+	return joinTurns(system, turns)
+}
+
+// transcriptChunks writes the turns out as transcripts of up to limit tokens each. A message too
+// large for a chunk of its own is cut short.
+func transcriptChunks(turns [][]openai.ChatCompletionMessage, limit int, counter tokens.Counter) []string {
+	var (
+		chunks []string
+		chunk  strings.Builder
+		size   int
+	)
+
+	for _, turn := range turns {
+		for _, message := range turn {
+			entry := truncateTokens(message.Role+": "+message.Content, limit, counter) + "\n\n"
+			count := counter.Count(entry)
+
+			if size > 0 && size+count > limit {
+				chunks = append(chunks, chunk.String())
+				chunk.Reset()
+
+				size = 0
+			}
+
+			chunk.WriteString(entry)
+
+			size += count
+		}
+	}
+
+	if size > 0 {
+		chunks = append(chunks, chunk.String())
+	}
+
+	return chunks
+}
+
+// truncateTokens cuts the text short to fit within the limit, marking where it was cut.
+func truncateTokens(text string, limit int, counter tokens.Counter) string {
+	count := counter.Count(text)
+	if count <= limit {
+		return text
+	}
+
+	target := limit - counter.Count(truncatedText)
+	runes := []rune(text)
+
+	for count > target && len(runes) > 0 {
+		// cut in proportion to the excess, by at least one character
+		runes = runes[:min(len(runes)-1, len(runes)*max(target, 0)/count)]
+		count = counter.Count(string(runes))
+	}
+
+	return string(runes) + truncatedText
+}
+
+// splitTurns separates the system message from the turns of the conversation.
+// A turn starts with a user message and holds every message up to the next one.
+func splitTurns(messages []openai.ChatCompletionMessage) (openai.ChatCompletionMessage, [][]openai.ChatCompletionMessage) {
+	if len(messages) == 0 {
+		return openai.ChatCompletionMessage{}, nil
+	}
+
+	var turns [][]openai.ChatCompletionMessage
+
+	for _, message := range messages[1:] {
+		if len(turns) == 0 || message.Role == openai.ChatMessageRoleUser {
+			turns = append(turns, []openai.ChatCompletionMessage{})
+		}
+
+		turns[len(turns)-1] = append(turns[len(turns)-1], message)
+	}
+
+	return messages[0], turns
+}
+
+func joinTurns(
+	system openai.ChatCompletionMessage,
+	turns [][]openai.ChatCompletionMessage,
+) []openai.ChatCompletionMessage {
+	messages := []openai.ChatCompletionMessage{system}
+	for _, turn := range turns {
+		messages = append(messages, turn...)
+	}
+
+	return messages
+}
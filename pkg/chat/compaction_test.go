@@ -0,0 +1,335 @@
+package chat_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/tokens"
+)
+
+// wordCounter counts words so the expected token counts are easy to reason about.
+type wordCounter struct{}
+
+func (wordCounter) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+func conversationMessages(turns int) []openai.ChatCompletionMessage {
+	messages := []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleSystem, Content: "system"}}
+
+	for i := 0; i < turns; i++ {
+		messages = append(messages,
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: "question"},
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "answer\n```go\ncode\n```"},
+		)
+	}
+
+	return messages
+}
+
+func TestNewCompactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		wantName string
+		wantNil  bool
+		wantErr  bool
+	}{
+		{name: "default is sliding window", strategy: "", wantName: chat.CompactionSlidingWindow},
+		{name: "sliding window", strategy: chat.CompactionSlidingWindow, wantName: chat.CompactionSlidingWindow},
+		{name: "summarize", strategy: chat.CompactionSummarize, wantName: chat.CompactionSummarize},
+		{name: "drop code blocks", strategy: chat.CompactionDropCodeBlocks, wantName: chat.CompactionDropCodeBlocks},
+		{name: "none disables compaction", strategy: chat.CompactionNone, wantNil: true},
+		{name: "unknown strategy", strategy: "forget-everything", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compactor, err := chat.NewCompactor(tt.strategy, 0, nil)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.wantNil {
+				assert.Nil(t, compactor)
+				return
+			}
+
+			assert.Equal(t, tt.wantName, compactor.Name())
+		})
+	}
+}
+
+func TestSlidingWindowCompactor_Compact(t *testing.T) {
+	tests := []struct {
+		name         string
+		window       int
+		turns        int
+		limit        int
+		wantMessages int
+	}{
+		{name: "keeps the window when it fits", window: 2, turns: 5, limit: 1000, wantMessages: 5},
+		{name: "drops turns until it fits", window: 4, turns: 5, limit: 30, wantMessages: 3},
+		{name: "always keeps the latest turn", window: 4, turns: 5, limit: 1, wantMessages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compactor := &chat.SlidingWindowCompactor{Window: tt.window}
+			messages := conversationMessages(tt.turns)
+
+			compacted, err := compactor.Compact(context.Background(), messages, tt.limit, wordCounter{}, chat.Parameters{})
+
+			require.NoError(t, err)
+			assert.Len(t, compacted, tt.wantMessages)
+			assert.Equal(t, messages[0], compacted[0])
+			assert.Equal(t, messages[len(messages)-1], compacted[len(compacted)-1])
+		})
+	}
+}
+
+func TestCodeBlockDroppingCompactor_Compact(t *testing.T) {
+	compactor := &chat.CodeBlockDroppingCompactor{Window: 1}
+	messages := conversationMessages(3)
+
+	compacted, err := compactor.Compact(context.Background(), messages, 1000, wordCounter{}, chat.Parameters{})
+
+	require.NoError(t, err)
+	require.Len(t, compacted, len(messages))
+	assert.NotContains(t, compacted[2].Content, "code\n")
+	assert.NotContains(t, compacted[4].Content, "code\n")
+	assert.Equal(t, messages[6], compacted[6], "the latest turn is kept untouched")
+	assert.Contains(t, messages[2].Content, "code\n", "the original history is not modified")
+}
+
+func TestSummarizingCompactor_Compact(t *testing.T) {
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{
+		Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: "short"}}},
+	}, nil).Once()
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	// the summary is asked of the current model of the conversation, without the limit of the answers
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.MatchedBy(func(req openai.ChatCompletionRequest) bool {
+		return req.Model == "gpt-4o-mini" && req.MaxTokens == 0
+	})).Return(stream, nil)
+
+	compactor, err := chat.NewCompactor(chat.CompactionSummarize, 1, backend)
+	require.NoError(t, err)
+
+	messages := conversationMessages(3)
+
+	compacted, err := compactor.Compact(context.Background(), messages, 1000, wordCounter{},
+		chat.Parameters{Model: "gpt-4o-mini", MaxTokens: 10})
+
+	require.NoError(t, err)
+	require.Len(t, compacted, 4)
+	assert.Equal(t, messages[0], compacted[0])
+	assert.Equal(t, openai.ChatMessageRoleSystem, compacted[1].Role)
+	assert.Contains(t, compacted[1].Content, "short")
+	assert.Equal(t, messages[5:], compacted[2:])
+}
+
+func TestCodeBlockDroppingCompactor_CompactDropsTurnsOverLimit(t *testing.T) {
+	compactor := &chat.CodeBlockDroppingCompactor{Window: 1}
+	messages := conversationMessages(3)
+
+	compacted, err := compactor.Compact(context.Background(), messages, 30, wordCounter{}, chat.Parameters{})
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, tokens.CountMessages(wordCounter{}, compacted), 30)
+	assert.Equal(t, []openai.ChatCompletionMessage{messages[0], messages[5], messages[6]}, compacted)
+}
+
+// summaryStream streams the summary.
+func summaryStream(t *testing.T, summary string) *mocks.Stream {
+	t.Helper()
+
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{
+		Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: summary}}},
+	}, nil).Once()
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	return stream
+}
+
+// summarizingBackend answers every summary request with the summary and records the transcripts.
+func summarizingBackend(t *testing.T, summary string, transcripts *[]string) *mocks.Backend {
+	t.Helper()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, req openai.ChatCompletionRequest) (chat.Stream, error) {
+			*transcripts = append(*transcripts, req.Messages[1].Content)
+			return summaryStream(t, summary), nil
+		})
+
+	return backend
+}
+
+func TestSummarizingCompactor_CompactInChunks(t *testing.T) {
+	var transcripts []string
+
+	compactor, err := chat.NewCompactor(chat.CompactionSummarize, 1, summarizingBackend(t, "short", &transcripts))
+	require.NoError(t, err)
+
+	messages := conversationMessages(10)
+
+	compacted, err := compactor.Compact(context.Background(), messages, 40, wordCounter{}, chat.Parameters{})
+
+	require.NoError(t, err)
+	require.Greater(t, len(transcripts), 1, "the older turns do not fit in a single summary request")
+
+	for _, transcript := range transcripts {
+		assert.LessOrEqual(t, wordCounter{}.Count(transcript), 40)
+	}
+
+	assert.Contains(t, transcripts[1], "Summary of the conversation before:\nshort")
+	assert.Len(t, compacted, 4)
+	assert.Contains(t, compacted[1].Content, "short")
+	assert.LessOrEqual(t, tokens.CountMessages(wordCounter{}, compacted), 40)
+}
+
+func TestSummarizingCompactor_CompactTruncatesLargeMessages(t *testing.T) {
+	var transcripts []string
+
+	compactor, err := chat.NewCompactor(chat.CompactionSummarize, 1, summarizingBackend(t, "short", &transcripts))
+	require.NoError(t, err)
+
+	messages := conversationMessages(2)
+	messages[1].Content = strings.Repeat("pasted log line\n", 100)
+
+	_, err = compactor.Compact(context.Background(), messages, 40, wordCounter{}, chat.Parameters{})
+
+	require.NoError(t, err)
+	require.Len(t, transcripts, 2)
+	assert.LessOrEqual(t, wordCounter{}.Count(transcripts[0]), 20)
+	assert.Contains(t, transcripts[0], "[truncated]")
+}
+
+func TestSummarizingCompactor_CompactDropsTurnsOverLimit(t *testing.T) {
+	var transcripts []string
+
+	longSummary := strings.Repeat("detail ", 50)
+	compactor, err := chat.NewCompactor(chat.CompactionSummarize, 2, summarizingBackend(t, longSummary, &transcripts))
+	require.NoError(t, err)
+
+	messages := conversationMessages(4)
+
+	compacted, err := compactor.Compact(context.Background(), messages, 40, wordCounter{}, chat.Parameters{})
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, tokens.CountMessages(wordCounter{}, compacted), 40)
+	assert.Equal(t, messages[0], compacted[0])
+	assert.Equal(t, messages[len(messages)-2:], compacted[len(compacted)-2:])
+
+	for _, message := range compacted {
+		assert.NotContains(t, message.Content, "detail", "the summary does not fit")
+	}
+}
+
+func TestConversation_CompactsWhenOverBudget(t *testing.T) {
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(stream, nil)
+
+	var notices []string
+
+	chatInstance := chat.NewChat(backend, "", func(*chat.ConversationChunk) {},
+		chat.WithParameters(chat.Parameters{Model: "gpt-4"}),
+		chat.WithCompactor(&chat.SlidingWindowCompactor{Window: 1}, 50),
+		chat.WithNoticeHandler(func(message string) { notices = append(notices, message) }),
+	)
+
+	conversation := chatInstance.ResumeConversation(conversationMessages(10))
+	conversation.Reply("question")
+	conversation.WaitMyTurn()
+
+	require.Len(t, notices, 1)
+	assert.Contains(t, notices[0], chat.CompactionSlidingWindow)
+	// the system message, the new question and the empty answer
+	assert.Len(t, conversation.Messages(), 3)
+}
+
+func TestConversation_CompactsWithCurrentParameters(t *testing.T) {
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(stream, nil)
+
+	switched := chat.Parameters{Model: "gpt-4o-mini", MaxTokens: 5}
+	compacted := conversationMessages(1)
+
+	compactor := mocks.NewCompactor(t)
+	compactor.EXPECT().Name().Return(chat.CompactionSummarize)
+	compactor.EXPECT().Compact(mock.Anything, mock.Anything, mock.Anything, mock.Anything, switched).
+		Return(compacted, nil).Once()
+
+	chatInstance := chat.NewChat(backend, "", func(*chat.ConversationChunk) {},
+		chat.WithParameters(chat.Parameters{Model: "gpt-4", MaxTokens: 10}),
+		chat.WithCompactor(compactor, 50),
+	)
+
+	conversation := chatInstance.ResumeConversation(conversationMessages(10))
+	conversation.SetParameters(switched)
+	conversation.Reply("question")
+	conversation.WaitMyTurn()
+}
+
+func TestConversation_ReportsFailedCompaction(t *testing.T) {
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	backend := mocks.NewBackend(t)
+	backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(stream, nil)
+
+	compactor := mocks.NewCompactor(t)
+	compactor.EXPECT().Name().Return(chat.CompactionSummarize)
+	compactor.EXPECT().Compact(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, messages []openai.ChatCompletionMessage, _ int, _ tokens.Counter,
+			_ chat.Parameters,
+		) ([]openai.ChatCompletionMessage, error) {
+			return messages, nil
+		})
+
+	var notices []string
+
+	chatInstance := chat.NewChat(backend, "", func(*chat.ConversationChunk) {},
+		chat.WithParameters(chat.Parameters{Model: "gpt-4"}),
+		chat.WithCompactor(compactor, 50),
+		chat.WithNoticeHandler(func(message string) { notices = append(notices, message) }),
+	)
+
+	conversation := chatInstance.ResumeConversation(conversationMessages(10))
+	conversation.Reply("question")
+	conversation.WaitMyTurn()
+
+	require.Len(t, notices, 1)
+	assert.Contains(t, notices[0], "could not be compacted")
+	assert.NotContains(t, notices[0], "compacted the history")
+	// the whole history, the new question and the empty answer
+	assert.Len(t, conversation.Messages(), 23)
+}
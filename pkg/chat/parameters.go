@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"math"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Parameters controls which model answers and how the answer is sampled.
+// Unset fields are left out of the request so the server defaults apply.
+type Parameters struct {
+	Model       string   `json:"model,omitempty"       yaml:"model,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	TopP        *float32 `json:"topP,omitempty"        yaml:"topP,omitempty"`
+	MaxTokens   int      `json:"maxTokens,omitempty"   yaml:"maxTokens,omitempty"`
+	Stop        []string `json:"stop,omitempty"        yaml:"stop,omitempty"`
+	Seed        *int     `json:"seed,omitempty"        yaml:"seed,omitempty"`
+}
+
+// Merge returns a copy of p where every field set in override replaces the value in p.
+func (p Parameters) Merge(override Parameters) Parameters {
+	merged := p
+
+	if override.Model != "" {
+		merged.Model = override.Model
+	}
+
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+
+	if override.MaxTokens != 0 {
+		merged.MaxTokens = override.MaxTokens
+	}
+
+	if len(override.Stop) > 0 {
+		merged.Stop = override.Stop
+	}
+
+	if override.Seed != nil {
+		merged.Seed = override.Seed
+	}
+
+	return merged
+}
+
+func (p Parameters) apply(req *openai.ChatCompletionRequest) {
+	req.Model = p.Model
+	req.MaxTokens = p.MaxTokens
+	req.Stop = p.Stop
+	req.Seed = p.Seed
+
+	if p.Temperature != nil {
+		req.Temperature = nonZero(*p.Temperature)
+	}
+
+	if p.TopP != nil {
+		req.TopP = nonZero(*p.TopP)
+	}
+}
+
+// nonZero works around the request fields being tagged omitempty, which would
+// otherwise drop an explicit zero and fall back to the server default.
+func nonZero(value float32) float32 {
+	if value == 0 {
+		return math.SmallestNonzeroFloat32
+	}
+
+	return value
+}
@@ -0,0 +1,40 @@
+package chat_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/cwc/pkg/chat"
+)
+
+func TestParameters_Merge(t *testing.T) {
+	low, high := float32(0.2), float32(0.9)
+	seed := 42
+
+	tests := []struct {
+		name     string
+		base     chat.Parameters
+		override chat.Parameters
+		want     chat.Parameters
+	}{
+		{
+			name:     "empty override keeps base",
+			base:     chat.Parameters{Model: "gpt-4", Temperature: &low, MaxTokens: 100},
+			override: chat.Parameters{},
+			want:     chat.Parameters{Model: "gpt-4", Temperature: &low, MaxTokens: 100},
+		},
+		{
+			name:     "set fields replace base",
+			base:     chat.Parameters{Model: "gpt-4", Temperature: &low, Stop: []string{"a"}},
+			override: chat.Parameters{Model: "gpt-4o", Temperature: &high, Seed: &seed},
+			want:     chat.Parameters{Model: "gpt-4o", Temperature: &high, Stop: []string{"a"}, Seed: &seed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.base.Merge(tt.override))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultBaseDelay   = time.Second
+	defaultMaxDelay    = 30 * time.Second
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay, a Retry-After header from the server may exceed it
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries up to three times, waiting about 1, 2 and 4 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+	}
+}
+
+// delay returns how long to wait before the next attempt. The server's Retry-After
+// takes precedence, otherwise the delay grows exponentially with full jitter.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff))) + 1 //nolint:gosec
+}
+
+// RetryAfterError is returned by the backend when the server asked the client
+// to wait before retrying, typically for rate limited requests.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfter returns the delay requested by the server, or zero when there is none.
+func retryAfter(err error) time.Duration {
+	var retryAfterErr *RetryAfterError
+	if stderrors.As(err, &retryAfterErr) {
+		return retryAfterErr.RetryAfter
+	}
+
+	return 0
+}
+
+// isRetryable reports whether a request failing with err may succeed when sent again:
+// rate limits, server errors, connection resets and timeouts.
+func isRetryable(err error) bool {
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *openai.APIError
+	if stderrors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.HTTPStatusCode)
+	}
+
+	var requestErr *openai.RequestError
+	if stderrors.As(err, &requestErr) {
+		return isRetryableStatus(requestErr.HTTPStatusCode)
+	}
+
+	if stderrors.Is(err, syscall.ECONNRESET) ||
+		stderrors.Is(err, syscall.EPIPE) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return stderrors.As(err, &netErr) && netErr.Timeout()
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+type retryAfterKey struct{}
+
+// RetryAfterTransport records the Retry-After header of failed responses, which
+// the OpenAI client does not expose, so the backend can honour it.
+type RetryAfterTransport struct {
+	Base http.RoundTripper
+}
+
+func (t *RetryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err //nolint:wrapcheck
+	}
+
+	recorded, ok := req.Context().Value(retryAfterKey{}).(*time.Duration)
+	if ok && resp.StatusCode >= http.StatusBadRequest {
+		*recorded = parseRetryAfter(resp.Header, time.Now())
+	}
+
+	return resp, nil
+}
+
+// parseRetryAfter reads the delay from the retry-after-ms header sent by Azure
+// or the standard Retry-After header in seconds or as an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.Atoi(header.Get("Retry-After-Ms")); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,168 @@
+package chat_test
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/errors"
+)
+
+func answerStream(t *testing.T, content string) *mocks.Stream {
+	t.Helper()
+
+	stream := mocks.NewStream(t)
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{
+		Choices: []openai.ChatCompletionStreamChoice{{Delta: openai.ChatCompletionStreamChoiceDelta{Content: content}}},
+	}, nil).Once()
+	stream.EXPECT().Recv().Return(openai.ChatCompletionStreamResponse{}, io.EOF).Once()
+	stream.EXPECT().Close().Return()
+
+	return stream
+}
+
+func TestConversation_Retries(t *testing.T) {
+	rateLimited := &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests, Message: "slow down"}
+	badRequest := &openai.APIError{HTTPStatusCode: http.StatusBadRequest, Message: "bad request"}
+	serverError := &openai.RequestError{HTTPStatusCode: http.StatusBadGateway}
+
+	tests := []struct {
+		name        string
+		failures    []error
+		wantCalls   int
+		wantNotices int
+		wantAnswer  bool
+		wantErr     func(err error) bool
+	}{
+		{
+			name:       "no failure",
+			wantCalls:  1,
+			wantAnswer: true,
+		},
+		{
+			name:        "retries rate limits and server errors",
+			failures:    []error{rateLimited, serverError},
+			wantCalls:   3,
+			wantNotices: 2,
+			wantAnswer:  true,
+		},
+		{
+			name:        "retries connection resets",
+			failures:    []error{syscall.ECONNRESET},
+			wantCalls:   2,
+			wantNotices: 1,
+			wantAnswer:  true,
+		},
+		{
+			name:      "does not retry client errors",
+			failures:  []error{badRequest},
+			wantCalls: 1,
+			wantErr:   func(err error) bool { return err != nil && !errors.IsRetriesExhaustedError(err) },
+		},
+		{
+			name:        "gives up after the last attempt",
+			failures:    []error{rateLimited, rateLimited, rateLimited},
+			wantCalls:   3,
+			wantNotices: 2,
+			wantErr:     errors.IsRetriesExhaustedError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := mocks.NewBackend(t)
+
+			for _, failure := range tt.failures {
+				backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).Return(nil, failure).Once()
+			}
+
+			if tt.wantAnswer {
+				backend.EXPECT().CreateChatCompletionStream(mock.Anything, mock.Anything).
+					Return(answerStream(t, "hello"), nil).Once()
+			}
+
+			var notices []string
+
+			chatInstance := chat.NewChat(backend, "system", func(*chat.ConversationChunk) {},
+				chat.WithRetryPolicy(chat.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+				chat.WithNoticeHandler(func(message string) { notices = append(notices, message) }),
+			)
+
+			conversation := chatInstance.BeginConversation("hi")
+			conversation.WaitMyTurn()
+
+			backend.AssertNumberOfCalls(t, "CreateChatCompletionStream", tt.wantCalls)
+			assert.Len(t, notices, tt.wantNotices)
+
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(conversation.Err()), "unexpected error: %v", conversation.Err())
+				return
+			}
+
+			require.NoError(t, conversation.Err())
+
+			messages := conversation.Messages()
+			assert.Equal(t, "hello", messages[len(messages)-1].Content)
+		})
+	}
+}
+
+func TestOpenAIBackend_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantStatus int
+		wantDelay  time.Duration
+	}{
+		{name: "seconds", header: "Retry-After", value: "7", wantStatus: http.StatusTooManyRequests, wantDelay: 7 * time.Second},
+		{name: "milliseconds", header: "Retry-After-Ms", value: "1500", wantStatus: http.StatusServiceUnavailable, wantDelay: 1500 * time.Millisecond},
+		{name: "missing", header: "X-Other", value: "1", wantStatus: http.StatusTooManyRequests, wantDelay: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set(tt.header, tt.value)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.wantStatus)
+				_, _ = w.Write([]byte(`{"error":{"message":"try again later","type":"rate_limit"}}`))
+			}))
+			defer server.Close()
+
+			clientConfig := openai.DefaultConfig("key")
+			clientConfig.BaseURL = server.URL
+			clientConfig.HTTPClient = &http.Client{Transport: &chat.RetryAfterTransport{Base: http.DefaultTransport}}
+
+			backend := chat.NewOpenAIBackend(openai.NewClientWithConfig(clientConfig), "gpt-4")
+
+			_, err := backend.CreateChatCompletionStream(context.Background(), openai.ChatCompletionRequest{Stream: true})
+			require.Error(t, err)
+
+			var apiErr *openai.APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.wantStatus, apiErr.HTTPStatusCode)
+
+			var retryAfterErr *chat.RetryAfterError
+			if tt.wantDelay == 0 {
+				assert.False(t, stderrors.As(err, &retryAfterErr))
+				return
+			}
+
+			require.ErrorAs(t, err, &retryAfterErr)
+			assert.Equal(t, tt.wantDelay, retryAfterErr.RetryAfter)
+		})
+	}
+}
@@ -2,12 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
 )
 
 type ClientProvider interface {
-	NewClientFromConfig() (*openai.Client, error)
+	NewClientFromConfig() (chat.Backend, error)
 }
 
 type OpenAIClientProvider struct {
@@ -18,13 +21,19 @@ func NewOpenAIClientProvider(provider Provider) *OpenAIClientProvider {
 	return &OpenAIClientProvider{cfg: provider}
 }
 
-func (c *OpenAIClientProvider) NewClientFromConfig() (*openai.Client, error) {
-	cfg, err := c.cfg.NewFromConfigFile()
+func (c *OpenAIClientProvider) NewClientFromConfig() (chat.Backend, error) { //nolint:ireturn
+	clientConfig, err := c.cfg.NewFromConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("error creating client: %w", err)
+	}
+
+	cfg, err := c.cfg.GetConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error creating client: %w", err)
 	}
 
-	client := openai.NewClientWithConfig(cfg)
+	clientConfig.HTTPClient = &http.Client{Transport: &chat.RetryAfterTransport{Base: http.DefaultTransport}}
+	client := openai.NewClientWithConfig(clientConfig)
 
-	return client, nil
+	return chat.NewOpenAIBackend(client, cfg.DefaultModel()), nil
 }
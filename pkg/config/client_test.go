@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/chat"
 )
 
 func TestNewClientFromConfig(t *testing.T) {
@@ -22,15 +23,31 @@ func TestNewClientFromConfig(t *testing.T) {
 	tests := []struct {
 		name       string
 		setupMocks func(testConfig)
-		wantResult func(t *testing.T, result *openai.Client)
+		wantResult func(t *testing.T, result chat.Backend)
 		wantErr    func(t *testing.T, err error)
 	}{
 		{
 			name: "success",
 			setupMocks: func(m testConfig) {
 				m.cfgProvider.On("NewFromConfigFile").Return(m.clientConfig, nil)
+				m.cfgProvider.On("GetConfig").Return(config.NewConfig("https://example.com", "gpt-4"), nil)
 			},
-			wantResult: func(t *testing.T, result *openai.Client) {
+			wantResult: func(t *testing.T, result chat.Backend) {
+				assert.NotNil(t, result)
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "openai backend",
+			setupMocks: func(m testConfig) {
+				cfg := config.NewConfig("", "gpt-4")
+				cfg.Backend = config.BackendOpenAI
+				m.cfgProvider.On("NewFromConfigFile").Return(m.clientConfig, nil)
+				m.cfgProvider.On("GetConfig").Return(cfg, nil)
+			},
+			wantResult: func(t *testing.T, result chat.Backend) {
 				assert.NotNil(t, result)
 			},
 			wantErr: func(t *testing.T, err error) {
@@ -43,7 +60,7 @@ func TestNewClientFromConfig(t *testing.T) {
 				m.cfgProvider.On("NewFromConfigFile").
 					Return(openai.ClientConfig{}, errors.New("error reading config"))
 			},
-			wantResult: func(t *testing.T, result *openai.Client) {
+			wantResult: func(t *testing.T, result chat.Backend) {
 				assert.Nil(t, result)
 			},
 			wantErr: func(t *testing.T, err error) {
@@ -4,6 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/intility/cwc/pkg/chat"
+	"github.com/intility/cwc/pkg/filetree"
 )
 
 const (
@@ -12,16 +17,47 @@ const (
 	apiVersion            = "2024-02-01"
 )
 
+// Supported chat completion backends.
+const (
+	BackendAzure            = "azure"             // Azure OpenAI, the endpoint is the resource url
+	BackendOpenAI           = "openai"            // OpenAI, the endpoint defaults to the public api
+	BackendOpenAICompatible = "openai-compatible" // any OpenAI compatible server, e.g. llama.cpp or vLLM
+)
+
+// Backends lists the valid values for Config.Backend.
+func Backends() []string {
+	return []string{BackendAzure, BackendOpenAI, BackendOpenAICompatible}
+}
+
 // SanitizeInput trims whitespaces and newlines from a string.
 func SanitizeInput(input string) string {
 	return strings.TrimSpace(input)
 }
 
 type Config struct {
-	Endpoint        string `yaml:"endpoint"`
+	// Backend is one of the Backend* constants, an empty value means azure
+	Backend  string `yaml:"backend,omitempty"`
+	Endpoint string `yaml:"endpoint"`
+	// ModelDeployment is the deployment name on azure and the model name for other backends
 	ModelDeployment string `yaml:"modelDeployment"`
 	ExcludeGitDir   bool   `yaml:"excludeGitDir"`
 	UseGitignore    bool   `yaml:"useGitignore"`
+	// TokenBudget caps the tokens of a single request below the model's context window, zero means no cap
+	TokenBudget int `yaml:"tokenBudget,omitempty"`
+	// Compaction is the strategy used to shrink long conversation histories, empty means sliding-window
+	Compaction string `yaml:"compaction,omitempty"`
+	// CompactionWindow is the number of recent turns kept untouched by compaction, zero means the default
+	CompactionWindow int `yaml:"compactionWindow,omitempty"`
+	// Secrets is what to do with secrets found in the files of the context: redact, block or off,
+	// empty means redact
+	Secrets string `yaml:"secrets,omitempty"`
+	// AuditLog records every request sent to the model in the audit log of the state directory
+	AuditLog bool `yaml:"auditLog,omitempty"`
+	// Languages override the languages of the files and which files are read as text or never included,
+	// the languages of the project config are added on top
+	Languages filetree.LanguageOverrides `yaml:"languages,omitempty"`
+	// Parameters are the default model and sampling parameters, templates and flags may override them
+	chat.Parameters `yaml:",inline"`
 	// Keep APIKey unexported to avoid accidental exposure
 	apiKey string
 }
@@ -29,11 +65,19 @@ type Config struct {
 // NewConfig creates a new Config object.
 func NewConfig(endpoint, modelDeployment string) *Config {
 	return &Config{
-		Endpoint:        endpoint,
-		ModelDeployment: modelDeployment,
-		ExcludeGitDir:   true,
-		UseGitignore:    true,
-		apiKey:          "",
+		Backend:          BackendAzure,
+		Endpoint:         endpoint,
+		ModelDeployment:  modelDeployment,
+		ExcludeGitDir:    true,
+		UseGitignore:     true,
+		TokenBudget:      0,
+		Compaction:       "",
+		CompactionWindow: 0,
+		Secrets:          "",
+		AuditLog:         false,
+		Languages:        filetree.LanguageOverrides{},
+		Parameters:       chat.Parameters{},
+		apiKey:           "",
 	}
 }
 
@@ -47,10 +91,35 @@ func (c *Config) APIKey() string {
 	return c.apiKey
 }
 
+// DefaultModel returns the model used when neither config, template nor flags set one.
+// On azure the deployment decides which model answers, the model name is still used
+// to look up properties such as the context window.
+func (c *Config) DefaultModel() string {
+	if c.BackendOrDefault() == BackendAzure {
+		return openai.GPT4TurboPreview
+	}
+
+	return c.ModelDeployment
+}
+
+// BackendOrDefault returns the configured backend, falling back to azure
+// for config files written before the backend could be chosen.
+func (c *Config) BackendOrDefault() string {
+	if c.Backend == "" {
+		return BackendAzure
+	}
+
+	return c.Backend
+}
+
 func GetConfigDir() (string, error) {
 	return XdgConfigPath()
 }
 
+func GetStateDir() (string, error) {
+	return XdgStatePath()
+}
+
 func DefaultConfigPath() (string, error) {
 	cfgPath, err := GetConfigDir()
 	if err != nil {
@@ -0,0 +1,45 @@
+package config
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/intility/cwc/pkg/filetree"
+)
+
+// DefaultProjectConfigPath returns the path of the config shared by everyone working in the
+// repository, next to its templates.
+func DefaultProjectConfigPath() string {
+	return filepath.Join(".cwc", "config.yaml")
+}
+
+// ProjectConfig holds the settings a repository may set for itself, on top of the user config.
+type ProjectConfig struct {
+	Languages filetree.LanguageOverrides `yaml:"languages,omitempty"`
+}
+
+// LoadProjectConfig reads the project config, a missing file is an empty config.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	cfg := &ProjectConfig{Languages: filetree.LanguageOverrides{}}
+
+	data, err := os.ReadFile(path) // #nosec
+	if stderrors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading project config: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing project config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
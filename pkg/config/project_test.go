@@ -0,0 +1,79 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/filetree"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    filetree.LanguageOverrides
+		wantErr bool
+	}{
+		{
+			name: "languages",
+			content: `languages:
+  extensions:
+    .tpl: go
+    .hcl.j2: HCL
+  filenames:
+    Tiltfile: Starlark
+  text:
+    - "*.log"
+  exclude:
+    - "*.lock"
+`,
+			want: filetree.LanguageOverrides{
+				Extensions: map[string]string{".tpl": "go", ".hcl.j2": "HCL"},
+				Filenames:  map[string]string{"Tiltfile": "Starlark"},
+				Text:       []string{"*.log"},
+				Exclude:    []string{"*.lock"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    filetree.LanguageOverrides{},
+			wantErr: false,
+		},
+		{
+			name:    "invalid",
+			content: "languages: [",
+			want:    filetree.LanguageOverrides{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			cfg, err := config.LoadProjectConfig(path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Languages)
+		})
+	}
+}
+
+func TestLoadProjectConfig_Missing(t *testing.T) {
+	cfg, err := config.LoadProjectConfig(filepath.Join(t.TempDir(), "config.yaml"))
+
+	require.NoError(t, err)
+	assert.Equal(t, filetree.LanguageOverrides{}, cfg.Languages)
+}
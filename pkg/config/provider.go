@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
 
@@ -186,13 +187,28 @@ func (c *DefaultProvider) NewFromConfigFile() (openai.ClientConfig, error) {
 		return openai.ClientConfig{}, err
 	}
 
-	config := openai.DefaultAzureConfig(cfg.APIKey(), cfg.Endpoint)
-	config.APIVersion = apiVersion
-	config.AzureModelMapperFunc = func(model string) string {
-		return cfg.ModelDeployment
-	}
+	return NewClientConfig(cfg), nil
+}
+
+// NewClientConfig creates the client configuration for the backend selected in the config.
+func NewClientConfig(cfg *Config) openai.ClientConfig {
+	switch cfg.BackendOrDefault() {
+	case BackendOpenAI, BackendOpenAICompatible:
+		config := openai.DefaultConfig(cfg.APIKey())
+		if cfg.Endpoint != "" {
+			config.BaseURL = strings.TrimSuffix(cfg.Endpoint, "/")
+		}
 
-	return config, nil
+		return config
+	default:
+		config := openai.DefaultAzureConfig(cfg.APIKey(), cfg.Endpoint)
+		config.APIVersion = apiVersion
+		config.AzureModelMapperFunc = func(model string) string {
+			return cfg.ModelDeployment
+		}
+
+		return config
+	}
 }
 
 func (c *DefaultProvider) SaveConfig(config *Config) error {
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/cwc/pkg/config"
+)
+
+func TestNewClientConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     string
+		endpoint    string
+		wantAPIType openai.APIType
+		wantBaseURL string
+	}{
+		{
+			name:        "empty backend defaults to azure",
+			backend:     "",
+			endpoint:    "https://example.openai.azure.com/",
+			wantAPIType: openai.APITypeAzure,
+			wantBaseURL: "https://example.openai.azure.com/",
+		},
+		{
+			name:        "openai uses public api without endpoint",
+			backend:     config.BackendOpenAI,
+			endpoint:    "",
+			wantAPIType: openai.APITypeOpenAI,
+			wantBaseURL: "https://api.openai.com/v1",
+		},
+		{
+			name:        "openai compatible uses endpoint as base url",
+			backend:     config.BackendOpenAICompatible,
+			endpoint:    "http://localhost:8080/v1/",
+			wantAPIType: openai.APITypeOpenAI,
+			wantBaseURL: "http://localhost:8080/v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig(tt.endpoint, "gpt-4")
+			cfg.Backend = tt.backend
+
+			clientConfig := config.NewClientConfig(cfg)
+
+			assert.Equal(t, tt.wantAPIType, clientConfig.APIType)
+			assert.Equal(t, tt.wantBaseURL, clientConfig.BaseURL)
+		})
+	}
+}
+
+func TestDefaultValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  string
+		endpoint string
+		apiKey   string
+		wantErr  bool
+	}{
+		{name: "azure requires endpoint", backend: config.BackendAzure, endpoint: "", apiKey: "key", wantErr: true},
+		{name: "azure requires api key", backend: config.BackendAzure, endpoint: "https://x", apiKey: "", wantErr: true},
+		{name: "openai without endpoint", backend: config.BackendOpenAI, endpoint: "", apiKey: "key", wantErr: false},
+		{name: "compatible without api key", backend: config.BackendOpenAICompatible, endpoint: "http://x", wantErr: false},
+		{name: "unknown backend", backend: "bard", endpoint: "https://x", apiKey: "key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig(tt.endpoint, "gpt-4")
+			cfg.Backend = tt.backend
+			cfg.SetAPIKey(tt.apiKey)
+
+			err := config.DefaultValidator(cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,15 +1,29 @@
 package config
 
-import "github.com/intility/cwc/pkg/errors"
+import (
+	"slices"
+	"strings"
+
+	"github.com/intility/cwc/pkg/errors"
+)
 
 func DefaultValidator(cfg *Config) error {
 	var validationErrors []string
 
-	if cfg.APIKey() == "" {
+	backend := cfg.BackendOrDefault()
+
+	if !slices.Contains(Backends(), backend) {
+		validationErrors = append(validationErrors,
+			"backend must be one of: "+strings.Join(Backends(), ", "))
+	}
+
+	// local OpenAI compatible servers usually do not require an api key
+	if cfg.APIKey() == "" && backend != BackendOpenAICompatible {
 		validationErrors = append(validationErrors, "apiKey must be provided and not be empty")
 	}
 
-	if cfg.Endpoint == "" {
+	// the public OpenAI api is used when no endpoint is given
+	if cfg.Endpoint == "" && backend != BackendOpenAI {
 		validationErrors = append(validationErrors, "endpoint must be provided and not be empty")
 	}
 
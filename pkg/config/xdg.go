@@ -33,3 +33,27 @@ func XdgConfigPath() (string, error) {
 
 	return configDir, nil
 }
+
+// helper function to get the XDG state path.
+func XdgStatePath() (string, error) {
+	xdgStateHome := os.Getenv("XDG_STATE_HOME")
+	if xdgStateHome == "" {
+		// XDG_STATE_HOME was not set, use the default "~/.local/state"
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error getting user home directory: %w", err)
+		}
+
+		xdgStateHome = filepath.Join(homeDir, ".local", "state")
+	}
+
+	stateDir := filepath.Join(xdgStateHome, serviceName)
+
+	// Ensure that the state directory exists
+	err := os.MkdirAll(stateDir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("error creating state directory: %w", err)
+	}
+
+	return stateDir, nil
+}
@@ -110,12 +110,20 @@ func IsTemplateNotFoundError(err error) bool {
 	return errors.As(err, &templateNotFoundError)
 }
 
-type SuppressedError struct{}
+// SuppressedError is returned when the error was already reported to the user.
+// Err holds the reported error, if any, to determine the exit status.
+type SuppressedError struct {
+	Err error
+}
 
 func (e SuppressedError) Error() string {
 	return "error suppressed"
 }
 
+func (e SuppressedError) Unwrap() error {
+	return e.Err
+}
+
 type ArgParseError struct {
 	Message string
 }
@@ -123,3 +131,147 @@ type ArgParseError struct {
 func (e ArgParseError) Error() string {
 	return e.Message
 }
+
+type SessionNotFoundError struct {
+	SessionID string
+}
+
+func (e SessionNotFoundError) Error() string {
+	return "session not found: " + e.SessionID
+}
+
+func IsSessionNotFoundError(err error) bool {
+	var sessionNotFoundError SessionNotFoundError
+	return errors.As(err, &sessionNotFoundError)
+}
+
+type TokenBudgetExceededError struct {
+	Tokens int
+	Limit  int
+}
+
+func (e TokenBudgetExceededError) Error() string {
+	return fmt.Sprintf("request of %d tokens exceeds the limit of %d tokens", e.Tokens, e.Limit)
+}
+
+func IsTokenBudgetExceededError(err error) bool {
+	var tokenBudgetExceededError TokenBudgetExceededError
+	return errors.As(err, &tokenBudgetExceededError)
+}
+
+// RetriesExhaustedError is returned when a request still fails after the last retry.
+type RetriesExhaustedError struct {
+	Attempts int
+	Err      error
+}
+
+func (e RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %s", e.Attempts, e.Err)
+}
+
+func (e RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+func IsRetriesExhaustedError(err error) bool {
+	var retriesExhaustedError RetriesExhaustedError
+	return errors.As(err, &retriesExhaustedError)
+}
+
+type UnknownCommandError struct {
+	Name string
+}
+
+func (e UnknownCommandError) Error() string {
+	return "unknown command: " + e.Name
+}
+
+func IsUnknownCommandError(err error) bool {
+	var unknownCommandError UnknownCommandError
+	return errors.As(err, &unknownCommandError)
+}
+
+// HunkConflictError is returned when a hunk of a proposed change does not apply cleanly to the file.
+type HunkConflictError struct {
+	Path string
+	Line int
+}
+
+func (e HunkConflictError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("the hunk at line %d does not apply cleanly to %s", e.Line, e.Path)
+	}
+
+	return "the hunk does not apply cleanly to " + e.Path
+}
+
+func IsHunkConflictError(err error) bool {
+	var hunkConflictError HunkConflictError
+	return errors.As(err, &hunkConflictError)
+}
+
+// AnswerFailedError is returned when the model could not answer, for example because the API failed.
+type AnswerFailedError struct {
+	Err error
+}
+
+func (e AnswerFailedError) Error() string {
+	return "error getting an answer: " + e.Err.Error()
+}
+
+func (e AnswerFailedError) Unwrap() error {
+	return e.Err
+}
+
+func IsAnswerFailedError(err error) bool {
+	var answerFailedError AnswerFailedError
+	return errors.As(err, &answerFailedError)
+}
+
+// SecretsFoundError is returned when secrets are found in the context and sending them is blocked.
+type SecretsFoundError struct {
+	// Secrets describes where each secret was found
+	Secrets []string
+}
+
+func (e SecretsFoundError) Error() string {
+	return fmt.Sprintf("found %d secrets in the context, not sending it: %s",
+		len(e.Secrets), strings.Join(e.Secrets, ", "))
+}
+
+func IsSecretsFoundError(err error) bool {
+	var secretsFoundError SecretsFoundError
+	return errors.As(err, &secretsFoundError)
+}
+
+// Exit statuses of cwc, so that scripts can tell why it failed.
+const (
+	ExitCodeFailure             = 1
+	ExitCodeUsage               = 2
+	ExitCodeTokenBudgetExceeded = 3
+	ExitCodeAnswerFailed        = 4
+	ExitCodeSecretsFound        = 5
+)
+
+// ExitCode returns the exit status for an error.
+func ExitCode(err error) int {
+	var (
+		argParseError         ArgParseError
+		noPromptProvidedError NoPromptProvidedError
+	)
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &argParseError), errors.As(err, &noPromptProvidedError), IsTemplateNotFoundError(err):
+		return ExitCodeUsage
+	case IsTokenBudgetExceededError(err):
+		return ExitCodeTokenBudgetExceeded
+	case IsAnswerFailedError(err):
+		return ExitCodeAnswerFailed
+	case IsSecretsFoundError(err):
+		return ExitCodeSecretsFound
+	default:
+		return ExitCodeFailure
+	}
+}
@@ -0,0 +1,137 @@
+package filetree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// sniffLen is the number of bytes looked at to tell text from binary content, as git does.
+const sniffLen = 8000
+
+// plainText is the type of the text files of no known language.
+const plainText = "text"
+
+// maxSkippedListed is the number of skipped files named in the summary.
+const maxSkippedListed = 3
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// isText reports whether the start of a file looks like text: UTF-8 without NUL bytes, or UTF-16
+// with a byte order mark.
+func isText(head []byte) bool {
+	if bytes.HasPrefix(head, bomUTF16LE) || bytes.HasPrefix(head, bomUTF16BE) {
+		return true
+	}
+
+	head = bytes.TrimPrefix(head, bomUTF8)
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+
+	// the head may end in the middle of a character
+	return utf8.Valid(trimPartialRune(head))
+}
+
+// trimPartialRune drops an incomplete character at the end of the data.
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		start := len(data) - i
+		if utf8.RuneStart(data[start]) {
+			if !utf8.FullRune(data[start:]) {
+				return data[:start]
+			}
+
+			break
+		}
+	}
+
+	return data
+}
+
+// decodeText returns the text as UTF-8 without a byte order mark.
+func decodeText(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return data[len(bomUTF8):]
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return decodeUTF16(data[len(bomUTF16LE):], binary.LittleEndian)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return decodeUTF16(data[len(bomUTF16BE):], binary.BigEndian)
+	default:
+		return data
+	}
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, 0, len(data)/2) //nolint:mnd
+
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order.Uint16(data[i:]))
+	}
+
+	return []byte(string(utf16.Decode(units)))
+}
+
+// readTextFile reads the file when it holds text, a binary file is only read as far as needed to tell.
+// A file forced to text is read whatever its contents.
+func readTextFile(path string, forceText bool) ([]byte, bool, error) {
+	if forceText {
+		data, err := os.ReadFile(path) // #nosec
+		if err != nil {
+			return nil, false, fmt.Errorf("error reading file: %w", err)
+		}
+
+		return decodeText(data), true, nil
+	}
+
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading file: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, false, fmt.Errorf("error reading file: %w", err)
+	}
+
+	head = head[:n]
+	if !isText(head) {
+		return nil, false, nil
+	}
+
+	rest, err := io.ReadAll(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return decodeText(append(head, rest...)), true, nil
+}
+
+// skippedSummary describes the skipped files in one line.
+func skippedSummary(paths []string) string {
+	listed := paths
+	if len(listed) > maxSkippedListed {
+		listed = listed[:maxSkippedListed]
+	}
+
+	summary := fmt.Sprintf("skipped %d binary files: %s", len(paths), strings.Join(listed, ", "))
+	if len(paths) > len(listed) {
+		summary += fmt.Sprintf(" and %d more", len(paths)-len(listed))
+	}
+
+	return summary + "\n"
+}
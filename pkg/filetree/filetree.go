@@ -2,12 +2,14 @@ package filetree
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"slices"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/intility/cwc/pkg/git"
 	pm "github.com/intility/cwc/pkg/pathmatcher"
 	cwcui "github.com/intility/cwc/pkg/ui"
 )
@@ -24,153 +26,255 @@ type File struct {
 	Type string
 }
 
+// maxReadWorkers is the number of files read at the same time.
+const maxReadWorkers = 16
+
 type FileGatherOptions struct {
 	IncludeMatcher pm.PathMatcher
 	ExcludeMatcher pm.PathMatcher
 	PathScopes     []string
+	// Ref is a git revision to read the files from instead of the working tree
+	Ref string
+	// Languages override the languages and file types of the files, when set
+	Languages *LanguageOverrides
 }
 
-func GatherFiles(opts *FileGatherOptions) ([]File, *FileNode, error) { //nolint:funlen,gocognit,cyclop
+// GatherFiles walks the path scopes for the files matching the include matcher and not the exclude
+// matcher, and reads them. Directories matched by the exclude matcher are not walked at all.
+func GatherFiles(opts *FileGatherOptions) ([]File, *FileNode, error) {
+	if opts.Ref != "" {
+		return gatherFilesAtRef(opts)
+	}
+
 	includeMatcher := opts.IncludeMatcher
 	excludeMatcher := opts.ExcludeMatcher
-	pathScopes := opts.PathScopes
-	ui := cwcui.NewUI() //nolint:varnamelen
+	// warnings go to stderr, stdout may hold the answer
+	ui := cwcui.NewUI(cwcui.WithWriter(os.Stderr)) //nolint:varnamelen
 
-	var files []File
-
-	knownLanguage := cachedLanguageChecker()
+	rules, err := newLanguageRules(opts.Languages)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	rootNode := &FileNode{Name: "/", IsDir: true, Children: []*FileNode{}}
+	var files []File
 
-	for _, path := range pathScopes {
-		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	for _, scope := range opts.PathScopes {
+		err := filepath.WalkDir(scope, func(path string, entry fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
 			normalizedPath := filepath.ToSlash(path)
-			if !includeMatcher.Match(normalizedPath) || info.IsDir() || excludeMatcher.Match(normalizedPath) {
-				return nil
-			}
 
-			fileType, ok := knownLanguage(path)
+			if entry.IsDir() {
+				if path != scope && matchDir(excludeMatcher, normalizedPath) {
+					return filepath.SkipDir
+				}
 
-			if !ok {
-				ui.PrintMessage("skipping unknown file type: "+path+"\n", cwcui.MessageTypeWarning)
 				return nil
 			}
 
-			file := &File{
-				Path: path,
-				Type: fileType,
-				Data: []byte{},
+			if !includeMatcher.Match(normalizedPath) || excludeMatcher.Match(normalizedPath) ||
+				rules.excluded(normalizedPath) {
+				return nil
 			}
 
-			codeFile, err := os.OpenFile(path, os.O_RDONLY, 0) // #nosec
-			if err != nil {
-				return fmt.Errorf("error opening codeFile: %w", err)
-			}
+			files = append(files, File{Path: path, Type: "", Data: []byte{}})
 
-			defer func() {
-				err = codeFile.Close()
-				if err != nil {
-					ui.PrintMessage(fmt.Sprintf("error closing codeFile: %s\n", err), cwcui.MessageTypeError)
-				}
-			}()
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error walking the path: %w", err)
+		}
+	}
+
+	files, skipped, err := readFiles(files, rules)
+	if err != nil {
+		return nil, nil, err
+	}
 
-			file.Data, err = os.ReadFile(path) // #nosec
+	if len(skipped) > 0 {
+		ui.PrintMessage(skippedSummary(skipped), cwcui.MessageTypeWarning)
+	}
 
-			if err != nil {
-				return fmt.Errorf("error reading codeFile: %w", err)
-			}
+	rootNode := &FileNode{Name: "/", IsDir: true, Children: []*FileNode{}}
+	for _, file := range files {
+		addToTree(rootNode, file.Path)
+	}
 
-			files = append(files, *file)
+	// Sort the files for consistent output
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
 
-			// Construct the codeFile tree
-			parts := strings.Split(path, string(os.PathSeparator))
-			current := rootNode
+	return files, rootNode, nil
+}
 
-			for _, part := range parts[:len(parts)-1] { // Exclude the last part which is the codeFile itself
-				found := false
+// matchDir reports whether the matcher excludes the directory as a whole.
+func matchDir(matcher pm.PathMatcher, path string) bool {
+	dirMatcher, ok := matcher.(pm.DirMatcher)
 
-				for _, child := range current.Children {
-					if child.Name == part && child.IsDir {
-						current = child
-						found = true
+	return ok && dirMatcher.MatchDir(path)
+}
 
-						break
-					}
-				}
+// readFiles reads the contents of the files with a bounded number of workers. It returns the text
+// files and the paths of the binary files, which are left out.
+func readFiles(files []File, rules *languageRules) ([]File, []string, error) {
+	workers := min(maxReadWorkers, len(files))
+	indexes := make(chan int)
+	errs := make([]error, len(files))
+	binaries := make([]bool, len(files))
+
+	var wg sync.WaitGroup
 
-				if !found {
-					newNode := &FileNode{Name: part, IsDir: true, Children: []*FileNode{}}
-					current.Children = append(current.Children, newNode)
-					current = newNode
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				data, ok, err := readTextFile(files[i].Path, rules.isText(files[i].Path))
+				if err != nil {
+					errs[i] = err
+					continue
 				}
+
+				files[i].Data = data
+				files[i].Type = rules.languageOf(files[i].Path, data)
+				binaries[i] = !ok
 			}
+		}()
+	}
 
-			current.Children = append(current.Children,
-				&FileNode{Name: parts[len(parts)-1], IsDir: false, Children: []*FileNode{}})
+	for i := range files {
+		indexes <- i
+	}
 
-			return nil
-		})
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
 		if err != nil {
-			return nil, nil, fmt.Errorf("error walking the path: %w", err)
+			return nil, nil, err
 		}
 	}
 
-	// Sort the files for consistent output
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Path < files[j].Path
-	})
+	text, skipped := splitBinaries(files, binaries)
 
-	return files, rootNode, nil
+	return text, skipped, nil
 }
 
-type languageCheckerCache struct {
-	cache     map[string]string
-	cacheHits int
-}
+// splitBinaries separates the binary files from the text files.
+func splitBinaries(files []File, binaries []bool) ([]File, []string) {
+	text := make([]File, 0, len(files))
 
-func (l *languageCheckerCache) Get(ext string) (string, bool) {
-	val, ok := l.cache[ext]
-	return val, ok
-}
+	var skipped []string
 
-func (l *languageCheckerCache) Set(ext, lang string) {
-	l.cache[ext] = lang
+	for i, file := range files {
+		if binaries[i] {
+			skipped = append(skipped, file.Path)
+			continue
+		}
+
+		text = append(text, file)
+	}
+
+	return text, skipped
 }
 
-func cachedLanguageChecker() func(string) (string, bool) {
-	cache := &languageCheckerCache{cache: make(map[string]string), cacheHits: 0}
+// gatherFilesAtRef gathers the files from the tree of a git revision, without checking it out.
+func gatherFilesAtRef(opts *FileGatherOptions) ([]File, *FileNode, error) {
+	ui := cwcui.NewUI(cwcui.WithWriter(os.Stderr)) //nolint:varnamelen
+	rootNode := &FileNode{Name: "/", IsDir: true, Children: []*FileNode{}}
+
+	rules, err := newLanguageRules(opts.Languages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	treeFiles, err := git.TreeFiles(opts.Ref, opts.PathScopes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing the files at %s: %w", opts.Ref, err)
+	}
 
-	return func(path string) (string, bool) {
-		if ext, ok := cache.Get(filepath.Ext(path)); ok {
-			cache.cacheHits++
-			return ext, true
+	var (
+		files []File
+		paths []string
+	)
+
+	for _, normalizedPath := range treeFiles {
+		if !opts.IncludeMatcher.Match(normalizedPath) || opts.ExcludeMatcher.Match(normalizedPath) ||
+			rules.excluded(normalizedPath) {
+			continue
 		}
-		// .md should be interpreted as markdown, not lisp
-		if filepath.Ext(path) == ".md" {
-			cache.Set(filepath.Ext(path), "markdown")
-			return "markdown", true
+
+		path := filepath.FromSlash(normalizedPath)
+
+		files = append(files, File{Path: path, Type: "", Data: []byte{}})
+		paths = append(paths, normalizedPath)
+	}
+
+	contents, err := git.ReadFiles(opts.Ref, paths)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading the files at %s: %w", opts.Ref, err)
+	}
+
+	binaries := make([]bool, len(files))
+
+	for i := range files {
+		// decoded like the files in the working tree, a file forced to text whatever its contents
+		if !rules.isText(files[i].Path) {
+			binaries[i] = !isText(contents[i][:min(sniffLen, len(contents[i]))])
 		}
 
-		for _, lang := range languages {
-			if slices.Contains(lang.Extensions, filepath.Ext(path)) {
-				// cache the extension for faster lookup
-				cache.Set(filepath.Ext(path), lang.AceMode)
-				return lang.AceMode, true
-			}
+		files[i].Data = decodeText(contents[i])
+		files[i].Type = rules.languageOf(files[i].Path, files[i].Data)
+	}
+
+	files, skipped := splitBinaries(files, binaries)
+
+	if len(skipped) > 0 {
+		ui.PrintMessage(skippedSummary(skipped), cwcui.MessageTypeWarning)
+	}
+
+	for _, file := range files {
+		addToTree(rootNode, file.Path)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, rootNode, nil
+}
 
-			if slices.Contains(lang.Filenames, filepath.Base(path)) {
-				// cache the filename for faster lookup
-				cache.Set(filepath.Base(path), lang.AceMode)
-				return lang.AceMode, true
+// addToTree adds the file at the path to the tree, creating the directories on the way.
+func addToTree(rootNode *FileNode, path string) {
+	parts := strings.Split(path, string(os.PathSeparator))
+	current := rootNode
+
+	for _, part := range parts[:len(parts)-1] { // Exclude the last part which is the file itself
+		found := false
+
+		for _, child := range current.Children {
+			if child.Name == part && child.IsDir {
+				current = child
+				found = true
+
+				break
 			}
 		}
 
-		return "", false
+		if !found {
+			newNode := &FileNode{Name: part, IsDir: true, Children: []*FileNode{}}
+			current.Children = append(current.Children, newNode)
+			current = newNode
+		}
 	}
+
+	current.Children = append(current.Children,
+		&FileNode{Name: parts[len(parts)-1], IsDir: false, Children: []*FileNode{}})
 }
 
 func GenerateFileTree(node *FileNode, indent string, isLast bool) string {
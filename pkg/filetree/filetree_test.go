@@ -0,0 +1,352 @@
+package filetree_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/git"
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+// recordingMatcher excludes the paths with the prefix and records the files it is asked about.
+type recordingMatcher struct {
+	prefix  string
+	matched []string
+}
+
+func (m *recordingMatcher) Match(path string) bool {
+	m.matched = append(m.matched, path)
+	return strings.HasPrefix(path, m.prefix)
+}
+
+func (m *recordingMatcher) MatchDir(path string) bool {
+	return strings.HasPrefix(path+"/", m.prefix)
+}
+
+func TestGatherFiles(t *testing.T) {
+	dir := t.TempDir()
+	want := map[string]string{}
+
+	for i := range 40 {
+		want[filepath.Join(dir, "pkg", fmt.Sprintf("file%d.go", i))] = fmt.Sprintf("package pkg // %d\n", i)
+	}
+
+	for path, content := range want {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	vendored := filepath.Join(dir, "vendor", "lib", "lib.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(vendored), 0o755))
+	require.NoError(t, os.WriteFile(vendored, []byte("package lib\n"), 0o600))
+
+	include, err := pathmatcher.NewRegexPathMatcher(`\.go$`)
+	require.NoError(t, err)
+
+	exclude := &recordingMatcher{prefix: filepath.ToSlash(filepath.Join(dir, "vendor")) + "/", matched: nil}
+
+	files, root, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: exclude,
+		PathScopes:     []string{dir},
+		Ref:            "",
+	})
+	require.NoError(t, err)
+
+	got := map[string]string{}
+	for _, file := range files {
+		got[file.Path] = string(file.Data)
+	}
+
+	assert.Equal(t, want, got)
+	assert.NotNil(t, root)
+
+	for _, path := range exclude.matched {
+		assert.NotContains(t, path, "/vendor/", "the excluded directory is walked")
+	}
+}
+
+func TestGatherFiles_Content(t *testing.T) {
+	dir := t.TempDir()
+	// a character spanning the end of the bytes looked at to tell text from binary
+	long := strings.Repeat("a", 7999) + "é\n"
+
+	contents := map[string][]byte{
+		"main.go":        []byte("package main\n"),
+		"Dockerfile.dev": []byte("FROM golang\n"),
+		"run":            []byte("#!/bin/sh\necho hi\n"),
+		"long.txt":       []byte(long),
+		"bom.cs":         append([]byte{0xEF, 0xBB, 0xBF}, "class A {}\n"...),
+		"utf16.txt":      {0xFF, 0xFE, 'h', 0, 'i', 0},
+		"image.go":       {0x89, 'P', 'N', 'G', 0, 0, 0, 0x0D},
+		"latin1.txt":     {'c', 'a', 'f', 0xE9, '\n'},
+	}
+
+	for name, data := range contents {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	include, err := pathmatcher.NewRegexPathMatcher(`.*`)
+	require.NoError(t, err)
+
+	files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: pathmatcher.NewCompoundPathMatcher(),
+		PathScopes:     []string{dir},
+		Ref:            "",
+	})
+	require.NoError(t, err)
+
+	got := map[string]filetree.File{}
+	for _, file := range files {
+		got[filepath.Base(file.Path)] = file
+	}
+
+	assert.Len(t, got, 6)
+	assert.NotContains(t, got, "image.go", "a binary with a known extension is included")
+	assert.NotContains(t, got, "latin1.txt", "invalid UTF-8 is included")
+	assert.Equal(t, "go", got["main.go"].Type)
+	assert.Equal(t, "text", got["Dockerfile.dev"].Type)
+	assert.Equal(t, "shell", got["run"].Type)
+	assert.Equal(t, long, string(got["long.txt"].Data))
+	assert.Equal(t, "class A {}\n", string(got["bom.cs"].Data))
+	assert.Equal(t, "hi", string(got["utf16.txt"].Data))
+}
+
+func TestGatherFiles_Language(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "main.go", data: "package main\n", want: "go"},
+		{name: "README.md", data: "# cwc\n", want: "markdown"},
+		{name: "header.h", data: "int f(void);\n", want: "c"},
+		{name: "App.CS", data: "class App {}\n", want: "csharp"},
+		{name: "types.d.ts", data: "export {};\n", want: "typescript"},
+		{name: "Dockerfile", data: "FROM golang\n", want: "dockerfile"},
+		{name: "Makefile", data: "all:\n", want: "makefile"},
+		{name: "deploy", data: "#!/usr/bin/env python3\nprint()\n", want: "python"},
+		{name: "build", data: "#!/bin/bash -e\necho\n", want: "shell"},
+		{name: "serve", data: "#!/usr/bin/env -S deno run\n", want: "typescript"},
+		{name: "task", data: "#!/usr/bin/python3.12\n", want: "python"},
+		{name: "notes", data: "remember the milk\n", want: "text"},
+	}
+
+	dir := t.TempDir()
+
+	for _, tt := range tests {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, tt.name), []byte(tt.data), 0o600))
+	}
+
+	include, err := pathmatcher.NewRegexPathMatcher(`.*`)
+	require.NoError(t, err)
+
+	files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: pathmatcher.NewCompoundPathMatcher(),
+		PathScopes:     []string{dir},
+		Ref:            "",
+	})
+	require.NoError(t, err)
+
+	got := map[string]string{}
+	for _, file := range files {
+		got[filepath.Base(file.Path)] = file.Type
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, got[tt.name])
+		})
+	}
+}
+
+func TestGatherFiles_LanguageOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	contents := map[string][]byte{
+		"main.tpl":            []byte("{{ .Name }}\n"),
+		"vars.hcl.j2":         []byte("name = \"{{ name }}\"\n"),
+		"schema.cue":          []byte("name: string\n"),
+		"Jenkinsfile.release": []byte("pipeline {}\n"),
+		"legacy.txt":          {'c', 'a', 'f', 0xE9, '\n'},
+		"go.sum":              []byte("github.com/x v1.0.0 h1:abc=\n"),
+		"main.go":             []byte("package main\n"),
+	}
+
+	for name, data := range contents {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	include, err := pathmatcher.NewRegexPathMatcher(`.*`)
+	require.NoError(t, err)
+
+	files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: pathmatcher.NewCompoundPathMatcher(),
+		PathScopes:     []string{dir},
+		Ref:            "",
+		Languages: &filetree.LanguageOverrides{
+			Extensions: map[string]string{"tpl": "Go Template", ".hcl.j2": "hcl", ".cue": "cue", ".go": "Text"},
+			Filenames:  map[string]string{"Jenkinsfile.release": "Groovy"},
+			Text:       []string{"legacy.txt"},
+			Exclude:    []string{"*.sum"},
+		},
+	})
+	require.NoError(t, err)
+
+	got := map[string]string{}
+	for _, file := range files {
+		got[filepath.Base(file.Path)] = file.Type
+	}
+
+	assert.Equal(t, map[string]string{
+		"main.tpl":            "go-template",
+		"vars.hcl.j2":         "hcl",
+		"schema.cue":          "cue",
+		"Jenkinsfile.release": "groovy",
+		"legacy.txt":          "text",
+		"main.go":             "text",
+	}, got)
+}
+
+func TestGatherFiles_ForcedTextIsDecoded(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	run := func(args ...string) {
+		_, err := git.Run(args...)
+		require.NoError(t, err)
+	}
+
+	// forced to text, so that the UTF-16 file is not taken for a binary one
+	require.NoError(t, os.WriteFile("bom.txt", append([]byte{0xEF, 0xBB, 0xBF}, "with bom\n"...), 0o600))
+	require.NoError(t, os.WriteFile("utf16.txt", []byte{0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0}, 0o600))
+
+	run("init", "--quiet")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Initial commit")
+
+	include, err := pathmatcher.NewRegexPathMatcher(`.*`)
+	require.NoError(t, err)
+
+	exclude, err := pathmatcher.NewRegexPathMatcher(`^\.git/`)
+	require.NoError(t, err)
+
+	for _, ref := range []string{"", "HEAD"} {
+		files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+			IncludeMatcher: include,
+			ExcludeMatcher: exclude,
+			PathScopes:     []string{"."},
+			Ref:            ref,
+			Languages: &filetree.LanguageOverrides{
+				Extensions: nil,
+				Filenames:  nil,
+				Text:       []string{"*.txt"},
+				Exclude:    nil,
+			},
+		})
+		require.NoError(t, err)
+
+		got := map[string]string{}
+		for _, file := range files {
+			got[filepath.Base(file.Path)] = string(file.Data)
+		}
+
+		assert.Equal(t, map[string]string{"bom.txt": "with bom\n", "utf16.txt": "hi\n"}, got, "ref %q", ref)
+	}
+}
+
+func TestLanguageOverrides_Merge(t *testing.T) {
+	user := filetree.LanguageOverrides{
+		Extensions: map[string]string{".tpl": "go", ".j2": "jinja"},
+		Filenames:  nil,
+		Text:       []string{"*.log"},
+		Exclude:    nil,
+	}
+	project := filetree.LanguageOverrides{
+		Extensions: map[string]string{".tpl": "helm"},
+		Filenames:  map[string]string{"Tiltfile": "Starlark"},
+		Text:       nil,
+		Exclude:    []string{"*.lock"},
+	}
+
+	merged := user.Merge(project)
+
+	assert.Equal(t, map[string]string{".tpl": "helm", ".j2": "jinja"}, merged.Extensions)
+	assert.Equal(t, map[string]string{"Tiltfile": "Starlark"}, merged.Filenames)
+	assert.Equal(t, []string{"*.log"}, merged.Text)
+	assert.Equal(t, []string{"*.lock"}, merged.Exclude)
+	assert.Equal(t, "go", user.Extensions[".tpl"], "the user overrides are changed")
+}
+
+func TestGatherFiles_RegexExcludeDoesNotSkipDirectories(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pkg", "main.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "LICENSE"), []byte("MIT\n"), 0o600))
+
+	include, err := pathmatcher.NewRegexPathMatcher(`.*`)
+	require.NoError(t, err)
+
+	// excludes the files without an extension
+	exclude, err := pathmatcher.NewRegexPathMatcher(`(^|/)[^./]+$`)
+	require.NoError(t, err)
+
+	files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: pathmatcher.NewCompoundPathMatcher(exclude),
+		PathScopes:     []string{dir},
+		Ref:            "",
+		Languages:      nil,
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, path, files[0].Path)
+}
+
+func TestGatherFiles_RegexExcludeSkipsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	vendored := filepath.Join(dir, "vendor", "lib", "lib.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(vendored), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o600))
+	require.NoError(t, os.WriteFile(vendored, []byte("package lib\n"), 0o600))
+
+	// records the files the walk gets to, and includes them all
+	include := &recordingMatcher{prefix: "", matched: nil}
+
+	// the default form of -x vendor/
+	exclude, err := pathmatcher.NewPatternPathMatcher("vendor/")
+	require.NoError(t, err)
+
+	files, _, err := filetree.GatherFiles(&filetree.FileGatherOptions{
+		IncludeMatcher: include,
+		ExcludeMatcher: pathmatcher.NewCompoundPathMatcher(exclude),
+		PathScopes:     []string{dir},
+		Ref:            "",
+		Languages:      nil,
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, path, files[0].Path)
+	assert.Equal(t, []string{filepath.ToSlash(path)}, include.matched, "the excluded directory is walked")
+}
@@ -0,0 +1,95 @@
+package filetree
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// languageOf returns the code fence name of the language of the file, by its filename, the
+// interpreter of its shebang line or its extension. Files of no known language are plain text.
+func languageOf(filePath string, data []byte) string {
+	name, ok := detectLanguage(filepath.Base(filePath), data)
+	if !ok {
+		return plainText
+	}
+
+	return languageFences[name]
+}
+
+func detectLanguage(base string, data []byte) (string, bool) {
+	if name, ok := languagesByFilename[base]; ok {
+		return name, true
+	}
+
+	if name, ok := languageByInterpreter(shebangInterpreter(data)); ok {
+		return name, true
+	}
+
+	return lookupExtension(languagesByExtension, base)
+}
+
+// lookupExtension looks up the extensions of the file name, the longest first so that .d.ts is not
+// taken for .ts, as they are and in lower case.
+func lookupExtension(index map[string]string, base string) (string, bool) {
+	for i := range len(base) {
+		if base[i] != '.' {
+			continue
+		}
+
+		if value, ok := index[base[i:]]; ok {
+			return value, true
+		}
+
+		if value, ok := index[strings.ToLower(base[i:])]; ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// languageByInterpreter looks the interpreter up as it is and without its version, python3.12 as python.
+func languageByInterpreter(interpreter string) (string, bool) {
+	if interpreter == "" {
+		return "", false
+	}
+
+	if name, ok := languagesByInterpreter[interpreter]; ok {
+		return name, true
+	}
+
+	name, ok := languagesByInterpreter[strings.TrimRight(interpreter, "0123456789.")]
+
+	return name, ok
+}
+
+// shebangInterpreter returns the interpreter of the shebang line of a script, such as python3
+// for "#!/usr/bin/env python3" or bash for "#!/bin/bash -e".
+func shebangInterpreter(data []byte) string {
+	if !bytes.HasPrefix(data, []byte("#!")) {
+		return ""
+	}
+
+	line, _, _ := bytes.Cut(data[len("#!"):], []byte("\n"))
+	fields := strings.Fields(string(line))
+
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter != "env" {
+		return interpreter
+	}
+
+	// skip the options and variables of env, as in "#!/usr/bin/env -S deno run"
+	for _, field := range fields[1:] {
+		if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+			return path.Base(field)
+		}
+	}
+
+	return ""
+}
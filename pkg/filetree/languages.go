@@ -9987,3 +9987,2577 @@ var languages = map[string]Language{
 		LanguageID:         421,
 	},
 }
+
+// languagesByExtension maps the extensions to the name of their language.
+var languagesByExtension = map[string]string{
+	".1":                   "Roff Manpage",
+	".1in":                 "Roff",
+	".1m":                  "Roff",
+	".1x":                  "Roff",
+	".2":                   "Roff",
+	".2da":                 "2-Dimensional Array",
+	".3":                   "Roff",
+	".3in":                 "Roff",
+	".3m":                  "Roff",
+	".3p":                  "Roff",
+	".3pm":                 "Roff",
+	".3qt":                 "Roff",
+	".3x":                  "Roff",
+	".4":                   "Roff",
+	".4DForm":              "JSON",
+	".4DProject":           "JSON",
+	".4dm":                 "4D",
+	".4gl":                 "Genero 4gl",
+	".4th":                 "Forth",
+	".5":                   "Roff",
+	".6":                   "Roff",
+	".6pl":                 "Raku",
+	".6pm":                 "Raku",
+	".7":                   "Roff",
+	".8":                   "Roff",
+	".8xk":                 "TI Program",
+	".8xk.txt":             "TI Program",
+	".8xp":                 "TI Program",
+	".8xp.txt":             "TI Program",
+	".9":                   "Roff",
+	".Dsr":                 "Visual Basic 6.0",
+	".JSON-tmLanguage":     "JSON",
+	".OutJob":              "Altium Designer",
+	".PcbDoc":              "Altium Designer",
+	".PrjPCB":              "Altium Designer",
+	".SchDoc":              "Altium Designer",
+	"._coffee":             "CoffeeScript",
+	"._js":                 "JavaScript",
+	"._ls":                 "LiveScript",
+	".a51":                 "Assembly",
+	".abap":                "ABAP",
+	".abnf":                "ABNF",
+	".ada":                 "Ada",
+	".adb":                 "Ada",
+	".adml":                "XML",
+	".admx":                "XML",
+	".ado":                 "Stata",
+	".adoc":                "AsciiDoc",
+	".adp":                 "Tcl",
+	".ads":                 "Ada",
+	".afm":                 "Adobe Font Metrics",
+	".agc":                 "Apollo Guidance Computer",
+	".agda":                "Agda",
+	".ahk":                 "AutoHotkey",
+	".ahkl":                "AutoHotkey",
+	".aidl":                "AIDL",
+	".aj":                  "AspectJ",
+	".al":                  "Perl",
+	".als":                 "Alloy",
+	".ampl":                "AMPL",
+	".angelscript":         "AngelScript",
+	".anim":                "Unity3D Asset",
+	".ant":                 "XML",
+	".antlers.html":        "Antlers",
+	".antlers.php":         "Antlers",
+	".antlers.xml":         "Antlers",
+	".apacheconf":          "ApacheConf",
+	".apib":                "API Blueprint",
+	".apl":                 "APL",
+	".app":                 "Erlang",
+	".app.src":             "Erlang",
+	".applescript":         "AppleScript",
+	".arc":                 "Arc",
+	".arpa":                "DNS Zone",
+	".arr":                 "Pyret",
+	".as":                  "ActionScript",
+	".asax":                "ASP.NET",
+	".asc":                 "AGS Script",
+	".asciidoc":            "AsciiDoc",
+	".ascx":                "ASP.NET",
+	".asd":                 "Common Lisp",
+	".asddls":              "ABAP CDS",
+	".ash":                 "AGS Script",
+	".ashx":                "ASP.NET",
+	".asl":                 "ASL",
+	".asm":                 "Assembly",
+	".asmx":                "ASP.NET",
+	".asn":                 "ASN.1",
+	".asn1":                "ASN.1",
+	".asp":                 "Classic ASP",
+	".aspx":                "ASP.NET",
+	".asset":               "Unity3D Asset",
+	".astro":               "Astro",
+	".asy":                 "Asymptote",
+	".au3":                 "AutoIt",
+	".aug":                 "Augeas",
+	".auk":                 "Awk",
+	".aux":                 "TeX",
+	".avdl":                "Avro IDL",
+	".avsc":                "JSON",
+	".aw":                  "PHP",
+	".awk":                 "Awk",
+	".axaml":               "XML",
+	".axd":                 "ASP.NET",
+	".axi":                 "NetLinx",
+	".axi.erb":             "NetLinx+ERB",
+	".axml":                "XML",
+	".axs":                 "NetLinx",
+	".axs.erb":             "NetLinx+ERB",
+	".b":                   "Brainfuck",
+	".bal":                 "Ballerina",
+	".bas":                 "BASIC",
+	".bash":                "Shell",
+	".bat":                 "Batchfile",
+	".bats":                "Shell",
+	".bb":                  "BitBake",
+	".bbx":                 "TeX",
+	".bdf":                 "Glyph Bitmap Distribution Format",
+	".bdy":                 "PLSQL",
+	".be":                  "Berry",
+	".befunge":             "Befunge",
+	".bf":                  "Beef",
+	".bi":                  "FreeBasic",
+	".bib":                 "BibTeX",
+	".bibtex":              "BibTeX",
+	".bicep":               "Bicep",
+	".bison":               "Bison",
+	".blade":               "Blade",
+	".blade.php":           "Blade",
+	".bmx":                 "BlitzMax",
+	".bones":               "JavaScript",
+	".boo":                 "Boo",
+	".boot":                "Clojure",
+	".bpl":                 "Boogie",
+	".brd":                 "KiCad Legacy Layout",
+	".bro":                 "Zeek",
+	".brs":                 "Brightscript",
+	".bs":                  "Bikeshed",
+	".bsl":                 "1C Enterprise",
+	".bsv":                 "Bluespec",
+	".builder":             "Ruby",
+	".builds":              "XML",
+	".bzl":                 "Starlark",
+	".c":                   "C",
+	".c++":                 "C++",
+	".c++-objdump":         "Cpp-ObjDump",
+	".c++objdump":          "Cpp-ObjDump",
+	".c-objdump":           "C-ObjDump",
+	".cabal":               "Cabal Config",
+	".cairo":               "Cairo",
+	".cake":                "C#",
+	".capnp":               "Cap'n Proto",
+	".cats":                "C",
+	".cbl":                 "COBOL",
+	".cbx":                 "TeX",
+	".cc":                  "C++",
+	".ccp":                 "COBOL",
+	".ccproj":              "XML",
+	".ccxml":               "XML",
+	".cdc":                 "Cadence",
+	".cdf":                 "Mathematica",
+	".cds":                 "CAP CDS",
+	".ceylon":              "Ceylon",
+	".cfc":                 "ColdFusion CFC",
+	".cfg":                 "HAProxy",
+	".cfm":                 "ColdFusion",
+	".cfml":                "ColdFusion",
+	".cgi":                 "Perl",
+	".cginc":               "HLSL",
+	".ch":                  "Charity",
+	".chem":                "Pic",
+	".chpl":                "Chapel",
+	".chs":                 "C2hs Haskell",
+	".cil":                 "CIL",
+	".circom":              "Circom",
+	".cirru":               "Cirru",
+	".cjs":                 "JavaScript",
+	".cjsx":                "CoffeeScript",
+	".ck":                  "ChucK",
+	".cl":                  "Cool",
+	".cl2":                 "Clojure",
+	".clar":                "Clarity",
+	".click":               "Click",
+	".clixml":              "XML",
+	".clj":                 "Clojure",
+	".cljc":                "Clojure",
+	".cljs":                "Clojure",
+	".cljs.hl":             "Clojure",
+	".cljscm":              "Clojure",
+	".cljx":                "Clojure",
+	".clp":                 "CLIPS",
+	".cls":                 "Apex",
+	".clw":                 "Clarion",
+	".cmake":               "CMake",
+	".cmake.in":            "CMake",
+	".cmd":                 "Batchfile",
+	".cmp":                 "Gerber Image",
+	".cnc":                 "G-code",
+	".cnf":                 "INI",
+	".cob":                 "COBOL",
+	".cobol":               "COBOL",
+	".cocci":               "SmPL",
+	".code-snippets":       "JSON with Comments",
+	".code-workspace":      "JSON with Comments",
+	".coffee":              "CoffeeScript",
+	".coffee.md":           "Literate CoffeeScript",
+	".com":                 "DIGITAL Command Language",
+	".command":             "Shell",
+	".conll":               "CoNLL-U",
+	".conllu":              "CoNLL-U",
+	".coq":                 "Coq",
+	".cp":                  "C++",
+	".cpp":                 "C++",
+	".cpp-objdump":         "Cpp-ObjDump",
+	".cppm":                "C++",
+	".cppobjdump":          "Cpp-ObjDump",
+	".cproject":            "XML",
+	".cps":                 "Component Pascal",
+	".cpy":                 "COBOL",
+	".cql":                 "SQL",
+	".cr":                  "Crystal",
+	".crc32":               "Checksums",
+	".creole":              "Creole",
+	".cs":                  "C#",
+	".csc":                 "GSC",
+	".cscfg":               "XML",
+	".csd":                 "Csound Document",
+	".csdef":               "XML",
+	".csh":                 "Tcsh",
+	".cshtml":              "HTML+Razor",
+	".csl":                 "XML",
+	".cson":                "CSON",
+	".csproj":              "XML",
+	".css":                 "CSS",
+	".csv":                 "CSV",
+	".csx":                 "C#",
+	".ct":                  "XML",
+	".ctl":                 "Visual Basic 6.0",
+	".ctp":                 "PHP",
+	".cts":                 "TypeScript",
+	".cu":                  "Cuda",
+	".cue":                 "CUE",
+	".cuh":                 "Cuda",
+	".curry":               "Curry",
+	".cw":                  "Redcode",
+	".cwl":                 "Common Workflow Language",
+	".cxx":                 "C++",
+	".cxx-objdump":         "Cpp-ObjDump",
+	".cy":                  "Cycript",
+	".cyp":                 "Cypher",
+	".cypher":              "Cypher",
+	".d":                   "D",
+	".d-objdump":           "D-ObjDump",
+	".d2":                  "D2",
+	".dae":                 "COLLADA",
+	".darcspatch":          "Darcs Patch",
+	".dart":                "Dart",
+	".dats":                "ATS",
+	".db2":                 "SQLPL",
+	".dcl":                 "Clean",
+	".ddl":                 "SQL",
+	".decls":               "BlitzBasic",
+	".depproj":             "XML",
+	".desktop":             "desktop",
+	".desktop.in":          "desktop",
+	".dfm":                 "Pascal",
+	".dfy":                 "Dafny",
+	".dhall":               "Dhall",
+	".di":                  "D",
+	".diff":                "Diff",
+	".dircolors":           "dircolors",
+	".dita":                "XML",
+	".ditamap":             "XML",
+	".ditaval":             "XML",
+	".djs":                 "Dogescript",
+	".dll.config":          "XML",
+	".dlm":                 "IDL",
+	".dm":                  "DM",
+	".do":                  "Stata",
+	".dockerfile":          "Dockerfile",
+	".dof":                 "INI",
+	".doh":                 "Stata",
+	".dot":                 "Graphviz (DOT)",
+	".dotsettings":         "XML",
+	".dpatch":              "Darcs Patch",
+	".dpr":                 "Pascal",
+	".druby":               "Mirah",
+	".dsc":                 "Debian Package Control File",
+	".dsl":                 "ASL",
+	".dsp":                 "Faust",
+	".dtx":                 "TeX",
+	".duby":                "Mirah",
+	".dwl":                 "DataWeave",
+	".dyalog":              "APL",
+	".dyl":                 "Dylan",
+	".dylan":               "Dylan",
+	".e":                   "E",
+	".eam.fs":              "Formatted",
+	".eb":                  "Easybuild",
+	".ebnf":                "EBNF",
+	".ebuild":              "Gentoo Ebuild",
+	".ec":                  "eC",
+	".ecl":                 "ECL",
+	".eclass":              "Gentoo Eclass",
+	".eclxml":              "ECL",
+	".ecr":                 "HTML+ECR",
+	".ect":                 "EJS",
+	".edc":                 "Edje Data Collection",
+	".edgeql":              "EdgeQL",
+	".editorconfig":        "EditorConfig",
+	".edn":                 "edn",
+	".eex":                 "HTML+EEX",
+	".eh":                  "eC",
+	".ejs":                 "EJS",
+	".ejs.t":               "EJS",
+	".el":                  "Emacs Lisp",
+	".eliom":               "OCaml",
+	".eliomi":              "OCaml",
+	".elm":                 "Elm",
+	".elv":                 "Elvish",
+	".em":                  "EmberScript",
+	".emacs":               "Emacs Lisp",
+	".emacs.desktop":       "Emacs Lisp",
+	".emberscript":         "EmberScript",
+	".eml":                 "E-mail",
+	".env":                 "Dotenv",
+	".epj":                 "Ecere Projects",
+	".eps":                 "PostScript",
+	".epsi":                "PostScript",
+	".eq":                  "EQ",
+	".erb":                 "HTML+ERB",
+	".erb.deface":          "HTML+ERB",
+	".erl":                 "Erlang",
+	".es":                  "JavaScript",
+	".es6":                 "JavaScript",
+	".escript":             "Erlang",
+	".esdl":                "EdgeQL",
+	".ex":                  "Elixir",
+	".exs":                 "Elixir",
+	".eye":                 "Ruby",
+	".f":                   "Filebench WML",
+	".f03":                 "Fortran Free Form",
+	".f08":                 "Fortran Free Form",
+	".f77":                 "Fortran",
+	".f90":                 "Fortran Free Form",
+	".f95":                 "Fortran Free Form",
+	".factor":              "Factor",
+	".fan":                 "Fantom",
+	".fancypack":           "Fancy",
+	".fcgi":                "PHP",
+	".fea":                 "OpenType Feature File",
+	".feature":             "Gherkin",
+	".filters":             "XML",
+	".fish":                "fish",
+	".flex":                "JFlex",
+	".flf":                 "FIGlet Font",
+	".flux":                "FLUX",
+	".fnc":                 "PLSQL",
+	".fnl":                 "Fennel",
+	".for":                 "Formatted",
+	".forth":               "Forth",
+	".fp":                  "GLSL",
+	".fpp":                 "Fortran",
+	".fr":                  "Text",
+	".frag":                "JavaScript",
+	".frg":                 "GLSL",
+	".frm":                 "VBA",
+	".frt":                 "Forth",
+	".fs":                  "F#",
+	".fsh":                 "GLSL",
+	".fshader":             "GLSL",
+	".fsi":                 "F#",
+	".fsproj":              "XML",
+	".fst":                 "F*",
+	".fsti":                "F*",
+	".fsx":                 "F#",
+	".fth":                 "Forth",
+	".ftl":                 "Fluent",
+	".fun":                 "Standard ML",
+	".fut":                 "Futhark",
+	".fx":                  "FLUX",
+	".fxh":                 "HLSL",
+	".fxml":                "XML",
+	".fy":                  "Fancy",
+	".g":                   "G-code",
+	".g4":                  "ANTLR",
+	".gaml":                "GAML",
+	".gap":                 "GAP",
+	".gawk":                "Awk",
+	".gbl":                 "Gerber Image",
+	".gbo":                 "Gerber Image",
+	".gbp":                 "Gerber Image",
+	".gbr":                 "Gerber Image",
+	".gbs":                 "Gerber Image",
+	".gco":                 "G-code",
+	".gcode":               "G-code",
+	".gd":                  "GDScript",
+	".gdb":                 "GDB",
+	".gdbinit":             "GDB",
+	".gdnlib":              "Godot Resource",
+	".gdns":                "Godot Resource",
+	".ged":                 "GEDCOM",
+	".gemspec":             "Ruby",
+	".geo":                 "GLSL",
+	".geojson":             "JSON",
+	".geom":                "GLSL",
+	".gf":                  "Grammatical Framework",
+	".gi":                  "GAP",
+	".gitconfig":           "Git Config",
+	".gitignore":           "Ignore List",
+	".gjs":                 "Glimmer JS",
+	".gko":                 "Gerber Image",
+	".glade":               "XML",
+	".gleam":               "Gleam",
+	".glf":                 "Glyph",
+	".glsl":                "GLSL",
+	".glslf":               "GLSL",
+	".glslv":               "GLSL",
+	".gltf":                "JSON",
+	".glyphs":              "OpenStep Property List",
+	".gmi":                 "Gemini",
+	".gml":                 "XML",
+	".gms":                 "GAMS",
+	".gmx":                 "XML",
+	".gn":                  "GN",
+	".gni":                 "GN",
+	".gnu":                 "Gnuplot",
+	".gnuplot":             "Gnuplot",
+	".go":                  "Go",
+	".god":                 "Ruby",
+	".golo":                "Golo",
+	".gp":                  "Gnuplot",
+	".gpb":                 "Gerber Image",
+	".gpt":                 "Gerber Image",
+	".gql":                 "GraphQL",
+	".grace":               "Grace",
+	".gradle":              "Gradle",
+	".gradle.kts":          "Gradle Kotlin DSL",
+	".graphql":             "GraphQL",
+	".graphqls":            "GraphQL",
+	".groovy":              "Groovy",
+	".grt":                 "Groovy",
+	".grxml":               "XML",
+	".gs":                  "JavaScript",
+	".gsc":                 "GSC",
+	".gsh":                 "GSC",
+	".gshader":             "GLSL",
+	".gsp":                 "Groovy Server Pages",
+	".gst":                 "XML",
+	".gsx":                 "Gosu",
+	".gtl":                 "Gerber Image",
+	".gto":                 "Gerber Image",
+	".gtp":                 "Gerber Image",
+	".gtpl":                "Groovy",
+	".gts":                 "Gerber Image",
+	".gv":                  "Graphviz (DOT)",
+	".gvy":                 "Groovy",
+	".gyp":                 "Python",
+	".gypi":                "Python",
+	".h":                   "C",
+	".h++":                 "C++",
+	".hack":                "Hack",
+	".haml":                "Haml",
+	".haml.deface":         "Haml",
+	".handlebars":          "Handlebars",
+	".har":                 "JSON",
+	".hats":                "ATS",
+	".hb":                  "Harbour",
+	".hbs":                 "Handlebars",
+	".hc":                  "HolyC",
+	".hcl":                 "HCL",
+	".hh":                  "C++",
+	".hhi":                 "Hack",
+	".hic":                 "Clojure",
+	".hlean":               "Lean",
+	".hlsl":                "HLSL",
+	".hlsli":               "HLSL",
+	".hocon":               "HOCON",
+	".hoon":                "hoon",
+	".hpp":                 "C++",
+	".hqf":                 "SQF",
+	".hql":                 "HiveQL",
+	".hrl":                 "Erlang",
+	".hs":                  "Haskell",
+	".hs-boot":             "Haskell",
+	".hsc":                 "Haskell",
+	".hta":                 "HTML",
+	".htm":                 "HTML",
+	".html":                "HTML",
+	".html.heex":           "HTML+EEX",
+	".html.hl":             "HTML",
+	".html.leex":           "HTML+EEX",
+	".http":                "HTTP",
+	".hx":                  "Haxe",
+	".hxml":                "HXML",
+	".hxsl":                "Haxe",
+	".hxx":                 "C++",
+	".hy":                  "Hy",
+	".hzp":                 "XML",
+	".i":                   "Assembly",
+	".i3":                  "Modula-3",
+	".i7x":                 "Inform 7",
+	".ice":                 "JSON",
+	".iced":                "CoffeeScript",
+	".icl":                 "Clean",
+	".idc":                 "C",
+	".idr":                 "Idris",
+	".ig":                  "Modula-3",
+	".ihlp":                "Stata",
+	".ijm":                 "ImageJ Macro",
+	".ijs":                 "J",
+	".ik":                  "Ioke",
+	".ily":                 "LilyPond",
+	".imba":                "Imba",
+	".iml":                 "XML",
+	".inc":                 "PHP",
+	".ini":                 "INI",
+	".ink":                 "Ink",
+	".inl":                 "C++",
+	".ino":                 "C++",
+	".ins":                 "TeX",
+	".intr":                "Dylan",
+	".io":                  "Io",
+	".iol":                 "Jolie",
+	".ipf":                 "IGOR Pro",
+	".ipp":                 "C++",
+	".ipynb":               "Jupyter Notebook",
+	".irclog":              "IRC log",
+	".isl":                 "Inno Setup",
+	".iss":                 "Inno Setup",
+	".iuml":                "PlantUML",
+	".ivy":                 "XML",
+	".ixx":                 "C++",
+	".j":                   "Jasmin",
+	".j2":                  "Jinja",
+	".jade":                "Pug",
+	".jake":                "JavaScript",
+	".janet":               "Janet",
+	".jav":                 "Java",
+	".java":                "Java",
+	".javascript":          "JavaScript",
+	".jbuilder":            "Ruby",
+	".jcl":                 "JCL",
+	".jelly":               "XML",
+	".jflex":               "JFlex",
+	".jinja":               "Jinja",
+	".jinja2":              "Jinja",
+	".jison":               "Jison",
+	".jisonlex":            "Jison Lex",
+	".jl":                  "Julia",
+	".jq":                  "JSONiq",
+	".js":                  "JavaScript",
+	".js.erb":              "JavaScript+ERB",
+	".jsb":                 "JavaScript",
+	".jscad":               "JavaScript",
+	".jsfl":                "JavaScript",
+	".jsh":                 "Java",
+	".jslib":               "JavaScript",
+	".jsm":                 "JavaScript",
+	".json":                "JSON",
+	".json5":               "JSON5",
+	".jsonc":               "JSON with Comments",
+	".jsonl":               "JSON",
+	".jsonld":              "JSONLD",
+	".jsonnet":             "Jsonnet",
+	".jsp":                 "Java Server Pages",
+	".jspre":               "JavaScript",
+	".jsproj":              "XML",
+	".jss":                 "JavaScript",
+	".jst":                 "EJS",
+	".jsx":                 "JavaScript",
+	".kak":                 "KakouneScript",
+	".kicad_mod":           "KiCad Layout",
+	".kicad_pcb":           "KiCad Layout",
+	".kicad_sch":           "KiCad Schematic",
+	".kicad_wks":           "KiCad Layout",
+	".kid":                 "Genshi",
+	".kit":                 "Kit",
+	".kml":                 "XML",
+	".kojo":                "Scala",
+	".kql":                 "Kusto",
+	".krl":                 "KRL",
+	".ks":                  "KerboScript",
+	".ksh":                 "Shell",
+	".ksy":                 "Kaitai Struct",
+	".kt":                  "Kotlin",
+	".ktm":                 "Kotlin",
+	".kts":                 "Kotlin",
+	".kv":                  "kvlang",
+	".l":                   "Lex",
+	".lagda":               "Literate Agda",
+	".lark":                "Lark",
+	".las":                 "Lasso",
+	".lasso":               "Lasso",
+	".lasso8":              "Lasso",
+	".lasso9":              "Lasso",
+	".latte":               "Latte",
+	".launch":              "XML",
+	".lbx":                 "TeX",
+	".ld":                  "Linker Script",
+	".lds":                 "Linker Script",
+	".lean":                "Lean",
+	".lektorproject":       "INI",
+	".less":                "Less",
+	".lex":                 "Lex",
+	".lfe":                 "LFE",
+	".lgt":                 "Logtalk",
+	".lhs":                 "Literate Haskell",
+	".libsonnet":           "Jsonnet",
+	".lid":                 "Dylan",
+	".lidr":                "Idris",
+	".ligo":                "LigoLANG",
+	".linq":                "C#",
+	".liquid":              "Liquid",
+	".lisp":                "Common Lisp",
+	".litcoffee":           "Literate CoffeeScript",
+	".livemd":              "Markdown",
+	".lkml":                "LookML",
+	".ll":                  "LLVM",
+	".lmi":                 "Python",
+	".logtalk":             "Logtalk",
+	".lol":                 "LOLCODE",
+	".lookml":              "LookML",
+	".lpr":                 "Pascal",
+	".ls":                  "LiveScript",
+	".lsl":                 "LSL",
+	".lslp":                "LSL",
+	".lsp":                 "Common Lisp",
+	".ltx":                 "TeX",
+	".lua":                 "Lua",
+	".lvclass":             "LabVIEW",
+	".lvlib":               "LabVIEW",
+	".lvproj":              "LabVIEW",
+	".ly":                  "LilyPond",
+	".m":                   "Objective-C",
+	".m2":                  "Macaulay2",
+	".m3":                  "Modula-3",
+	".m4":                  "M4",
+	".ma":                  "Mathematica",
+	".mak":                 "Makefile",
+	".make":                "Makefile",
+	".makefile":            "Makefile",
+	".mako":                "Mako",
+	".man":                 "Roff",
+	".mao":                 "Mako",
+	".markdown":            "Markdown",
+	".marko":               "Marko",
+	".mask":                "Mask",
+	".mat":                 "Unity3D Asset",
+	".mata":                "Stata",
+	".matah":               "Stata",
+	".mathematica":         "Mathematica",
+	".matlab":              "MATLAB",
+	".mawk":                "Awk",
+	".maxhelp":             "Max",
+	".maxpat":              "Max",
+	".maxproj":             "Max",
+	".mbox":                "E-mail",
+	".mc":                  "Monkey C",
+	".mcfunction":          "mcfunction",
+	".mcmeta":              "JSON",
+	".mcr":                 "MAXScript",
+	".md":                  "Markdown",
+	".md2":                 "Checksums",
+	".md4":                 "Checksums",
+	".md5":                 "Checksums",
+	".mdoc":                "Roff",
+	".mdown":               "Markdown",
+	".mdpolicy":            "XML",
+	".mdwn":                "Markdown",
+	".mdx":                 "MDX",
+	".me":                  "Roff",
+	".mediawiki":           "Wikitext",
+	".mermaid":             "Mermaid",
+	".meta":                "Unity3D Asset",
+	".metal":               "Metal",
+	".mg":                  "Modula-3",
+	".minid":               "MiniD",
+	".mint":                "Mint",
+	".mir":                 "YAML",
+	".mirah":               "Mirah",
+	".mjml":                "XML",
+	".mjs":                 "JavaScript",
+	".mk":                  "Makefile",
+	".mkd":                 "Markdown",
+	".mkdn":                "Markdown",
+	".mkdown":              "Markdown",
+	".mkfile":              "Makefile",
+	".mkii":                "TeX",
+	".mkiv":                "TeX",
+	".mkvi":                "TeX",
+	".ml":                  "OCaml",
+	".ml4":                 "OCaml",
+	".mli":                 "OCaml",
+	".mligo":               "CameLIGO",
+	".mlir":                "MLIR",
+	".mll":                 "OCaml",
+	".mly":                 "OCaml",
+	".mm":                  "XML",
+	".mmd":                 "Mermaid",
+	".mmk":                 "Module Management System",
+	".mms":                 "Module Management System",
+	".mo":                  "Modelica",
+	".mod":                 "XML",
+	".monkey":              "Monkey",
+	".monkey2":             "Monkey",
+	".moo":                 "Moocode",
+	".moon":                "MoonScript",
+	".move":                "Move",
+	".mpl":                 "JetBrains MPS",
+	".mps":                 "JetBrains MPS",
+	".mq4":                 "MQL4",
+	".mq5":                 "MQL5",
+	".mqh":                 "MQL4",
+	".mrc":                 "mIRC Script",
+	".ms":                  "MAXScript",
+	".msd":                 "JetBrains MPS",
+	".mspec":               "Ruby",
+	".mss":                 "CartoCSS",
+	".mt":                  "Mathematica",
+	".mtl":                 "Wavefront Material",
+	".mtml":                "MTML",
+	".mts":                 "TypeScript",
+	".mu":                  "mupad",
+	".mud":                 "ZIL",
+	".muf":                 "MUF",
+	".mumps":               "M",
+	".muse":                "Muse",
+	".mustache":            "Mustache",
+	".mxml":                "XML",
+	".mxt":                 "Max",
+	".mysql":               "SQL",
+	".myt":                 "Myghty",
+	".n":                   "Nemerle",
+	".nanorc":              "nanorc",
+	".nas":                 "Assembly",
+	".nasl":                "NASL",
+	".nasm":                "Assembly",
+	".natvis":              "XML",
+	".nawk":                "Awk",
+	".nb":                  "Text",
+	".nbp":                 "Mathematica",
+	".nc":                  "nesC",
+	".ncl":                 "XML",
+	".ndproj":              "XML",
+	".ne":                  "Nearley",
+	".nearley":             "Nearley",
+	".neon":                "NEON",
+	".nf":                  "Nextflow",
+	".nginx":               "Nginx",
+	".nginxconf":           "Nginx",
+	".ni":                  "Inform 7",
+	".nim":                 "Nim",
+	".nim.cfg":             "Nim",
+	".nimble":              "Nim",
+	".nimrod":              "Nim",
+	".nims":                "Nim",
+	".ninja":               "Ninja",
+	".nit":                 "Nit",
+	".nix":                 "Nix",
+	".njk":                 "Nunjucks",
+	".njs":                 "JavaScript",
+	".nl":                  "NL",
+	".nlogo":               "NetLogo",
+	".no":                  "Text",
+	".nomad":               "HCL",
+	".nproj":               "XML",
+	".nqp":                 "Raku",
+	".nr":                  "Roff",
+	".nse":                 "Lua",
+	".nsh":                 "NSIS",
+	".nsi":                 "NSIS",
+	".nss":                 "NWScript",
+	".nu":                  "Nu",
+	".numpy":               "NumPy",
+	".numpyw":              "NumPy",
+	".numsc":               "NumPy",
+	".nuspec":              "XML",
+	".nut":                 "Squirrel",
+	".ny":                  "Common Lisp",
+	".obj":                 "Wavefront Object",
+	".objdump":             "ObjDump",
+	".odd":                 "XML",
+	".odin":                "Object Data Instance Notation",
+	".ol":                  "Jolie",
+	".omgrofl":             "Omgrofl",
+	".ooc":                 "ooc",
+	".opa":                 "Opa",
+	".opal":                "Opal",
+	".opencl":              "OpenCL",
+	".orc":                 "Csound",
+	".org":                 "Org",
+	".os":                  "1C Enterprise",
+	".osm":                 "XML",
+	".owl":                 "Web Ontology Language",
+	".ox":                  "Ox",
+	".oxh":                 "Ox",
+	".oxo":                 "Ox",
+	".oxygene":             "Oxygene",
+	".oz":                  "Oz",
+	".p":                   "OpenEdge ABL",
+	".p4":                  "P4",
+	".p6":                  "Raku",
+	".p6l":                 "Raku",
+	".p6m":                 "Raku",
+	".p8":                  "Lua",
+	".pac":                 "JavaScript",
+	".pact":                "Pact",
+	".pan":                 "Pan",
+	".parrot":              "Parrot",
+	".pas":                 "Pascal",
+	".pascal":              "Pascal",
+	".pasm":                "Parrot Assembly",
+	".pat":                 "Max",
+	".patch":               "Diff",
+	".pb":                  "PureBasic",
+	".pbi":                 "PureBasic",
+	".pbt":                 "PowerBuilder",
+	".pbtxt":               "Protocol Buffer Text Format",
+	".pck":                 "PLSQL",
+	".pcss":                "PostCSS",
+	".pd":                  "Pure Data",
+	".pd_lua":              "Lua",
+	".pddl":                "PDDL",
+	".pde":                 "Processing",
+	".pegjs":               "PEG.js",
+	".pep":                 "Pep8",
+	".per":                 "Genero per",
+	".perl":                "Perl",
+	".pfa":                 "PostScript",
+	".pgsql":               "PLpgSQL",
+	".ph":                  "Perl",
+	".php":                 "PHP",
+	".php3":                "PHP",
+	".php4":                "PHP",
+	".php5":                "PHP",
+	".phps":                "PHP",
+	".phpt":                "PHP",
+	".phtml":               "HTML+PHP",
+	".pic":                 "Pic",
+	".pig":                 "PigLatin",
+	".pike":                "Pike",
+	".pir":                 "Parrot Internal Representation",
+	".pkb":                 "PLSQL",
+	".pkgproj":             "XML",
+	".pkl":                 "Pickle",
+	".pks":                 "PLSQL",
+	".pl":                  "Perl",
+	".pl6":                 "Raku",
+	".plantuml":            "PlantUML",
+	".plb":                 "PLSQL",
+	".plist":               "OpenStep Property List",
+	".plot":                "Gnuplot",
+	".pls":                 "PLSQL",
+	".plsql":               "PLSQL",
+	".plt":                 "Gnuplot",
+	".pluginspec":          "XML",
+	".plx":                 "Perl",
+	".pm":                  "Perl",
+	".pm6":                 "Raku",
+	".pml":                 "Promela",
+	".pmod":                "Pike",
+	".po":                  "Gettext Catalog",
+	".pod":                 "Pod",
+	".pod6":                "Pod 6",
+	".podsl":               "Common Lisp",
+	".podspec":             "Ruby",
+	".pogo":                "PogoScript",
+	".polar":               "Polar",
+	".pony":                "Pony",
+	".por":                 "Portugol",
+	".postcss":             "PostCSS",
+	".pot":                 "Gettext Catalog",
+	".pov":                 "POV-Ray SDL",
+	".pp":                  "Puppet",
+	".pprx":                "REXX",
+	".praat":               "Praat",
+	".prawn":               "Ruby",
+	".prc":                 "SQL",
+	".prefab":              "Unity3D Asset",
+	".prefs":               "INI",
+	".prg":                 "xBase",
+	".pri":                 "QMake",
+	".prisma":              "Prisma",
+	".pro":                 "IDL",
+	".proj":                "XML",
+	".prolog":              "Prolog",
+	".properties":          "Java Properties",
+	".props":               "XML",
+	".proto":               "Protocol Buffer",
+	".prw":                 "xBase",
+	".ps":                  "PostScript",
+	".ps1":                 "PowerShell",
+	".ps1xml":              "XML",
+	".psc":                 "Papyrus",
+	".psc1":                "XML",
+	".psd1":                "PowerShell",
+	".psgi":                "Perl",
+	".psm1":                "PowerShell",
+	".pt":                  "XML",
+	".pub":                 "Public Key",
+	".pug":                 "Pug",
+	".puml":                "PlantUML",
+	".purs":                "PureScript",
+	".pwn":                 "Pawn",
+	".pxd":                 "Cython",
+	".pxi":                 "Cython",
+	".py":                  "Python",
+	".py3":                 "Python",
+	".pyde":                "Python",
+	".pyi":                 "Python",
+	".pyp":                 "Python",
+	".pyt":                 "Python",
+	".pytb":                "Python traceback",
+	".pyw":                 "Python",
+	".pyx":                 "Cython",
+	".q":                   "HiveQL",
+	".qasm":                "OpenQASM",
+	".qbs":                 "QML",
+	".qhelp":               "XML",
+	".ql":                  "CodeQL",
+	".qll":                 "CodeQL",
+	".qmd":                 "RMarkdown",
+	".qml":                 "QML",
+	".qs":                  "Q#",
+	".r":                   "R",
+	".r2":                  "Rebol",
+	".r3":                  "Rebol",
+	".rabl":                "Ruby",
+	".rake":                "Ruby",
+	".raku":                "Raku",
+	".rakumod":             "Raku",
+	".raml":                "RAML",
+	".raw":                 "Raw token data",
+	".razor":               "HTML+Razor",
+	".rb":                  "Ruby",
+	".rbbas":               "REALbasic",
+	".rbfrm":               "REALbasic",
+	".rbi":                 "Ruby",
+	".rbmnu":               "REALbasic",
+	".rbres":               "REALbasic",
+	".rbs":                 "RBS",
+	".rbtbar":              "REALbasic",
+	".rbuild":              "Ruby",
+	".rbuistate":           "REALbasic",
+	".rbw":                 "Ruby",
+	".rbx":                 "Ruby",
+	".rbxs":                "Lua",
+	".rchit":               "GLSL",
+	".rd":                  "R",
+	".rdf":                 "XML",
+	".rdoc":                "RDoc",
+	".re":                  "C++",
+	".reb":                 "Rebol",
+	".rebol":               "Rebol",
+	".red":                 "Red",
+	".reds":                "Red",
+	".reek":                "YAML",
+	".reg":                 "Windows Registry Entries",
+	".regex":               "Regular Expression",
+	".regexp":              "Regular Expression",
+	".rego":                "Open Policy Agent",
+	".rei":                 "Reason",
+	".religo":              "ReasonLIGO",
+	".res":                 "XML",
+	".rest":                "reStructuredText",
+	".rest.txt":            "reStructuredText",
+	".resx":                "XML",
+	".rex":                 "REXX",
+	".rexx":                "REXX",
+	".rg":                  "Rouge",
+	".rhtml":               "HTML+ERB",
+	".ring":                "Ring",
+	".riot":                "Riot",
+	".rkt":                 "Racket",
+	".rktd":                "Racket",
+	".rktl":                "Racket",
+	".rl":                  "Ragel",
+	".rmd":                 "RMarkdown",
+	".rmiss":               "GLSL",
+	".rnh":                 "RUNOFF",
+	".rno":                 "RUNOFF",
+	".rnw":                 "Sweave",
+	".robot":               "RobotFramework",
+	".rockspec":            "Lua",
+	".roff":                "Roff",
+	".ronn":                "Markdown",
+	".rpgle":               "RPGLE",
+	".rpy":                 "Python",
+	".rq":                  "SPARQL",
+	".rs":                  "XML",
+	".rs.in":               "Rust",
+	".rsc":                 "Rascal",
+	".rsh":                 "RenderScript",
+	".rss":                 "XML",
+	".rst":                 "reStructuredText",
+	".rst.txt":             "reStructuredText",
+	".rsx":                 "R",
+	".rtf":                 "Rich Text Format",
+	".ru":                  "Ruby",
+	".ruby":                "Ruby",
+	".rviz":                "YAML",
+	".s":                   "Unix Assembly",
+	".sage":                "Sage",
+	".sagews":              "Sage",
+	".sas":                 "SAS",
+	".sass":                "Sass",
+	".sats":                "ATS",
+	".sbt":                 "Scala",
+	".sc":                  "Scala",
+	".scad":                "OpenSCAD",
+	".scala":               "Scala",
+	".scaml":               "Scaml",
+	".scd":                 "Markdown",
+	".sce":                 "Scilab",
+	".scenic":              "Scenic",
+	".sch":                 "XML",
+	".sci":                 "Scilab",
+	".scm":                 "Scheme",
+	".sco":                 "Csound Score",
+	".scpt":                "AppleScript",
+	".scrbl":               "Racket",
+	".scss":                "SCSS",
+	".scxml":               "XML",
+	".sdc":                 "Tcl",
+	".sed":                 "sed",
+	".self":                "Self",
+	".service":             "desktop",
+	".sexp":                "Common Lisp",
+	".sfd":                 "Spline Font Database",
+	".sfproj":              "XML",
+	".sfv":                 "Simple File Verification",
+	".sh":                  "Shell",
+	".sh-session":          "ShellSession",
+	".sh.in":               "Shell",
+	".sha1":                "Checksums",
+	".sha2":                "Checksums",
+	".sha224":              "Checksums",
+	".sha256":              "Checksums",
+	".sha256sum":           "Checksums",
+	".sha3":                "Checksums",
+	".sha384":              "Checksums",
+	".sha512":              "Checksums",
+	".shader":              "ShaderLab",
+	".shen":                "Shen",
+	".shproj":              "XML",
+	".sieve":               "Sieve",
+	".sig":                 "Standard ML",
+	".sj":                  "Objective-J",
+	".sjs":                 "JavaScript",
+	".sl":                  "Slash",
+	".sld":                 "Scheme",
+	".slim":                "Slim",
+	".sln":                 "Microsoft Visual Studio Solution",
+	".sls":                 "SaltStack",
+	".sma":                 "Pawn",
+	".smali":               "Smali",
+	".smithy":              "Smithy",
+	".smk":                 "Snakemake",
+	".sml":                 "Standard ML",
+	".smt":                 "SMT",
+	".smt2":                "SMT",
+	".snakefile":           "Snakemake",
+	".snap":                "Jest Snapshot",
+	".snip":                "Vim Snippet",
+	".snippet":             "Vim Snippet",
+	".snippets":            "Vim Snippet",
+	".sol":                 "Solidity",
+	".soy":                 "Closure Templates",
+	".sp":                  "SourcePawn",
+	".sparql":              "SPARQL",
+	".spc":                 "PLSQL",
+	".spec":                "Python",
+	".spin":                "Propeller Spin",
+	".sps":                 "Scheme",
+	".sqf":                 "SQF",
+	".sql":                 "SQL",
+	".sqlrpgle":            "RPGLE",
+	".sra":                 "PowerBuilder",
+	".srdf":                "XML",
+	".srt":                 "SRecode Template",
+	".sru":                 "PowerBuilder",
+	".srw":                 "PowerBuilder",
+	".ss":                  "Scheme",
+	".ssjs":                "JavaScript",
+	".sss":                 "SugarSS",
+	".st":                  "Smalltalk",
+	".stTheme":             "XML Property List",
+	".stan":                "Stan",
+	".star":                "STAR",
+	".sthlp":               "Stata",
+	".stl":                 "STL",
+	".ston":                "STON",
+	".story":               "Gherkin",
+	".storyboard":          "XML",
+	".sty":                 "TeX",
+	".styl":                "Stylus",
+	".sublime-build":       "JSON with Comments",
+	".sublime-commands":    "JSON with Comments",
+	".sublime-completions": "JSON with Comments",
+	".sublime-keymap":      "JSON with Comments",
+	".sublime-macro":       "JSON with Comments",
+	".sublime-menu":        "JSON with Comments",
+	".sublime-mousemap":    "JSON with Comments",
+	".sublime-project":     "JSON with Comments",
+	".sublime-settings":    "JSON with Comments",
+	".sublime-snippet":     "XML",
+	".sublime-syntax":      "YAML",
+	".sublime-theme":       "JSON with Comments",
+	".sublime-workspace":   "JSON with Comments",
+	".sublime_metrics":     "JSON with Comments",
+	".sublime_session":     "JSON with Comments",
+	".sv":                  "SystemVerilog",
+	".svelte":              "Svelte",
+	".svg":                 "SVG",
+	".svh":                 "SystemVerilog",
+	".sw":                  "XML",
+	".swift":               "Swift",
+	".syntax":              "YAML",
+	".t":                   "Perl",
+	".tab":                 "SQL",
+	".tac":                 "Python",
+	".tag":                 "Java Server Pages",
+	".talon":               "Talon",
+	".targets":             "XML",
+	".tcc":                 "C++",
+	".tcl":                 "Tcl",
+	".tcl.in":              "Tcl",
+	".tcsh":                "Tcsh",
+	".te":                  "SELinux Policy",
+	".tea":                 "Tea",
+	".tesc":                "GLSL",
+	".tese":                "GLSL",
+	".tex":                 "TeX",
+	".texi":                "Texinfo",
+	".texinfo":             "Texinfo",
+	".textile":             "Textile",
+	".textproto":           "Protocol Buffer Text Format",
+	".tf":                  "HCL",
+	".tfstate":             "JSON",
+	".tfstate.backup":      "JSON",
+	".tftpl":               "Terraform Template",
+	".tfvars":              "HCL",
+	".thor":                "Ruby",
+	".thrift":              "Thrift",
+	".thy":                 "Isabelle",
+	".tl":                  "Type Language",
+	".tla":                 "TLA",
+	".tlv":                 "TL-Verilog",
+	".tm":                  "Tcl",
+	".tmCommand":           "XML Property List",
+	".tmLanguage":          "XML Property List",
+	".tmPreferences":       "XML Property List",
+	".tmSnippet":           "XML Property List",
+	".tmTheme":             "XML Property List",
+	".tmac":                "Roff",
+	".tml":                 "XML",
+	".tmux":                "Shell",
+	".toc":                 "World of Warcraft Addon Data",
+	".toit":                "Toit",
+	".toml":                "TOML",
+	".tool":                "Shell",
+	".topojson":            "JSON",
+	".tpb":                 "PLSQL",
+	".tpl":                 "Smarty",
+	".tpp":                 "C++",
+	".tps":                 "PLSQL",
+	".tres":                "Godot Resource",
+	".trg":                 "PLSQL",
+	".trigger":             "Apex",
+	".ts":                  "TypeScript",
+	".tscn":                "Godot Resource",
+	".tst":                 "GAP",
+	".tsv":                 "TSV",
+	".tsx":                 "XML",
+	".ttl":                 "Turtle",
+	".tu":                  "Turing",
+	".twig":                "Twig",
+	".txi":                 "Texinfo",
+	".txl":                 "TXL",
+	".txt":                 "Text",
+	".txx":                 "C++",
+	".typ":                 "XML",
+	".uc":                  "UnrealScript",
+	".udf":                 "SQL",
+	".udo":                 "Csound",
+	".ui":                  "XML",
+	".unity":               "Unity3D Asset",
+	".uno":                 "Uno",
+	".upc":                 "Unified Parallel C",
+	".ur":                  "UrWeb",
+	".urdf":                "XML",
+	".url":                 "INI",
+	".urs":                 "UrWeb",
+	".ux":                  "XML",
+	".v":                   "Verilog",
+	".vala":                "Vala",
+	".vapi":                "Vala",
+	".vark":                "Gosu",
+	".vb":                  "Visual Basic .NET",
+	".vba":                 "VBA",
+	".vbhtml":              "Visual Basic .NET",
+	".vbproj":              "XML",
+	".vbs":                 "VBScript",
+	".vcl":                 "VCL",
+	".vcxproj":             "XML",
+	".vdf":                 "Valve Data Format",
+	".veo":                 "Verilog",
+	".vert":                "GLSL",
+	".vh":                  "SystemVerilog",
+	".vhd":                 "VHDL",
+	".vhdl":                "VHDL",
+	".vhf":                 "VHDL",
+	".vhi":                 "VHDL",
+	".vho":                 "VHDL",
+	".vhost":               "ApacheConf",
+	".vhs":                 "VHDL",
+	".vht":                 "VHDL",
+	".vhw":                 "VHDL",
+	".vim":                 "Vim Script",
+	".vimrc":               "Vim Script",
+	".viw":                 "SQL",
+	".vmb":                 "Vim Script",
+	".volt":                "Volt",
+	".vrx":                 "GLSL",
+	".vs":                  "GLSL",
+	".vsh":                 "GLSL",
+	".vshader":             "GLSL",
+	".vsixmanifest":        "XML",
+	".vssettings":          "XML",
+	".vstemplate":          "XML",
+	".vtl":                 "Velocity Template Language",
+	".vtt":                 "WebVTT",
+	".vue":                 "Vue",
+	".vw":                  "PLSQL",
+	".vxml":                "XML",
+	".vy":                  "Vyper",
+	".w":                   "CWeb",
+	".wast":                "WebAssembly",
+	".wat":                 "WebAssembly",
+	".watchr":              "Ruby",
+	".wdl":                 "WDL",
+	".webapp":              "JSON",
+	".webidl":              "WebIDL",
+	".webmanifest":         "JSON",
+	".weechatlog":          "IRC log",
+	".wgsl":                "WGSL",
+	".whiley":              "Whiley",
+	".wiki":                "Wikitext",
+	".wikitext":            "Wikitext",
+	".wisp":                "wisp",
+	".wit":                 "WebAssembly Interface Type",
+	".wixproj":             "XML",
+	".wl":                  "Mathematica",
+	".wlk":                 "Wollok",
+	".wlt":                 "Mathematica",
+	".wlua":                "Lua",
+	".workbook":            "Markdown",
+	".workflow":            "XML",
+	".wren":                "Wren",
+	".ws":                  "Witcher Script",
+	".wsdl":                "XML",
+	".wsf":                 "XML",
+	".wsgi":                "Python",
+	".wxi":                 "XML",
+	".wxl":                 "XML",
+	".wxs":                 "XML",
+	".x":                   "DirectX 3D File",
+	".x10":                 "X10",
+	".x3d":                 "XML",
+	".x68":                 "Motorola 68K Assembly",
+	".xacro":               "XML",
+	".xaml":                "XML",
+	".xbm":                 "X BitMap",
+	".xc":                  "XC",
+	".xdc":                 "Tcl",
+	".xht":                 "HTML",
+	".xhtml":               "HTML",
+	".xi":                  "Logos",
+	".xib":                 "XML",
+	".xlf":                 "XML",
+	".xliff":               "XML",
+	".xm":                  "Logos",
+	".xmi":                 "XML",
+	".xml":                 "XML",
+	".xml.dist":            "XML",
+	".xmp":                 "XML",
+	".xojo_code":           "Xojo",
+	".xojo_menu":           "Xojo",
+	".xojo_report":         "Xojo",
+	".xojo_script":         "Xojo",
+	".xojo_toolbar":        "Xojo",
+	".xojo_window":         "Xojo",
+	".xpl":                 "XProc",
+	".xpm":                 "X PixMap",
+	".xproc":               "XProc",
+	".xproj":               "XML",
+	".xpy":                 "Python",
+	".xq":                  "XQuery",
+	".xql":                 "XQuery",
+	".xqm":                 "XQuery",
+	".xquery":              "XQuery",
+	".xqy":                 "XQuery",
+	".xrl":                 "Erlang",
+	".xs":                  "XS",
+	".xsd":                 "XML",
+	".xsh":                 "Xonsh",
+	".xsjs":                "JavaScript",
+	".xsjslib":             "JavaScript",
+	".xsl":                 "XSLT",
+	".xslt":                "XSLT",
+	".xsp-config":          "XPages",
+	".xsp.metadata":        "XPages",
+	".xspec":               "XML",
+	".xtend":               "Xtend",
+	".xul":                 "XML",
+	".xzap":                "ZAP",
+	".y":                   "Yacc",
+	".yacc":                "Yacc",
+	".yaml":                "YAML",
+	".yaml-tmlanguage":     "YAML",
+	".yaml.sed":            "YAML",
+	".yang":                "YANG",
+	".yap":                 "Prolog",
+	".yar":                 "YARA",
+	".yara":                "YARA",
+	".yasnippet":           "YASnippet",
+	".yml":                 "YAML",
+	".yml.mysql":           "YAML",
+	".yrl":                 "Erlang",
+	".yul":                 "Yul",
+	".yy":                  "JSON",
+	".yyp":                 "JSON",
+	".zap":                 "ZAP",
+	".zcml":                "XML",
+	".zeek":                "Zeek",
+	".zep":                 "Zephir",
+	".zig":                 "Zig",
+	".zil":                 "ZIL",
+	".zimpl":               "Zimpl",
+	".zmpl":                "Zimpl",
+	".zone":                "DNS Zone",
+	".zpl":                 "Zimpl",
+	".zs":                  "ZenScript",
+	".zsh":                 "Shell",
+	".zsh-theme":           "Shell",
+}
+
+// languagesByFilename maps the filenames to the name of their language.
+var languagesByFilename = map[string]string{
+	".Rprofile":                    "R",
+	".XCompose":                    "XCompose",
+	".abbrev_defs":                 "Emacs Lisp",
+	".ackrc":                       "Option List",
+	".all-contributorsrc":          "JSON",
+	".arcconfig":                   "JSON",
+	".atomignore":                  "Ignore List",
+	".auto-changelog":              "JSON",
+	".babelignore":                 "Ignore List",
+	".babelrc":                     "JSON with Comments",
+	".bash_aliases":                "Shell",
+	".bash_functions":              "Shell",
+	".bash_history":                "Shell",
+	".bash_logout":                 "Shell",
+	".bash_profile":                "Shell",
+	".bashrc":                      "Shell",
+	".browserslistrc":              "Browserslist",
+	".bzrignore":                   "Ignore List",
+	".c8rc":                        "JSON",
+	".clang-format":                "YAML",
+	".clang-tidy":                  "YAML",
+	".classpath":                   "XML",
+	".coffeelintignore":            "Ignore List",
+	".coveragerc":                  "INI",
+	".cproject":                    "XML",
+	".cshrc":                       "Shell",
+	".curlrc":                      "cURL Config",
+	".cvsignore":                   "Ignore List",
+	".devcontainer.json":           "JSON with Comments",
+	".dir_colors":                  "dircolors",
+	".dircolors":                   "dircolors",
+	".dockerignore":                "Ignore List",
+	".editorconfig":                "EditorConfig",
+	".eleventyignore":              "Ignore List",
+	".emacs":                       "Emacs Lisp",
+	".emacs.desktop":               "Emacs Lisp",
+	".env":                         "Dotenv",
+	".env.ci":                      "Dotenv",
+	".env.dev":                     "Dotenv",
+	".env.development":             "Dotenv",
+	".env.development.local":       "Dotenv",
+	".env.example":                 "Dotenv",
+	".env.local":                   "Dotenv",
+	".env.prod":                    "Dotenv",
+	".env.production":              "Dotenv",
+	".env.staging":                 "Dotenv",
+	".env.test":                    "Dotenv",
+	".env.testing":                 "Dotenv",
+	".eslintignore":                "Ignore List",
+	".eslintrc.json":               "JSON with Comments",
+	".exrc":                        "Vim Script",
+	".factor-boot-rc":              "Factor",
+	".factor-rc":                   "Factor",
+	".flake8":                      "INI",
+	".flaskenv":                    "Shell",
+	".gclient":                     "Python",
+	".gemrc":                       "YAML",
+	".git-blame-ignore-revs":       "Git Revision List",
+	".gitattributes":               "Git Attributes",
+	".gitconfig":                   "Git Config",
+	".gitignore":                   "Ignore List",
+	".gitmodules":                  "Git Config",
+	".gn":                          "GN",
+	".gnus":                        "Emacs Lisp",
+	".gvimrc":                      "Vim Script",
+	".htaccess":                    "ApacheConf",
+	".htmlhintrc":                  "JSON",
+	".imgbotconfig":                "JSON",
+	".inputrc":                     "Readline Config",
+	".irbrc":                       "Ruby",
+	".jscsrc":                      "JSON with Comments",
+	".jshintrc":                    "JSON with Comments",
+	".jslintrc":                    "JSON with Comments",
+	".kshrc":                       "Shell",
+	".latexmkrc":                   "Perl",
+	".login":                       "Shell",
+	".luacheckrc":                  "Lua",
+	".markdownlintignore":          "Ignore List",
+	".nanorc":                      "nanorc",
+	".nodemonignore":               "Ignore List",
+	".npmignore":                   "Ignore List",
+	".npmrc":                       "NPM Config",
+	".nvimrc":                      "Vim Script",
+	".nycrc":                       "JSON",
+	".php":                         "PHP",
+	".php_cs":                      "PHP",
+	".php_cs.dist":                 "PHP",
+	".prettierignore":              "Ignore List",
+	".profile":                     "Shell",
+	".project":                     "XML",
+	".pryrc":                       "Ruby",
+	".pylintrc":                    "INI",
+	".rspec":                       "Option List",
+	".scalafix.conf":               "HOCON",
+	".scalafmt.conf":               "HOCON",
+	".shellcheckrc":                "ShellCheck Config",
+	".simplecov":                   "Ruby",
+	".spacemacs":                   "Emacs Lisp",
+	".stylelintignore":             "Ignore List",
+	".swcrc":                       "JSON with Comments",
+	".tern-config":                 "JSON",
+	".tern-project":                "JSON",
+	".tm_properties":               "TextMate Properties",
+	".vercelignore":                "Ignore List",
+	".vimrc":                       "Vim Script",
+	".viper":                       "Emacs Lisp",
+	".vscodeignore":                "Ignore List",
+	".watchmanconfig":              "JSON",
+	".wgetrc":                      "Wget Config",
+	".yardopts":                    "Option List",
+	".zlogin":                      "Shell",
+	".zlogout":                     "Shell",
+	".zprofile":                    "Shell",
+	".zshenv":                      "Shell",
+	".zshrc":                       "Shell",
+	"9fs":                          "Shell",
+	"APKBUILD":                     "Alpine Abuild",
+	"Android.bp":                   "Soong",
+	"App.config":                   "XML",
+	"Appraisals":                   "Ruby",
+	"BSDmakefile":                  "Makefile",
+	"BUCK":                         "Starlark",
+	"BUILD":                        "Starlark",
+	"BUILD.bazel":                  "Starlark",
+	"Berksfile":                    "Ruby",
+	"Brewfile":                     "Ruby",
+	"Buildfile":                    "Ruby",
+	"CITATION":                     "Text",
+	"CITATION.cff":                 "YAML",
+	"CITATIONS":                    "Text",
+	"CMakeLists.txt":               "CMake",
+	"CODEOWNERS":                   "CODEOWNERS",
+	"COPYING":                      "Text",
+	"COPYING.regex":                "Text",
+	"COPYRIGHT.regex":              "Text",
+	"Cakefile":                     "CoffeeScript",
+	"Capfile":                      "Ruby",
+	"Cargo.lock":                   "TOML",
+	"Cask":                         "Emacs Lisp",
+	"Containerfile":                "Dockerfile",
+	"DEPS":                         "Python",
+	"DIR_COLORS":                   "dircolors",
+	"Dangerfile":                   "Ruby",
+	"Deliverfile":                  "Ruby",
+	"Dockerfile":                   "Dockerfile",
+	"Earthfile":                    "Earthly",
+	"Emakefile":                    "Erlang",
+	"FONTLOG":                      "Text",
+	"Fakefile":                     "Fancy",
+	"Fastfile":                     "Ruby",
+	"GNUmakefile":                  "Makefile",
+	"Gemfile":                      "Ruby",
+	"Gemfile.lock":                 "Gemfile.lock",
+	"Gopkg.lock":                   "TOML",
+	"Guardfile":                    "Ruby",
+	"HOSTS":                        "Hosts File",
+	"INSTALL":                      "Text",
+	"INSTALL.mysql":                "Text",
+	"JUSTFILE":                     "Just",
+	"Jakefile":                     "JavaScript",
+	"Jarfile":                      "Ruby",
+	"Jenkinsfile":                  "Groovy",
+	"Justfile":                     "Just",
+	"Kbuild":                       "Makefile",
+	"LICENSE":                      "Text",
+	"LICENSE.mysql":                "Text",
+	"Lexer.x":                      "Lex",
+	"MANIFEST.MF":                  "JAR Manifest",
+	"MD5SUMS":                      "Checksums",
+	"MODULE.bazel":                 "Starlark",
+	"Makefile":                     "Makefile",
+	"Makefile.PL":                  "Perl",
+	"Makefile.am":                  "Makefile",
+	"Makefile.boot":                "Makefile",
+	"Makefile.frag":                "Makefile",
+	"Makefile.in":                  "Makefile",
+	"Makefile.inc":                 "Makefile",
+	"Makefile.wat":                 "Makefile",
+	"Mavenfile":                    "Ruby",
+	"Modulefile":                   "Puppet",
+	"NEWS":                         "Text",
+	"Notebook":                     "Jupyter Notebook",
+	"NuGet.config":                 "XML",
+	"Nukefile":                     "Nu",
+	"PKGBUILD":                     "Shell",
+	"Phakefile":                    "PHP",
+	"Pipfile":                      "TOML",
+	"Pipfile.lock":                 "JSON",
+	"Podfile":                      "Ruby",
+	"Procfile":                     "Procfile",
+	"Project.ede":                  "Emacs Lisp",
+	"Puppetfile":                   "Ruby",
+	"README.me":                    "Text",
+	"README.mysql":                 "Text",
+	"README.nss":                   "Text",
+	"ROOT":                         "Isabelle ROOT",
+	"Rakefile":                     "Ruby",
+	"Rexfile":                      "Perl",
+	"SConscript":                   "Python",
+	"SConstruct":                   "Python",
+	"SHA1SUMS":                     "Checksums",
+	"SHA256SUMS":                   "Checksums",
+	"SHA256SUMS.txt":               "Checksums",
+	"SHA512SUMS":                   "Checksums",
+	"Settings.StyleCop":            "XML",
+	"Singularity":                  "Singularity",
+	"Slakefile":                    "LiveScript",
+	"Snakefile":                    "Snakemake",
+	"Snapfile":                     "Ruby",
+	"Steepfile":                    "Ruby",
+	"Thorfile":                     "Ruby",
+	"Tiltfile":                     "Starlark",
+	"Vagrantfile":                  "Ruby",
+	"WORKSPACE":                    "Starlark",
+	"WORKSPACE.bazel":              "Starlark",
+	"Web.Debug.config":             "XML",
+	"Web.Release.config":           "XML",
+	"Web.config":                   "XML",
+	"XCompose":                     "XCompose",
+	"_curlrc":                      "cURL Config",
+	"_dir_colors":                  "dircolors",
+	"_dircolors":                   "dircolors",
+	"_emacs":                       "Emacs Lisp",
+	"_redirects":                   "Redirect Rules",
+	"_vimrc":                       "Vim Script",
+	"abbrev_defs":                  "Emacs Lisp",
+	"ack":                          "Perl",
+	"ackrc":                        "Option List",
+	"ant.xml":                      "Ant Build System",
+	"apache2.conf":                 "ApacheConf",
+	"api-extractor.json":           "JSON with Comments",
+	"bash_aliases":                 "Shell",
+	"bash_logout":                  "Shell",
+	"bash_profile":                 "Shell",
+	"bashrc":                       "Shell",
+	"browserslist":                 "Browserslist",
+	"build.xml":                    "Ant Build System",
+	"buildfile":                    "Ruby",
+	"buildozer.spec":               "INI",
+	"cabal.config":                 "Cabal Config",
+	"cabal.project":                "Cabal Config",
+	"checksums.txt":                "Checksums",
+	"cksums":                       "Checksums",
+	"click.me":                     "Text",
+	"composer.lock":                "JSON",
+	"configure.ac":                 "M4Sugar",
+	"contents.lr":                  "Markdown",
+	"cpanfile":                     "Perl",
+	"cshrc":                        "Shell",
+	"delete.me":                    "Text",
+	"deno.lock":                    "JSON",
+	"descrip.mmk":                  "Module Management System",
+	"descrip.mms":                  "Module Management System",
+	"devcontainer.json":            "JSON with Comments",
+	"dir_colors":                   "dircolors",
+	"encodings.dir":                "X Font Directory Index",
+	"eqnrc":                        "Roff",
+	"expr-dist":                    "R",
+	"file_contexts":                "SELinux Policy",
+	"firestore.rules":              "Cloud Firestore Security Rules",
+	"flake.lock":                   "JSON",
+	"fonts.alias":                  "X Font Directory Index",
+	"fonts.dir":                    "X Font Directory Index",
+	"fonts.scale":                  "X Font Directory Index",
+	"fp-lib-table":                 "KiCad Layout",
+	"genfs_contexts":               "SELinux Policy",
+	"gitignore-global":             "Ignore List",
+	"gitignore_global":             "Ignore List",
+	"glide.lock":                   "YAML",
+	"go.mod":                       "Go Module",
+	"go.sum":                       "Go Checksums",
+	"go.work":                      "Go Workspace",
+	"go.work.sum":                  "Go Checksums",
+	"gradlew":                      "Shell",
+	"gvimrc":                       "Vim Script",
+	"haproxy.cfg":                  "HAProxy",
+	"hosts":                        "Hosts File",
+	"httpd.conf":                   "ApacheConf",
+	"initial_sids":                 "SELinux Policy",
+	"inputrc":                      "Readline Config",
+	"installscript.qs":             "Qt Script",
+	"jsconfig.json":                "JSON with Comments",
+	"justfile":                     "Just",
+	"kakrc":                        "KakouneScript",
+	"keep.me":                      "Text",
+	"kshrc":                        "Shell",
+	"language-configuration.json":  "JSON with Comments",
+	"language-subtag-registry.txt": "Record Jar",
+	"latexmkrc":                    "Perl",
+	"ld.script":                    "Linker Script",
+	"lexer.x":                      "Lex",
+	"login":                        "Shell",
+	"m3makefile":                   "Quake",
+	"m3overrides":                  "Quake",
+	"makefile":                     "Makefile",
+	"makefile.sco":                 "Makefile",
+	"man":                          "Shell",
+	"mcmod.info":                   "JSON",
+	"md5sum.txt":                   "Checksums",
+	"meson.build":                  "Meson",
+	"meson_options.txt":            "Meson",
+	"mix.lock":                     "Elixir",
+	"mkfile":                       "Makefile",
+	"mmn":                          "Roff",
+	"mmt":                          "Roff",
+	"mocha.opts":                   "Option List",
+	"nanorc":                       "nanorc",
+	"nextflow.config":              "Nextflow",
+	"nginx.conf":                   "Nginx",
+	"nim.cfg":                      "Nim",
+	"nvimrc":                       "Vim Script",
+	"owh":                          "Tcl",
+	"package.mask":                 "Text",
+	"package.use.mask":             "Text",
+	"package.use.stable.mask":      "Text",
+	"packages.config":              "XML",
+	"pdm.lock":                     "TOML",
+	"poetry.lock":                  "TOML",
+	"pom.xml":                      "Maven POM",
+	"port_contexts":                "SELinux Policy",
+	"profile":                      "Shell",
+	"project.godot":                "Godot Resource",
+	"pylintrc":                     "INI",
+	"read.me":                      "Text",
+	"readme.1st":                   "Text",
+	"rebar.config":                 "Erlang",
+	"rebar.config.lock":            "Erlang",
+	"rebar.lock":                   "Erlang",
+	"riemann.config":               "Clojure",
+	"robots.txt":                   "robots.txt",
+	"security_classes":             "SELinux Policy",
+	"ssh-config":                   "SSH Config",
+	"ssh_config":                   "SSH Config",
+	"sshconfig":                    "SSH Config",
+	"sshconfig.snip":               "SSH Config",
+	"sshd-config":                  "SSH Config",
+	"sshd_config":                  "SSH Config",
+	"starfield":                    "Tcl",
+	"test.me":                      "Text",
+	"toolchain_installscript.qs":   "Qt Script",
+	"troffrc":                      "Roff",
+	"troffrc-end":                  "Roff",
+	"tsconfig.json":                "JSON with Comments",
+	"tslint.json":                  "JSON with Comments",
+	"use.mask":                     "Text",
+	"use.stable.mask":              "Text",
+	"vimrc":                        "Vim Script",
+	"vlcrc":                        "INI",
+	"wscript":                      "Python",
+	"xcompose":                     "XCompose",
+	"yarn.lock":                    "YAML",
+	"zlogin":                       "Shell",
+	"zlogout":                      "Shell",
+	"zprofile":                     "Shell",
+	"zshenv":                       "Shell",
+	"zshrc":                        "Shell",
+}
+
+// languagesByInterpreter maps the interpreters of shebang lines to the name of their language.
+var languagesByInterpreter = map[string]string{
+	"M2":          "Macaulay2",
+	"RouterOS":    "RouterOS Script",
+	"Rscript":     "R",
+	"aidl":        "AIDL",
+	"apl":         "APL",
+	"aplx":        "APL",
+	"ash":         "Shell",
+	"asy":         "Asymptote",
+	"awk":         "Awk",
+	"bash":        "Shell",
+	"bb":          "Clojure",
+	"bigloo":      "Scheme",
+	"boogie":      "Boogie",
+	"boolector":   "SMT",
+	"ccl":         "Common Lisp",
+	"chakra":      "JavaScript",
+	"chicken":     "Scheme",
+	"clisp":       "Common Lisp",
+	"coffee":      "CoffeeScript",
+	"cperl":       "Perl",
+	"crystal":     "Crystal",
+	"csh":         "Tcsh",
+	"csi":         "Scheme",
+	"cvc4":        "SMT",
+	"cwl-runner":  "Common Workflow Language",
+	"d8":          "JavaScript",
+	"dafny":       "Dafny",
+	"dart":        "Dart",
+	"dash":        "Shell",
+	"deno":        "TypeScript",
+	"dtrace":      "DTrace",
+	"dyalog":      "APL",
+	"ecl":         "Common Lisp",
+	"elixir":      "Elixir",
+	"elvish":      "Elvish",
+	"escript":     "Erlang",
+	"eui":         "Euphoria",
+	"euiw":        "Euphoria",
+	"fennel":      "Fennel",
+	"fish":        "fish",
+	"gawk":        "Awk",
+	"gerbv":       "Gerber Image",
+	"gerbview":    "Gerber Image",
+	"gjs":         "JavaScript",
+	"gn":          "GN",
+	"gnuplot":     "Gnuplot",
+	"gosh":        "Scheme",
+	"groovy":      "Groovy",
+	"gsed":        "sed",
+	"guile":       "Scheme",
+	"hy":          "Hy",
+	"instantfpc":  "Pascal",
+	"io":          "Io",
+	"ioke":        "Ioke",
+	"janet":       "Janet",
+	"jconsole":    "J",
+	"jolie":       "Jolie",
+	"jruby":       "Ruby",
+	"js":          "JavaScript",
+	"julia":       "Julia",
+	"ksh":         "Shell",
+	"lisp":        "Common Lisp",
+	"lsl":         "LSL",
+	"lua":         "Lua",
+	"macruby":     "Ruby",
+	"make":        "Makefile",
+	"makeinfo":    "Texinfo",
+	"mathsat5":    "SMT",
+	"mawk":        "Awk",
+	"minised":     "sed",
+	"mksh":        "Shell",
+	"mmi":         "Mercury",
+	"moon":        "MoonScript",
+	"nawk":        "Awk",
+	"newlisp":     "NewLisp",
+	"nextflow":    "Nextflow",
+	"node":        "JavaScript",
+	"nodejs":      "JavaScript",
+	"nu":          "Nushell",
+	"nush":        "Nu",
+	"ocaml":       "OCaml",
+	"ocamlrun":    "OCaml",
+	"ocamlscript": "OCaml",
+	"openrc-run":  "OpenRC runscript",
+	"opensmt":     "SMT",
+	"osascript":   "AppleScript",
+	"parrot":      "Parrot Assembly",
+	"pdksh":       "Shell",
+	"perl":        "Perl",
+	"perl6":       "Raku",
+	"php":         "PHP",
+	"picolisp":    "PicoLisp",
+	"pike":        "Pike",
+	"pil":         "PicoLisp",
+	"pwsh":        "PowerShell",
+	"py":          "Python",
+	"pypy":        "Python",
+	"pypy3":       "Python",
+	"python":      "Python",
+	"python2":     "Python",
+	"python3":     "Python",
+	"qjs":         "JavaScript",
+	"qmake":       "QMake",
+	"r6rs":        "Scheme",
+	"racket":      "Racket",
+	"rake":        "Ruby",
+	"raku":        "Raku",
+	"rakudo":      "Raku",
+	"rbx":         "Ruby",
+	"rc":          "Shell",
+	"regina":      "REXX",
+	"rexx":        "REXX",
+	"rhino":       "JavaScript",
+	"ruby":        "Ruby",
+	"rune":        "E",
+	"runghc":      "Haskell",
+	"runhaskell":  "Haskell",
+	"runhugs":     "Haskell",
+	"rust-script": "Rust",
+	"sbcl":        "Common Lisp",
+	"scala":       "Scala",
+	"scenic":      "Scenic",
+	"scheme":      "Scheme",
+	"sclang":      "SuperCollider",
+	"scsynth":     "SuperCollider",
+	"sed":         "sed",
+	"sh":          "Shell",
+	"smt-rat":     "SMT",
+	"smtinterpol": "SMT",
+	"ssed":        "sed",
+	"stp":         "SMT",
+	"swipl":       "Prolog",
+	"tcc":         "C",
+	"tclsh":       "Tcl",
+	"tcsh":        "Tcsh",
+	"ts-node":     "TypeScript",
+	"v8":          "JavaScript",
+	"v8-shell":    "JavaScript",
+	"verit":       "SMT",
+	"wish":        "Tcl",
+	"yap":         "Prolog",
+	"yices2":      "SMT",
+	"z3":          "SMT",
+	"zsh":         "Shell",
+}
+
+// languageFences maps the names of the languages to their name in markdown code fences.
+var languageFences = map[string]string{
+	"1C Enterprise":                      "1c-enterprise",
+	"2-Dimensional Array":                "2-dimensional-array",
+	"4D":                                 "4d",
+	"ABAP":                               "abap",
+	"ABAP CDS":                           "abap-cds",
+	"ABNF":                               "abnf",
+	"AGS Script":                         "ags",
+	"AIDL":                               "aidl",
+	"AL":                                 "al",
+	"AMPL":                               "ampl",
+	"ANTLR":                              "antlr",
+	"API Blueprint":                      "api-blueprint",
+	"APL":                                "apl",
+	"ASL":                                "asl",
+	"ASN.1":                              "asn.1",
+	"ASP.NET":                            "aspx",
+	"ATS":                                "ats",
+	"ActionScript":                       "actionscript",
+	"Ada":                                "ada",
+	"Adblock Filter List":                "adb",
+	"Adobe Font Metrics":                 "acfm",
+	"Agda":                               "agda",
+	"Alloy":                              "alloy",
+	"Alpine Abuild":                      "abuild",
+	"Altium Designer":                    "altium",
+	"AngelScript":                        "angelscript",
+	"Ant Build System":                   "ant-build-system",
+	"Antlers":                            "antlers",
+	"ApacheConf":                         "apacheconf",
+	"Apex":                               "apex",
+	"Apollo Guidance Computer":           "apollo-guidance-computer",
+	"AppleScript":                        "applescript",
+	"Arc":                                "arc",
+	"AsciiDoc":                           "asciidoc",
+	"AspectJ":                            "aspectj",
+	"Assembly":                           "assembly",
+	"Astro":                              "astro",
+	"Asymptote":                          "asymptote",
+	"Augeas":                             "augeas",
+	"AutoHotkey":                         "autohotkey",
+	"AutoIt":                             "autoit",
+	"Avro IDL":                           "avro-idl",
+	"Awk":                                "awk",
+	"BASIC":                              "basic",
+	"Ballerina":                          "ballerina",
+	"Batchfile":                          "batchfile",
+	"Beef":                               "beef",
+	"Befunge":                            "befunge",
+	"Berry":                              "berry",
+	"BibTeX":                             "bibtex",
+	"Bicep":                              "bicep",
+	"Bikeshed":                           "bikeshed",
+	"Bison":                              "bison",
+	"BitBake":                            "bitbake",
+	"Blade":                              "blade",
+	"BlitzBasic":                         "blitzbasic",
+	"BlitzMax":                           "blitzmax",
+	"Bluespec":                           "bluespec",
+	"Bluespec BH":                        "bh",
+	"Boo":                                "boo",
+	"Boogie":                             "boogie",
+	"Brainfuck":                          "brainfuck",
+	"BrighterScript":                     "brighterscript",
+	"Brightscript":                       "brightscript",
+	"Browserslist":                       "browserslist",
+	"C":                                  "c",
+	"C#":                                 "csharp",
+	"C++":                                "cpp",
+	"C-ObjDump":                          "c-objdump",
+	"C2hs Haskell":                       "c2hs",
+	"CAP CDS":                            "cds",
+	"CIL":                                "cil",
+	"CLIPS":                              "clips",
+	"CMake":                              "cmake",
+	"COBOL":                              "cobol",
+	"CODEOWNERS":                         "codeowners",
+	"COLLADA":                            "collada",
+	"CSON":                               "cson",
+	"CSS":                                "css",
+	"CSV":                                "csv",
+	"CUE":                                "cue",
+	"CWeb":                               "cweb",
+	"Cabal Config":                       "cabal-config",
+	"Cadence":                            "cadence",
+	"Cairo":                              "cairo",
+	"CameLIGO":                           "cameligo",
+	"Cap'n Proto":                        "cap'n-proto",
+	"CartoCSS":                           "cartocss",
+	"Ceylon":                             "ceylon",
+	"Chapel":                             "chapel",
+	"Charity":                            "charity",
+	"Checksums":                          "checksums",
+	"ChucK":                              "chuck",
+	"Circom":                             "circom",
+	"Cirru":                              "cirru",
+	"Clarion":                            "clarion",
+	"Clarity":                            "clarity",
+	"Classic ASP":                        "asp",
+	"Clean":                              "clean",
+	"Click":                              "click",
+	"Clojure":                            "clojure",
+	"Closure Templates":                  "soy",
+	"Cloud Firestore Security Rules":     "cloud-firestore-security-rules",
+	"CoNLL-U":                            "conll-u",
+	"CodeQL":                             "codeql",
+	"CoffeeScript":                       "coffeescript",
+	"ColdFusion":                         "coldfusion",
+	"ColdFusion CFC":                     "cfc",
+	"Common Lisp":                        "lisp",
+	"Common Workflow Language":           "cwl",
+	"Component Pascal":                   "component-pascal",
+	"Cool":                               "cool",
+	"Coq":                                "coq",
+	"Cpp-ObjDump":                        "cpp-objdump",
+	"Creole":                             "creole",
+	"Crystal":                            "crystal",
+	"Csound":                             "csound",
+	"Csound Document":                    "csound-csd",
+	"Csound Score":                       "csound-sco",
+	"Cuda":                               "cuda",
+	"Cue Sheet":                          "cue-sheet",
+	"Curry":                              "curry",
+	"Cycript":                            "cycript",
+	"Cypher":                             "cypher",
+	"Cython":                             "cython",
+	"D":                                  "d",
+	"D-ObjDump":                          "d-objdump",
+	"D2":                                 "d2",
+	"DIGITAL Command Language":           "dcl",
+	"DM":                                 "dm",
+	"DNS Zone":                           "dns-zone",
+	"DTrace":                             "dtrace",
+	"Dafny":                              "dafny",
+	"Darcs Patch":                        "dpatch",
+	"Dart":                               "dart",
+	"DataWeave":                          "dataweave",
+	"Debian Package Control File":        "debian-package-control-file",
+	"DenizenScript":                      "denizenscript",
+	"Dhall":                              "dhall",
+	"Diff":                               "diff",
+	"DirectX 3D File":                    "directx-3d-file",
+	"Dockerfile":                         "dockerfile",
+	"Dogescript":                         "dogescript",
+	"Dotenv":                             "dotenv",
+	"Dylan":                              "dylan",
+	"E":                                  "e",
+	"E-mail":                             "e-mail",
+	"EBNF":                               "ebnf",
+	"ECL":                                "ecl",
+	"ECLiPSe":                            "eclipse",
+	"EJS":                                "ejs",
+	"EQ":                                 "eq",
+	"Eagle":                              "eagle",
+	"Earthly":                            "earthly",
+	"Easybuild":                          "easybuild",
+	"Ecere Projects":                     "ecere-projects",
+	"Ecmarkup":                           "ecmarkup",
+	"EdgeQL":                             "edgeql",
+	"EditorConfig":                       "editorconfig",
+	"Edje Data Collection":               "edje-data-collection",
+	"Eiffel":                             "eiffel",
+	"Elixir":                             "elixir",
+	"Elm":                                "elm",
+	"Elvish":                             "elvish",
+	"Elvish Transcript":                  "elvish-transcript",
+	"Emacs Lisp":                         "elisp",
+	"EmberScript":                        "emberscript",
+	"Erlang":                             "erlang",
+	"Euphoria":                           "euphoria",
+	"F#":                                 "fsharp",
+	"F*":                                 "fstar",
+	"FIGlet Font":                        "figlet-font",
+	"FLUX":                               "flux",
+	"Factor":                             "factor",
+	"Fancy":                              "fancy",
+	"Fantom":                             "fantom",
+	"Faust":                              "faust",
+	"Fennel":                             "fennel",
+	"Filebench WML":                      "filebench-wml",
+	"Filterscript":                       "filterscript",
+	"Fluent":                             "fluent",
+	"Formatted":                          "formatted",
+	"Forth":                              "forth",
+	"Fortran":                            "fortran",
+	"Fortran Free Form":                  "fortran-free-form",
+	"FreeBasic":                          "freebasic",
+	"FreeMarker":                         "freemarker",
+	"Frege":                              "frege",
+	"Futhark":                            "futhark",
+	"G-code":                             "g-code",
+	"GAML":                               "gaml",
+	"GAMS":                               "gams",
+	"GAP":                                "gap",
+	"GCC Machine Description":            "gcc-machine-description",
+	"GDB":                                "gdb",
+	"GDScript":                           "gdscript",
+	"GEDCOM":                             "gedcom",
+	"GLSL":                               "glsl",
+	"GN":                                 "gn",
+	"GSC":                                "gsc",
+	"Game Maker Language":                "game-maker-language",
+	"Gemfile.lock":                       "gemfile.lock",
+	"Gemini":                             "gemini",
+	"Genero 4gl":                         "genero-4gl",
+	"Genero per":                         "genero-per",
+	"Genie":                              "genie",
+	"Genshi":                             "genshi",
+	"Gentoo Ebuild":                      "gentoo-ebuild",
+	"Gentoo Eclass":                      "gentoo-eclass",
+	"Gerber Image":                       "rs-274x",
+	"Gettext Catalog":                    "pot",
+	"Gherkin":                            "gherkin",
+	"Git Attributes":                     "gitattributes",
+	"Git Config":                         "gitconfig",
+	"Git Revision List":                  "git-revision-list",
+	"Gleam":                              "gleam",
+	"Glimmer JS":                         "glimmer-js",
+	"Glyph":                              "glyph",
+	"Glyph Bitmap Distribution Format":   "glyph-bitmap-distribution-format",
+	"Gnuplot":                            "gnuplot",
+	"Go":                                 "go",
+	"Go Checksums":                       "go-checksums",
+	"Go Module":                          "go-module",
+	"Go Workspace":                       "go-workspace",
+	"Godot Resource":                     "godot-resource",
+	"Golo":                               "golo",
+	"Gosu":                               "gosu",
+	"Grace":                              "grace",
+	"Gradle":                             "gradle",
+	"Gradle Kotlin DSL":                  "gradle-kotlin-dsl",
+	"Grammatical Framework":              "gf",
+	"Graph Modeling Language":            "graph-modeling-language",
+	"GraphQL":                            "graphql",
+	"Graphviz (DOT)":                     "graphviz-(dot)",
+	"Groovy":                             "groovy",
+	"Groovy Server Pages":                "gsp",
+	"HAProxy":                            "haproxy",
+	"HCL":                                "hcl",
+	"HLSL":                               "hlsl",
+	"HOCON":                              "hocon",
+	"HTML":                               "html",
+	"HTML+ECR":                           "ecr",
+	"HTML+EEX":                           "eex",
+	"HTML+ERB":                           "erb",
+	"HTML+PHP":                           "html+php",
+	"HTML+Razor":                         "razor",
+	"HTTP":                               "http",
+	"HXML":                               "hxml",
+	"Hack":                               "hack",
+	"Haml":                               "haml",
+	"Handlebars":                         "handlebars",
+	"Harbour":                            "harbour",
+	"Haskell":                            "haskell",
+	"Haxe":                               "haxe",
+	"HiveQL":                             "hiveql",
+	"HolyC":                              "holyc",
+	"Hosts File":                         "hosts",
+	"Hy":                                 "hy",
+	"HyPhy":                              "hyphy",
+	"IDL":                                "idl",
+	"IGOR Pro":                           "igor",
+	"INI":                                "ini",
+	"IRC log":                            "irc",
+	"Idris":                              "idris",
+	"Ignore List":                        "ignore",
+	"ImageJ Macro":                       "ijm",
+	"Imba":                               "imba",
+	"Inform 7":                           "i7",
+	"Ink":                                "ink",
+	"Inno Setup":                         "inno-setup",
+	"Io":                                 "io",
+	"Ioke":                               "ioke",
+	"Isabelle":                           "isabelle",
+	"Isabelle ROOT":                      "isabelle-root",
+	"J":                                  "j",
+	"JAR Manifest":                       "jar-manifest",
+	"JCL":                                "jcl",
+	"JFlex":                              "jflex",
+	"JSON":                               "json",
+	"JSON with Comments":                 "jsonc",
+	"JSON5":                              "json5",
+	"JSONLD":                             "jsonld",
+	"JSONiq":                             "jsoniq",
+	"Janet":                              "janet",
+	"Jasmin":                             "jasmin",
+	"Java":                               "java",
+	"Java Properties":                    "java-properties",
+	"Java Server Pages":                  "jsp",
+	"JavaScript":                         "javascript",
+	"JavaScript+ERB":                     "javascript+erb",
+	"Jest Snapshot":                      "jest-snapshot",
+	"JetBrains MPS":                      "mps",
+	"Jinja":                              "jinja",
+	"Jison":                              "jison",
+	"Jison Lex":                          "jison-lex",
+	"Jolie":                              "jolie",
+	"Jsonnet":                            "jsonnet",
+	"Julia":                              "julia",
+	"Jupyter Notebook":                   "jupyter-notebook",
+	"Just":                               "just",
+	"KRL":                                "krl",
+	"Kaitai Struct":                      "ksy",
+	"KakouneScript":                      "kakounescript",
+	"KerboScript":                        "kerboscript",
+	"KiCad Layout":                       "pcbnew",
+	"KiCad Legacy Layout":                "kicad-legacy-layout",
+	"KiCad Schematic":                    "kicad-schematic",
+	"Kickstart":                          "kickstart",
+	"Kit":                                "kit",
+	"Kotlin":                             "kotlin",
+	"Kusto":                              "kusto",
+	"LFE":                                "lfe",
+	"LLVM":                               "llvm",
+	"LOLCODE":                            "lolcode",
+	"LSL":                                "lsl",
+	"LTspice Symbol":                     "ltspice-symbol",
+	"LabVIEW":                            "labview",
+	"Lark":                               "lark",
+	"Lasso":                              "lasso",
+	"Latte":                              "latte",
+	"Lean":                               "lean",
+	"Lean 4":                             "lean-4",
+	"Less":                               "less",
+	"Lex":                                "lex",
+	"LigoLANG":                           "ligolang",
+	"LilyPond":                           "lilypond",
+	"Limbo":                              "limbo",
+	"Linker Script":                      "linker-script",
+	"Linux Kernel Module":                "linux-kernel-module",
+	"Liquid":                             "liquid",
+	"Literate Agda":                      "literate-agda",
+	"Literate CoffeeScript":              "litcoffee",
+	"Literate Haskell":                   "lhaskell",
+	"LiveScript":                         "livescript",
+	"Logos":                              "logos",
+	"Logtalk":                            "logtalk",
+	"LookML":                             "lookml",
+	"LoomScript":                         "loomscript",
+	"Lua":                                "lua",
+	"M":                                  "m",
+	"M4":                                 "m4",
+	"M4Sugar":                            "m4sugar",
+	"MATLAB":                             "matlab",
+	"MAXScript":                          "maxscript",
+	"MDX":                                "mdx",
+	"MLIR":                               "mlir",
+	"MQL4":                               "mql4",
+	"MQL5":                               "mql5",
+	"MTML":                               "mtml",
+	"MUF":                                "muf",
+	"Macaulay2":                          "macaulay2",
+	"Makefile":                           "makefile",
+	"Mako":                               "mako",
+	"Markdown":                           "markdown",
+	"Marko":                              "marko",
+	"Mask":                               "mask",
+	"Mathematica":                        "mathematica",
+	"Maven POM":                          "maven-pom",
+	"Max":                                "max",
+	"Mercury":                            "mercury",
+	"Mermaid":                            "mermaid",
+	"Meson":                              "meson",
+	"Metal":                              "metal",
+	"Microsoft Developer Studio Project": "microsoft-developer-studio-project",
+	"Microsoft Visual Studio Solution":   "microsoft-visual-studio-solution",
+	"MiniD":                              "minid",
+	"MiniYAML":                           "miniyaml",
+	"Mint":                               "mint",
+	"Mirah":                              "mirah",
+	"Modelica":                           "modelica",
+	"Modula-2":                           "modula-2",
+	"Modula-3":                           "modula-3",
+	"Module Management System":           "module-management-system",
+	"Monkey":                             "monkey",
+	"Monkey C":                           "monkey-c",
+	"Moocode":                            "moocode",
+	"MoonScript":                         "moonscript",
+	"Motoko":                             "motoko",
+	"Motorola 68K Assembly":              "m68k",
+	"Move":                               "move",
+	"Muse":                               "muse",
+	"Mustache":                           "mustache",
+	"Myghty":                             "myghty",
+	"NASL":                               "nasl",
+	"NCL":                                "ncl",
+	"NEON":                               "neon",
+	"NL":                                 "nl",
+	"NPM Config":                         "npmrc",
+	"NSIS":                               "nsis",
+	"NWScript":                           "nwscript",
+	"Nasal":                              "nasal",
+	"Nearley":                            "nearley",
+	"Nemerle":                            "nemerle",
+	"NetLinx":                            "netlinx",
+	"NetLinx+ERB":                        "netlinx+erb",
+	"NetLogo":                            "netlogo",
+	"NewLisp":                            "newlisp",
+	"Nextflow":                           "nextflow",
+	"Nginx":                              "nginx",
+	"Nim":                                "nim",
+	"Ninja":                              "ninja",
+	"Nit":                                "nit",
+	"Nix":                                "nix",
+	"Nu":                                 "nu",
+	"NumPy":                              "numpy",
+	"Nunjucks":                           "nunjucks",
+	"Nushell":                            "nushell",
+	"OASv2-json":                         "oasv2-json",
+	"OASv2-yaml":                         "oasv2-yaml",
+	"OASv3-json":                         "oasv3-json",
+	"OASv3-yaml":                         "oasv3-yaml",
+	"OCaml":                              "ocaml",
+	"ObjDump":                            "objdump",
+	"Object Data Instance Notation":      "object-data-instance-notation",
+	"ObjectScript":                       "objectscript",
+	"Objective-C":                        "objective-c",
+	"Objective-C++":                      "objective-c++",
+	"Objective-J":                        "objective-j",
+	"Odin":                               "odin",
+	"Omgrofl":                            "omgrofl",
+	"Opa":                                "opa",
+	"Opal":                               "opal",
+	"Open Policy Agent":                  "open-policy-agent",
+	"OpenAPI Specification v2":           "oasv2",
+	"OpenAPI Specification v3":           "oasv3",
+	"OpenCL":                             "opencl",
+	"OpenEdge ABL":                       "progress",
+	"OpenQASM":                           "openqasm",
+	"OpenRC runscript":                   "openrc",
+	"OpenSCAD":                           "openscad",
+	"OpenStep Property List":             "openstep-property-list",
+	"OpenType Feature File":              "opentype-feature-file",
+	"Option List":                        "opts",
+	"Org":                                "org",
+	"Ox":                                 "ox",
+	"Oxygene":                            "oxygene",
+	"Oz":                                 "oz",
+	"P4":                                 "p4",
+	"PDDL":                               "pddl",
+	"PEG.js":                             "peg.js",
+	"PHP":                                "php",
+	"PLSQL":                              "plsql",
+	"PLpgSQL":                            "plpgsql",
+	"POV-Ray SDL":                        "pov-ray",
+	"Pact":                               "pact",
+	"Pan":                                "pan",
+	"Papyrus":                            "papyrus",
+	"Parrot":                             "parrot",
+	"Parrot Assembly":                    "pasm",
+	"Parrot Internal Representation":     "pir",
+	"Pascal":                             "pascal",
+	"Pawn":                               "pawn",
+	"Pep8":                               "pep8",
+	"Perl":                               "perl",
+	"Pic":                                "pic",
+	"Pickle":                             "pickle",
+	"PicoLisp":                           "picolisp",
+	"PigLatin":                           "piglatin",
+	"Pike":                               "pike",
+	"PlantUML":                           "plantuml",
+	"Pod":                                "pod",
+	"Pod 6":                              "pod-6",
+	"PogoScript":                         "pogoscript",
+	"Polar":                              "polar",
+	"Pony":                               "pony",
+	"Portugol":                           "portugol",
+	"PostCSS":                            "postcss",
+	"PostScript":                         "postscript",
+	"PowerBuilder":                       "powerbuilder",
+	"PowerShell":                         "powershell",
+	"Praat":                              "praat",
+	"Prisma":                             "prisma",
+	"Processing":                         "processing",
+	"Procfile":                           "procfile",
+	"Proguard":                           "proguard",
+	"Prolog":                             "prolog",
+	"Promela":                            "promela",
+	"Propeller Spin":                     "propeller-spin",
+	"Protocol Buffer":                    "proto",
+	"Protocol Buffer Text Format":        "protocol-buffer-text-format",
+	"Public Key":                         "public-key",
+	"Pug":                                "pug",
+	"Puppet":                             "puppet",
+	"Pure Data":                          "pure-data",
+	"PureBasic":                          "purebasic",
+	"PureScript":                         "purescript",
+	"Pyret":                              "pyret",
+	"Python":                             "python",
+	"Python console":                     "pycon",
+	"Python traceback":                   "python-traceback",
+	"Q#":                                 "qsharp",
+	"QML":                                "qml",
+	"QMake":                              "qmake",
+	"Qt Script":                          "qt-script",
+	"Quake":                              "quake",
+	"R":                                  "r",
+	"RAML":                               "raml",
+	"RBS":                                "rbs",
+	"RDoc":                               "rdoc",
+	"REALbasic":                          "realbasic",
+	"REXX":                               "rexx",
+	"RMarkdown":                          "rmarkdown",
+	"RPC":                                "rpc",
+	"RPGLE":                              "rpgle",
+	"RPM Spec":                           "specfile",
+	"RUNOFF":                             "runoff",
+	"Racket":                             "racket",
+	"Ragel":                              "ragel",
+	"Raku":                               "raku",
+	"Rascal":                             "rascal",
+	"Raw token data":                     "raw",
+	"ReScript":                           "rescript",
+	"Readline Config":                    "inputrc",
+	"Reason":                             "reason",
+	"ReasonLIGO":                         "reasonligo",
+	"Rebol":                              "rebol",
+	"Record Jar":                         "record-jar",
+	"Red":                                "red",
+	"Redcode":                            "redcode",
+	"Redirect Rules":                     "redirects",
+	"Regular Expression":                 "regexp",
+	"Ren'Py":                             "renpy",
+	"RenderScript":                       "renderscript",
+	"Rez":                                "rez",
+	"Rich Text Format":                   "rich-text-format",
+	"Ring":                               "ring",
+	"Riot":                               "riot",
+	"RobotFramework":                     "robotframework",
+	"Roff":                               "roff",
+	"Roff Manpage":                       "roff-manpage",
+	"Rouge":                              "rouge",
+	"RouterOS Script":                    "routeros-script",
+	"Ruby":                               "ruby",
+	"Rust":                               "rust",
+	"SAS":                                "sas",
+	"SCSS":                               "scss",
+	"SELinux Policy":                     "sepolicy",
+	"SMT":                                "smt",
+	"SPARQL":                             "sparql",
+	"SQF":                                "sqf",
+	"SQL":                                "sql",
+	"SQLPL":                              "sqlpl",
+	"SRecode Template":                   "srecode-template",
+	"SSH Config":                         "ssh-config",
+	"STAR":                               "star",
+	"STL":                                "stl",
+	"STON":                               "ston",
+	"SVG":                                "svg",
+	"SWIG":                               "swig",
+	"Sage":                               "sage",
+	"SaltStack":                          "saltstack",
+	"Sass":                               "sass",
+	"Scala":                              "scala",
+	"Scaml":                              "scaml",
+	"Scenic":                             "scenic",
+	"Scheme":                             "scheme",
+	"Scilab":                             "scilab",
+	"Self":                               "self",
+	"ShaderLab":                          "shaderlab",
+	"Shell":                              "shell",
+	"ShellCheck Config":                  "shellcheckrc",
+	"ShellSession":                       "shellsession",
+	"Shen":                               "shen",
+	"Sieve":                              "sieve",
+	"Simple File Verification":           "sfv",
+	"Singularity":                        "singularity",
+	"Slash":                              "slash",
+	"Slice":                              "slice",
+	"Slim":                               "slim",
+	"SmPL":                               "smpl",
+	"Smali":                              "smali",
+	"Smalltalk":                          "smalltalk",
+	"Smarty":                             "smarty",
+	"Smithy":                             "smithy",
+	"Snakemake":                          "snakemake",
+	"Solidity":                           "solidity",
+	"Soong":                              "soong",
+	"SourcePawn":                         "sourcepawn",
+	"Spline Font Database":               "spline-font-database",
+	"Squirrel":                           "squirrel",
+	"Stan":                               "stan",
+	"Standard ML":                        "sml",
+	"Starlark":                           "starlark",
+	"Stata":                              "stata",
+	"StringTemplate":                     "stringtemplate",
+	"Stylus":                             "stylus",
+	"SubRip Text":                        "subrip-text",
+	"SugarSS":                            "sugarss",
+	"SuperCollider":                      "supercollider",
+	"Svelte":                             "svelte",
+	"Sway":                               "sway",
+	"Sweave":                             "sweave",
+	"Swift":                              "swift",
+	"SystemVerilog":                      "systemverilog",
+	"TI Program":                         "ti-program",
+	"TL-Verilog":                         "tl-verilog",
+	"TLA":                                "tla",
+	"TOML":                               "toml",
+	"TSQL":                               "tsql",
+	"TSV":                                "tsv",
+	"TSX":                                "tsx",
+	"TXL":                                "txl",
+	"Talon":                              "talon",
+	"Tcl":                                "tcl",
+	"Tcsh":                               "tcsh",
+	"TeX":                                "tex",
+	"Tea":                                "tea",
+	"Terra":                              "terra",
+	"Terraform Template":                 "terraform-template",
+	"Texinfo":                            "texinfo",
+	"Text":                               "text",
+	"TextMate Properties":                "tm-properties",
+	"Textile":                            "textile",
+	"Thrift":                             "thrift",
+	"Toit":                               "toit",
+	"Turing":                             "turing",
+	"Turtle":                             "turtle",
+	"Twig":                               "twig",
+	"Type Language":                      "tl",
+	"TypeScript":                         "typescript",
+	"Typst":                              "typst",
+	"Unified Parallel C":                 "unified-parallel-c",
+	"Unity3D Asset":                      "unity3d-asset",
+	"Unix Assembly":                      "gas",
+	"Uno":                                "uno",
+	"UnrealScript":                       "unrealscript",
+	"UrWeb":                              "urweb",
+	"V":                                  "v",
+	"VBA":                                "vba",
+	"VBScript":                           "vbscript",
+	"VCL":                                "vcl",
+	"VHDL":                               "vhdl",
+	"Vala":                               "vala",
+	"Valve Data Format":                  "keyvalues",
+	"Velocity Template Language":         "vtl",
+	"Verilog":                            "verilog",
+	"Vim Help File":                      "help",
+	"Vim Script":                         "vim",
+	"Vim Snippet":                        "vim-snippet",
+	"Visual Basic .NET":                  "vbnet",
+	"Visual Basic 6.0":                   "vb6",
+	"Volt":                               "volt",
+	"Vue":                                "vue",
+	"Vyper":                              "vyper",
+	"WDL":                                "wdl",
+	"WGSL":                               "wgsl",
+	"Wavefront Material":                 "wavefront-material",
+	"Wavefront Object":                   "wavefront-object",
+	"Web Ontology Language":              "web-ontology-language",
+	"WebAssembly":                        "webassembly",
+	"WebAssembly Interface Type":         "wit",
+	"WebIDL":                             "webidl",
+	"WebVTT":                             "webvtt",
+	"Wget Config":                        "wgetrc",
+	"Whiley":                             "whiley",
+	"Wikitext":                           "wikitext",
+	"Win32 Message File":                 "win32-message-file",
+	"Windows Registry Entries":           "windows-registry-entries",
+	"Witcher Script":                     "witcher-script",
+	"Wollok":                             "wollok",
+	"World of Warcraft Addon Data":       "world-of-warcraft-addon-data",
+	"Wren":                               "wren",
+	"X BitMap":                           "xbm",
+	"X Font Directory Index":             "x-font-directory-index",
+	"X PixMap":                           "xpm",
+	"X10":                                "x10",
+	"XC":                                 "xc",
+	"XCompose":                           "xcompose",
+	"XML":                                "xml",
+	"XML Property List":                  "xml-property-list",
+	"XPages":                             "xpages",
+	"XProc":                              "xproc",
+	"XQuery":                             "xquery",
+	"XS":                                 "xs",
+	"XSLT":                               "xslt",
+	"Xojo":                               "xojo",
+	"Xonsh":                              "xonsh",
+	"Xtend":                              "xtend",
+	"YAML":                               "yaml",
+	"YANG":                               "yang",
+	"YARA":                               "yara",
+	"YASnippet":                          "yasnippet",
+	"Yacc":                               "yacc",
+	"Yul":                                "yul",
+	"ZAP":                                "zap",
+	"ZIL":                                "zil",
+	"Zeek":                               "zeek",
+	"ZenScript":                          "zenscript",
+	"Zephir":                             "zephir",
+	"Zig":                                "zig",
+	"Zimpl":                              "zimpl",
+	"cURL Config":                        "curlrc",
+	"desktop":                            "desktop",
+	"dircolors":                          "dircolors",
+	"eC":                                 "ec",
+	"edn":                                "edn",
+	"fish":                               "fish",
+	"hoon":                               "hoon",
+	"jq":                                 "jq",
+	"kvlang":                             "kvlang",
+	"mIRC Script":                        "mirc-script",
+	"mcfunction":                         "mcfunction",
+	"mupad":                              "mupad",
+	"nanorc":                             "nanorc",
+	"nesC":                               "nesc",
+	"ooc":                                "ooc",
+	"q":                                  "q",
+	"reStructuredText":                   "restructuredtext",
+	"robots.txt":                         "robots",
+	"sed":                                "sed",
+	"wisp":                               "wisp",
+	"xBase":                              "xbase",
+}
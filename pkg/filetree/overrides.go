@@ -0,0 +1,172 @@
+package filetree
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	pm "github.com/intility/cwc/pkg/pathmatcher"
+)
+
+// LanguageOverrides are the languages and file types set in the user and project config, they
+// are consulted before the languages of Linguist.
+type LanguageOverrides struct {
+	// Extensions maps extensions, such as .tpl or .hcl.j2, to a language, either the name of a
+	// Linguist language or the name to put after the code fence
+	Extensions map[string]string `yaml:"extensions,omitempty"`
+	// Filenames maps file names, such as Tiltfile, to a language
+	Filenames map[string]string `yaml:"filenames,omitempty"`
+	// Text are globs of the files to include as plain text, even when they look binary
+	Text []string `yaml:"text,omitempty"`
+	// Exclude are globs of the files never to include
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// Merge returns the overrides with the other overrides on top, the mappings of the other win.
+func (o LanguageOverrides) Merge(other LanguageOverrides) LanguageOverrides {
+	extensions := maps.Clone(o.Extensions)
+	if extensions == nil {
+		extensions = map[string]string{}
+	}
+
+	maps.Copy(extensions, other.Extensions)
+
+	filenames := maps.Clone(o.Filenames)
+	if filenames == nil {
+		filenames = map[string]string{}
+	}
+
+	maps.Copy(filenames, other.Filenames)
+
+	return LanguageOverrides{
+		Extensions: extensions,
+		Filenames:  filenames,
+		Text:       append(append([]string{}, o.Text...), other.Text...),
+		Exclude:    append(append([]string{}, o.Exclude...), other.Exclude...),
+	}
+}
+
+// languageRules decide which files are read and in which language, from the overrides first
+// and the generated indexes second.
+type languageRules struct {
+	extensions map[string]string
+	filenames  map[string]string
+	text       []pm.PathMatcher
+	exclude    []pm.PathMatcher
+}
+
+func newLanguageRules(overrides *LanguageOverrides) (*languageRules, error) {
+	rules := &languageRules{
+		extensions: map[string]string{},
+		filenames:  map[string]string{},
+		text:       nil,
+		exclude:    nil,
+	}
+
+	if overrides == nil {
+		return rules, nil
+	}
+
+	for extension, language := range overrides.Extensions {
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+
+		rules.extensions[extension] = fenceOf(language)
+	}
+
+	for filename, language := range overrides.Filenames {
+		rules.filenames[filename] = fenceOf(language)
+	}
+
+	var err error
+
+	rules.text, err = newBaseNameMatchers(overrides.Text)
+	if err != nil {
+		return nil, fmt.Errorf("error creating text file matcher: %w", err)
+	}
+
+	rules.exclude, err = newBaseNameMatchers(overrides.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("error creating excluded file matcher: %w", err)
+	}
+
+	return rules, nil
+}
+
+// newBaseNameMatchers creates a matcher of each glob. As in .gitignore files, a glob without
+// a slash matches the name of a file in any directory, such as *.lock.
+func newBaseNameMatchers(globs []string) ([]pm.PathMatcher, error) {
+	matchers := make([]pm.PathMatcher, 0, len(globs))
+
+	for _, glob := range globs {
+		if !strings.Contains(glob, "/") {
+			glob = "**/" + glob
+		}
+
+		matcher, err := pm.NewGlobPathMatcher(glob)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, nil
+}
+
+// excluded reports whether the file is never to be included.
+func (r *languageRules) excluded(normalizedPath string) bool {
+	return matchAny(r.exclude, normalizedPath)
+}
+
+// isText reports whether the file is to be included as plain text whatever its contents.
+func (r *languageRules) isText(path string) bool {
+	return matchAny(r.text, filepath.ToSlash(path))
+}
+
+// languageOf returns the code fence name of the language of the file.
+func (r *languageRules) languageOf(filePath string, data []byte) string {
+	if r.isText(filePath) {
+		return plainText
+	}
+
+	base := filepath.Base(filePath)
+
+	if fence, ok := r.filenames[base]; ok {
+		return fence
+	}
+
+	if fence, ok := lookupExtension(r.extensions, base); ok {
+		return fence
+	}
+
+	return languageOf(filePath, data)
+}
+
+func matchAny(matchers []pm.PathMatcher, path string) bool {
+	for _, matcher := range matchers {
+		if matcher.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fenceOf returns the code fence name of a Linguist language, by its name in any case. The names
+// of languages Linguist does not know are used in lower case, with dashes for spaces.
+func fenceOf(language string) string {
+	if fence, ok := languageFences[language]; ok {
+		return fence
+	}
+
+	for name, fence := range languageFences {
+		if strings.EqualFold(name, language) {
+			return fence
+		}
+	}
+
+	return strings.ToLower(strings.Join(strings.Fields(language), "-"))
+}
@@ -0,0 +1,210 @@
+package git
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/intility/cwc/pkg/errors"
+)
+
+const (
+	// symlinkMode is the mode of symbolic links in a tree, their blob holds the target.
+	symlinkMode = "120000"
+	// batchHeaderFields is the number of fields of a header printed by git cat-file --batch.
+	batchHeaderFields = 3
+)
+
+var errUnexpectedOutput = stderrors.New("unexpected output of git cat-file")
+
+// logFormat shows the commits with their short hash, date, author and subject.
+const logFormat = "--format=%h %ad %an: %s"
+
+// Run runs git with the arguments in the working directory and returns its output. It returns
+// a GitNotInstalledError when git is not found, and a NotAGitRepositoryError outside a repository.
+func Run(args ...string) (string, error) {
+	output, err := run(nil, args...)
+
+	return string(output), err
+}
+
+func run(stdin io.Reader, args ...string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = buf
+	cmd.Stderr = errBuf
+
+	err := cmd.Run()
+	if err != nil {
+		errStr := errBuf.String()
+
+		if stderrors.Is(err, exec.ErrNotFound) || strings.Contains(err.Error(), "executable file not found in") {
+			return nil, errors.GitNotInstalledError{Message: "git not found in PATH"}
+		}
+
+		if strings.Contains(errStr, "fatal: not a git repository") {
+			return nil, errors.NotAGitRepositoryError{Message: "not a git repository"}
+		}
+
+		if message, _, _ := strings.Cut(strings.TrimSpace(errStr), "\n"); message != "" {
+			return nil, fmt.Errorf("error running git %s: %s", args[0], message)
+		}
+
+		return nil, fmt.Errorf("error running git %s: %w", args[0], err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// TopLevel returns the root of the working tree of the repository.
+func TopLevel() (string, error) {
+	output, err := Run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(output), nil
+}
+
+// StagedFiles returns the files with staged changes, relative to the working directory.
+func StagedFiles() ([]string, error) {
+	err := checkRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := Run("diff", "--cached", "--name-only", "-z", "--relative", "--diff-filter=d")
+	if err != nil {
+		return nil, err
+	}
+
+	return nulSeparated(output), nil
+}
+
+// ChangedFiles returns the files that changed between the revision and the working tree,
+// relative to the working directory. Deleted files are left out.
+func ChangedFiles(ref string) ([]string, error) {
+	err := checkRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := Run("diff", "--name-only", "-z", "--relative", "--diff-filter=d", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return nulSeparated(output), nil
+}
+
+// StagedDiff returns the staged changes of the files.
+func StagedDiff(paths []string) (string, error) {
+	return Run(append([]string{"diff", "--cached", "--relative", "--"}, paths...)...)
+}
+
+// Diff returns the changes of the files between the revision and the working tree.
+func Diff(ref string, paths []string) (string, error) {
+	return Run(append([]string{"diff", "--relative", ref, "--"}, paths...)...)
+}
+
+// Log returns at most maxCount commits in the revision range that changed the files, newest first.
+func Log(revisionRange string, maxCount int, paths []string) (string, error) {
+	args := []string{"log", logFormat, "--date=short", "--max-count=" + strconv.Itoa(maxCount), revisionRange, "--"}
+
+	return Run(append(args, paths...)...)
+}
+
+// TreeFiles returns the files in the tree of the revision within the paths, relative to the
+// working directory. Submodules and symbolic links are left out.
+func TreeFiles(rev string, paths []string) ([]string, error) {
+	output, err := Run(append([]string{"ls-tree", "-r", "-z", rev, "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for _, entry := range strings.Split(output, "\x00") {
+		// <mode> SP <type> SP <object> TAB <file>
+		info, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+
+		if mode, objectType, _ := strings.Cut(info, " "); mode != symlinkMode && strings.HasPrefix(objectType, "blob ") {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+// ReadFiles returns the contents of the files at the revision, in the order of the paths,
+// which are relative to the working directory.
+func ReadFiles(rev string, paths []string) ([][]byte, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	var input strings.Builder
+	for _, path := range paths {
+		input.WriteString(rev + ":./" + path + "\n")
+	}
+
+	output, err := run(strings.NewReader(input.String()), "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([][]byte, 0, len(paths))
+
+	for _, path := range paths {
+		// <object> SP <type> SP <size> LF <contents> LF
+		header, rest, ok := bytes.Cut(output, []byte("\n"))
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnexpectedOutput, path)
+		}
+
+		fields := strings.Fields(string(header))
+		if len(fields) != batchHeaderFields {
+			return nil, fmt.Errorf("error reading %s at %s: %s", path, rev, header)
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil || len(rest) < size {
+			return nil, fmt.Errorf("%w: %s", errUnexpectedOutput, path)
+		}
+
+		contents = append(contents, rest[:size])
+		output = bytes.TrimPrefix(rest[size:], []byte("\n"))
+	}
+
+	return contents, nil
+}
+
+// checkRepository returns a NotAGitRepositoryError outside a repository, where git diff
+// would compare files outside of git instead of failing.
+func checkRepository() error {
+	_, err := Run("rev-parse", "--git-dir")
+
+	return err
+}
+
+// nulSeparated splits the output of a command run with -z, whose paths are neither quoted nor escaped.
+func nulSeparated(output string) []string {
+	var result []string
+
+	for _, line := range strings.Split(output, "\x00") {
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
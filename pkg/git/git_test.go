@@ -0,0 +1,202 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/git"
+)
+
+// chdir changes the working directory for the duration of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+}
+
+// setupRepository creates a repository with a commit of a.go and b.go, in which b.go is staged
+// after the commit and c.go is changed in the working tree.
+func setupRepository(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	chdir(t, t.TempDir())
+
+	run := func(args ...string) {
+		_, err := git.Run(args...)
+		require.NoError(t, err)
+	}
+
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	}
+
+	run("init", "--quiet")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+
+	write("a.go", "package a\n")
+	write("b.go", "package b\n")
+	write(filepath.Join("sub", "c.go"), "package c\n")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Initial commit")
+
+	write("b.go", "package b\n\nconst B = 1\n")
+	run("add", "b.go")
+	write(filepath.Join("sub", "c.go"), "package c\n\nconst C = 1\n")
+}
+
+func TestTopLevel(t *testing.T) {
+	setupRepository(t)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	chdir(t, "sub")
+
+	root, err := git.TopLevel()
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.ToSlash(evalSymlinks(t, wd)), filepath.ToSlash(evalSymlinks(t, root)))
+}
+
+func evalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+
+	resolved, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+
+	return resolved
+}
+
+func TestStagedFiles(t *testing.T) {
+	setupRepository(t)
+
+	files, err := git.StagedFiles()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b.go"}, files)
+}
+
+func TestChangedFiles(t *testing.T) {
+	setupRepository(t)
+
+	files, err := git.ChangedFiles("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b.go", "sub/c.go"}, files)
+
+	// the paths are relative to the working directory, and limited to it
+	chdir(t, "sub")
+
+	files, err = git.ChangedFiles("HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.go"}, files)
+
+	_, err = git.ChangedFiles("no-such-ref")
+	assert.ErrorContains(t, err, "error running git diff: fatal:")
+}
+
+func TestStagedAndChangedFiles_SpecialCharacters(t *testing.T) {
+	setupRepository(t)
+
+	// git quotes such paths in its output, unless asked for raw paths
+	names := []string{"café.go", "日本語.go", "with space.go"}
+	for _, name := range names {
+		require.NoError(t, os.WriteFile(name, []byte("package a\n"), 0o600))
+	}
+
+	_, err := git.Run(append([]string{"add", "--"}, names...)...)
+	require.NoError(t, err)
+
+	files, err := git.StagedFiles()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append([]string{"b.go"}, names...), files)
+
+	files, err = git.ChangedFiles("HEAD")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append([]string{"b.go", "sub/c.go"}, names...), files)
+}
+
+func TestDiffAndLog(t *testing.T) {
+	setupRepository(t)
+
+	staged, err := git.StagedDiff([]string{"b.go"})
+	require.NoError(t, err)
+	assert.Contains(t, staged, "+const B = 1")
+	assert.NotContains(t, staged, "const C")
+
+	diff, err := git.Diff("HEAD", []string{"sub/c.go"})
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+const C = 1")
+
+	log, err := git.Log("HEAD", 10, []string{"a.go"})
+	require.NoError(t, err)
+	assert.Contains(t, log, "Test: Initial commit")
+}
+
+func TestRun_NotAGitRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	chdir(t, dir)
+
+	_, err := git.StagedFiles()
+
+	assert.True(t, errors.IsNotAGitRepositoryError(err))
+}
+
+func TestRun_GitNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := git.Run("status")
+
+	assert.True(t, errors.IsGitNotInstalledError(err))
+}
+
+func TestTreeFilesAndReadFiles(t *testing.T) {
+	setupRepository(t)
+
+	files, err := git.TreeFiles("HEAD", []string{"."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go", "b.go", "sub/c.go"}, files)
+
+	// the committed contents, not the staged or changed ones
+	contents, err := git.ReadFiles("HEAD", files)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("package a\n"), []byte("package b\n"), []byte("package c\n")}, contents)
+
+	chdir(t, "sub")
+
+	files, err = git.TreeFiles("HEAD", []string{"."})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.go"}, files)
+
+	contents, err = git.ReadFiles("HEAD", files)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("package c\n")}, contents)
+
+	_, err = git.ReadFiles("HEAD", []string{"missing.go"})
+	assert.ErrorContains(t, err, "error reading missing.go at HEAD")
+
+	_, err = git.TreeFiles("no-such-ref", nil)
+	assert.ErrorContains(t, err, "error running git ls-tree: fatal:")
+}
@@ -0,0 +1,157 @@
+package patch
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/intility/cwc/pkg/errors"
+)
+
+// diffContext is the number of unchanged lines shown around a change.
+const diffContext = 3
+
+// Apply applies a hunk to the content of a file. The lines the hunk removes or keeps must
+// appear in the file exactly; when they appear more than once, the occurrence closest to the
+// line the hunk starts at is changed. It returns a HunkConflictError when the hunk does not apply.
+func Apply(path, content string, hunk Hunk) (string, error) {
+	crlf := strings.Contains(content, "\r\n")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	lines, trailingNewline := splitLines(content)
+	before, after := hunk.sides()
+
+	at, ok := find(lines, before, hunk.OldStart-1)
+	if !ok {
+		return "", errors.HunkConflictError{Path: path, Line: hunk.OldStart}
+	}
+
+	result := slices.Concat(lines[:at], after, lines[at+len(before):])
+
+	if content == "" {
+		trailingNewline = true
+	}
+
+	patched := joinLines(result, trailingNewline)
+	if crlf {
+		patched = strings.ReplaceAll(patched, "\n", "\r\n")
+	}
+
+	return patched, nil
+}
+
+// Diff returns the hunks turning the old content into the new, as a single
+// hunk around the lines that differ, or no hunks if the contents are equal.
+func Diff(oldContent, newContent string) []Hunk {
+	oldLines, _ := splitLines(oldContent)
+	newLines, _ := splitLines(newContent)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	if prefix == len(oldLines) && prefix == len(newLines) {
+		return nil
+	}
+
+	start := max(prefix-diffContext, 0)
+	end := min(len(oldLines)-suffix+diffContext, len(oldLines))
+
+	var hunkLines []string
+
+	for _, line := range oldLines[start:prefix] {
+		hunkLines = append(hunkLines, " "+line)
+	}
+
+	for _, line := range oldLines[prefix : len(oldLines)-suffix] {
+		hunkLines = append(hunkLines, "-"+line)
+	}
+
+	for _, line := range newLines[prefix : len(newLines)-suffix] {
+		hunkLines = append(hunkLines, "+"+line)
+	}
+
+	for _, line := range oldLines[len(oldLines)-suffix : end] {
+		hunkLines = append(hunkLines, " "+line)
+	}
+
+	return []Hunk{{OldStart: start + 1, Lines: hunkLines}}
+}
+
+// sides returns the lines of the file before and after the hunk is applied.
+func (h Hunk) sides() ([]string, []string) {
+	var before, after []string
+
+	for _, line := range h.Lines {
+		switch line[0] {
+		case '-':
+			before = append(before, line[1:])
+		case '+':
+			after = append(after, line[1:])
+		default:
+			before = append(before, line[1:])
+			after = append(after, line[1:])
+		}
+	}
+
+	return before, after
+}
+
+// find returns the position of the lines in the file closest to the expected position.
+func find(lines, wanted []string, expected int) (int, bool) {
+	if len(wanted) == 0 {
+		// a hunk adding lines without context only applies to an empty file
+		return 0, len(lines) == 0
+	}
+
+	best, found := 0, false
+
+	for i := 0; i+len(wanted) <= len(lines); i++ {
+		if !slices.Equal(lines[i:i+len(wanted)], wanted) {
+			continue
+		}
+
+		if !found || distance(i, expected) < distance(best, expected) {
+			best, found = i, true
+		}
+	}
+
+	return best, found
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+func splitLines(content string) ([]string, bool) {
+	if content == "" {
+		return nil, false
+	}
+
+	trailingNewline := strings.HasSuffix(content, "\n")
+
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n"), trailingNewline
+}
+
+func joinLines(lines []string, trailingNewline bool) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	content := strings.Join(lines, "\n")
+	if trailingNewline {
+		content += "\n"
+	}
+
+	return content
+}
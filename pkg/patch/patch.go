@@ -0,0 +1,261 @@
+package patch
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const devNull = "/dev/null"
+
+var (
+	hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+	// a line naming the file of the code block that follows, such as "./cmd/main.go" or "`main.go`:"
+	pathLine = regexp.MustCompile("^(?:\\*\\*|`)?(?:File: ?)?((?:\\./)?[\\w.-]+(?:/[\\w.-]+)*\\.\\w+)(?:\\*\\*|`)?:?$")
+	// a path in the info string of a fence, such as "go:main.go", "go path=main.go" or "go cmd/main.go"
+	infoPath = regexp.MustCompile(`^(?:[\w+#-]+:|(?:path|file|filename|title)=)(.+)$`)
+	// a bare field of the info string taken as a path, which needs an extension starting with a letter
+	// so that versions such as "v1.2" are not, and neither are fields like "a/b"
+	infoFilePath = regexp.MustCompile(`^(?:\./)?(?:[\w.-]+/)*[\w-][\w.-]*\.[A-Za-z]\w*$`)
+)
+
+// Change is a change to a single file proposed in an answer, either
+// as the hunks of a unified diff or as the new content of the whole file.
+type Change struct {
+	Path  string
+	Hunks []Hunk
+	// Content replaces the file when WholeFile is set
+	Content   string
+	WholeFile bool
+	// the diff creates or deletes the file
+	Created bool
+	Deleted bool
+}
+
+// Hunk is a contiguous change in a unified diff.
+type Hunk struct {
+	// OldStart is the line the hunk starts at in the original file, counting from 1, or 0 when unknown.
+	OldStart int
+	// Lines are the lines of the hunk prefixed with ' ' for context, '-' for removed and '+' for added lines.
+	Lines []string
+}
+
+// String returns the hunk as it appears in a unified diff.
+func (h Hunk) String() string {
+	var oldLines, newLines int
+
+	for _, line := range h.Lines {
+		switch line[0] {
+		case '-':
+			oldLines++
+		case '+':
+			newLines++
+		default:
+			oldLines++
+			newLines++
+		}
+	}
+
+	header := "@@ -" + strconv.Itoa(h.OldStart) + "," + strconv.Itoa(oldLines) +
+		" +" + strconv.Itoa(h.OldStart) + "," + strconv.Itoa(newLines) + " @@\n"
+
+	return header + strings.Join(h.Lines, "\n") + "\n"
+}
+
+// Parse finds the changes proposed in an answer: unified diffs, fenced or not,
+// and fenced code blocks annotated with the path of the file they replace.
+func Parse(text string) []Change {
+	var (
+		changes  []Change
+		outside  []string
+		previous string
+	)
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+			outside = append(outside, line)
+
+			if trimmed != "" {
+				previous = trimmed
+			}
+
+			continue
+		}
+
+		// the text between code blocks may hold a diff without a fence
+		changes = append(changes, parseDiff(outside)...)
+		outside = nil
+
+		marker := trimmed[:3]
+		info := strings.TrimSpace(strings.TrimLeft(trimmed, marker[:1]))
+
+		var body []string
+
+		for i++; i < len(lines); i++ {
+			closing := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(closing, marker) && strings.Trim(closing, marker[:1]) == "" {
+				break
+			}
+
+			body = append(body, lines[i])
+		}
+
+		changes = append(changes, parseCodeBlock(info, previous, body)...)
+		previous = ""
+	}
+
+	return append(changes, parseDiff(outside)...)
+}
+
+func parseCodeBlock(info, previous string, body []string) []Change {
+	if diff := parseDiff(body); len(diff) > 0 {
+		return diff
+	}
+
+	filePath := annotatedPath(info, previous)
+	if filePath == "" {
+		return nil
+	}
+
+	return []Change{{
+		Path:      filePath,
+		Hunks:     nil,
+		Content:   strings.Join(body, "\n") + "\n",
+		WholeFile: true,
+		Created:   false,
+		Deleted:   false,
+	}}
+}
+
+// annotatedPath returns the path a code block is annotated with in its
+// info string, or in the line before the block, or "" if it has none.
+func annotatedPath(info, previous string) string {
+	for _, field := range strings.Fields(info) {
+		if match := infoPath.FindStringSubmatch(field); match != nil {
+			return cleanPath(strings.Trim(match[1], `"'`))
+		}
+
+		if infoFilePath.MatchString(field) {
+			return cleanPath(field)
+		}
+	}
+
+	if match := pathLine.FindStringSubmatch(previous); match != nil {
+		return cleanPath(match[1])
+	}
+
+	return ""
+}
+
+// parseDiff parses the unified diffs in the lines, ignoring any other text.
+func parseDiff(lines []string) []Change { //nolint:cyclop
+	var (
+		changes []Change
+		change  *Change
+		hunk    *Hunk
+	)
+
+	endHunk := func() {
+		if hunk != nil && change != nil {
+			hunk.Lines = trimEmptyLines(hunk.Lines)
+			if len(hunk.Lines) > 0 {
+				change.Hunks = append(change.Hunks, *hunk)
+			}
+		}
+
+		hunk = nil
+	}
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			endHunk()
+
+			oldPath, newPath := diffPath(line), diffPath(lines[i+1])
+			changes = append(changes, Change{
+				Path:      newPath,
+				Hunks:     nil,
+				Content:   "",
+				WholeFile: false,
+				Created:   oldPath == devNull,
+				Deleted:   newPath == devNull,
+			})
+			change = &changes[len(changes)-1]
+
+			if change.Deleted {
+				change.Path = oldPath
+			}
+		case strings.HasPrefix(line, "+++ ") && hunk == nil:
+		case strings.HasPrefix(line, "@@") && change != nil:
+			endHunk()
+
+			hunk = &Hunk{OldStart: 0, Lines: nil}
+			if match := hunkHeader.FindStringSubmatch(line); match != nil {
+				hunk.OldStart, _ = strconv.Atoi(match[1])
+			}
+		case hunk != nil && line == "":
+			// trailing spaces of empty context lines are often lost
+			hunk.Lines = append(hunk.Lines, " ")
+		case hunk != nil && strings.ContainsAny(line[:1], " +-"):
+			hunk.Lines = append(hunk.Lines, line)
+		case hunk != nil && strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			endHunk()
+		}
+	}
+
+	endHunk()
+
+	// keep the files the diff changes
+	result := changes[:0]
+
+	for _, change := range changes {
+		if change.Path != "" && (len(change.Hunks) > 0 || change.Deleted) {
+			result = append(result, change)
+		}
+	}
+
+	return result
+}
+
+// diffPath returns the path in a "--- a/path" or "+++ b/path" line.
+func diffPath(line string) string {
+	name := strings.TrimSpace(line[4:])
+
+	// strip the timestamp some tools add after a tab
+	name, _, _ = strings.Cut(name, "\t")
+
+	if name == devNull {
+		return devNull
+	}
+
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+
+	return cleanPath(name)
+}
+
+func cleanPath(name string) string {
+	name = path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." {
+		return ""
+	}
+
+	return name
+}
+
+func trimEmptyLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
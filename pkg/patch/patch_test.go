@@ -0,0 +1,160 @@
+package patch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/patch"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   []patch.Change
+	}{
+		{
+			name: "fenced unified diff",
+			answer: "Here is the fix:\n\n```diff\n--- a/main.go\n+++ b/main.go\n@@ -3,3 +3,3 @@ func main() {\n" +
+				" a\n-b\n+c\n\n d\n```\n\nThat should do it.",
+			want: []patch.Change{{
+				Path:  "main.go",
+				Hunks: []patch.Hunk{{OldStart: 3, Lines: []string{" a", "-b", "+c", " ", " d"}}},
+			}},
+		},
+		{
+			name:   "unfenced diff with two files",
+			answer: "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-x\n+y\n--- a/b.txt\n+++ b/b.txt\n@@\n-1\n+2\nDone.",
+			want: []patch.Change{
+				{Path: "a.txt", Hunks: []patch.Hunk{{OldStart: 1, Lines: []string{"-x", "+y"}}}},
+				{Path: "b.txt", Hunks: []patch.Hunk{{OldStart: 0, Lines: []string{"-1", "+2"}}}},
+			},
+		},
+		{
+			name:   "new and deleted files",
+			answer: "```diff\n--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+hello\n--- a/old.txt\n+++ /dev/null\n```",
+			want: []patch.Change{
+				{Path: "new.txt", Created: true, Hunks: []patch.Hunk{{OldStart: 0, Lines: []string{"+hello"}}}},
+				{Path: "old.txt", Deleted: true},
+			},
+		},
+		{
+			name:   "path in the info string",
+			answer: "```go:cmd/main.go\npackage main\n```\n```python title=\"app.py\"\nprint()\n```",
+			want: []patch.Change{
+				{Path: "cmd/main.go", Content: "package main\n", WholeFile: true},
+				{Path: "app.py", Content: "print()\n", WholeFile: true},
+			},
+		},
+		{
+			name:   "info fields that are not file paths",
+			answer: "```go v1.2\npackage main\n```\n```diff a/b\nno diff\n```\n```text ../..\nup\n```",
+			want:   nil,
+		},
+		{
+			name:   "path on the line before the block",
+			answer: "Update `pkg/a.go`:\n\n./pkg/a.go\n```go\npackage a\n```\n**b.go**\n```go\npackage b\n```",
+			want: []patch.Change{
+				{Path: "pkg/a.go", Content: "package a\n", WholeFile: true},
+				{Path: "b.go", Content: "package b\n", WholeFile: true},
+			},
+		},
+		{
+			name:   "code blocks without a path are ignored",
+			answer: "Run this:\n```sh\ngo test ./...\n```\nor `make test`.",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, patch.Parse(tt.answer))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		hunk     patch.Hunk
+		want     string
+		conflict bool
+	}{
+		{
+			name:    "replaces a line",
+			content: "a\nb\nc\n",
+			hunk:    patch.Hunk{OldStart: 1, Lines: []string{" a", "-b", "+B", " c"}},
+			want:    "a\nB\nc\n",
+		},
+		{
+			name:    "finds the lines when the line numbers are off",
+			content: "x\ny\na\nb\n",
+			hunk:    patch.Hunk{OldStart: 1, Lines: []string{" a", "+inserted", " b"}},
+			want:    "x\ny\na\ninserted\nb\n",
+		},
+		{
+			name:    "picks the occurrence closest to the start line",
+			content: "a\nb\na\nb\n",
+			hunk:    patch.Hunk{OldStart: 3, Lines: []string{"-a", "+c", " b"}},
+			want:    "a\nb\nc\nb\n",
+		},
+		{
+			name:    "creates a file",
+			content: "",
+			hunk:    patch.Hunk{OldStart: 0, Lines: []string{"+hello"}},
+			want:    "hello\n",
+		},
+		{
+			name:    "keeps windows line endings",
+			content: "a\r\nb\r\n",
+			hunk:    patch.Hunk{OldStart: 1, Lines: []string{"-a", "+c"}},
+			want:    "c\r\nb\r\n",
+		},
+		{
+			name:     "refuses changed lines",
+			content:  "a\nb\n",
+			hunk:     patch.Hunk{OldStart: 1, Lines: []string{" a", "-c", "+d"}},
+			conflict: true,
+		},
+		{
+			name:     "refuses adding lines without context to an existing file",
+			content:  "a\n",
+			hunk:     patch.Hunk{OldStart: 0, Lines: []string{"+b"}},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patch.Apply("file.txt", tt.content, tt.hunk)
+
+			if tt.conflict {
+				assert.True(t, errors.IsHunkConflictError(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldContent := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	newContent := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+
+	hunks := patch.Diff(oldContent, newContent)
+
+	require.Len(t, hunks, 1)
+	assert.Equal(t, "@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n", hunks[0].String())
+
+	applied, err := patch.Apply("file.txt", oldContent, hunks[0])
+	require.NoError(t, err)
+	assert.Equal(t, newContent, applied)
+
+	assert.Empty(t, patch.Diff(oldContent, oldContent))
+}
@@ -0,0 +1,25 @@
+package pathmatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+func TestCompoundPathMatcher_MatchDir(t *testing.T) {
+	nodeModules, err := pathmatcher.NewGlobPathMatcher("**/node_modules/**")
+	require.NoError(t, err)
+
+	tests, err := pathmatcher.NewRegexPathMatcher(`_test\.go$`)
+	require.NoError(t, err)
+
+	matcher := pathmatcher.NewCompoundPathMatcher(nodeModules, tests)
+
+	assert.True(t, matcher.MatchDir("web/node_modules"))
+	assert.False(t, matcher.MatchDir("web/src"))
+	assert.False(t, matcher.MatchDir("pkg"))
+	assert.True(t, matcher.Match("pkg/a_test.go"))
+}
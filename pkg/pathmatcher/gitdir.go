@@ -0,0 +1,25 @@
+package pathmatcher
+
+import (
+	"regexp"
+)
+
+// gitDirPattern matches a .git directory at any depth, and everything in it.
+var gitDirPattern = regexp.MustCompile(`(^|/)\.git(/|$)`)
+
+// GitDirPathMatcher matches the .git directories, of the repository and of any nested clone,
+// and the files in them.
+type GitDirPathMatcher struct{}
+
+func NewGitDirPathMatcher() *GitDirPathMatcher {
+	return &GitDirPathMatcher{}
+}
+
+func (g *GitDirPathMatcher) Match(path string) bool {
+	return gitDirPattern.MatchString(path)
+}
+
+// MatchDir reports whether the directory is a .git directory or inside one.
+func (g *GitDirPathMatcher) MatchDir(path string) bool {
+	return gitDirPattern.MatchString(path)
+}
@@ -0,0 +1,34 @@
+package pathmatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+func TestGitDirPathMatcher(t *testing.T) {
+	matcher := pathmatcher.NewGitDirPathMatcher()
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".git/config", true},
+		{"/home/user/repo/.git/HEAD", true},
+		{"../other-repo/.git/hooks/pre-commit.sample", true},
+		{"vendor/lib/.git/packed-refs", true},
+		{".gitignore", false},
+		{"docs/.github/workflows/ci.yml", false},
+		{"pkg/git/git.go", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matcher.Match(tt.path), tt.path)
+	}
+
+	assert.True(t, matcher.MatchDir("/home/user/repo/.git"))
+	assert.True(t, matcher.MatchDir("vendor/lib/.git"))
+	assert.False(t, matcher.MatchDir(".github"))
+}
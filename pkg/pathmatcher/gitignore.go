@@ -1,65 +1,423 @@
 package pathmatcher
 
 import (
+	"bufio"
 	"bytes"
+	stderrors "errors"
 	"fmt"
-	"os/exec"
-	"slices"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
-
-	"github.com/intility/cwc/pkg/errors"
+	"sync"
 )
 
+// GitignorePathMatcher matches the paths ignored by git, following the .gitignore files of the
+// repository, .git/info/exclude and the global excludes file, without running git. Outside a
+// repository, the .gitignore files from the working directory down are followed.
 type GitignorePathMatcher struct {
-	ignoredPaths []string
+	workingDir string
+	// the name of the files with the patterns of the paths to ignore
+	ignoreFile string
+	// the name of the files with the patterns of ignored paths to include again, if any
+	includeFile string
+	// whether .git/info/exclude and the global excludes file are followed
+	gitExcludes bool
+	global      []gitignorePattern
+	mu          sync.Mutex
+	// the rules in effect in each directory and the root of the rules of each directory, by absolute path
+	dirs  map[string]*gitignoreDir
+	roots map[string]string
+}
+
+// GitignoreOpt configures optional behaviour of the GitignorePathMatcher.
+type GitignoreOpt func(*GitignorePathMatcher)
+
+// WithIncludeFile includes the ignored paths matching the patterns of the files with the name
+// again, such as a checked in example of an ignored config file.
+func WithIncludeFile(name string) GitignoreOpt {
+	return func(g *GitignorePathMatcher) {
+		g.includeFile = name
+	}
 }
 
-func NewGitignorePathMatcher() (*GitignorePathMatcher, error) {
+// gitignoreDir holds the patterns of the ignore and include files of a directory, the patterns
+// of its parents take effect when none of them match.
+type gitignoreDir struct {
+	// the directory the paths of the patterns are relative to
+	root     string
+	parent   *gitignoreDir
+	patterns []gitignorePattern
+	includes []gitignorePattern
+	// whether the directory is ignored, in which case everything in it is ignored unless included again
+	ignored bool
+	// whether nothing in the directory can be included again, so that it need not be looked into
+	skipped bool
+}
+
+func NewGitignorePathMatcher(opts ...GitignoreOpt) (*GitignorePathMatcher, error) {
+	global, err := readGitignoreFile(globalExcludesFile(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	matcher, err := newIgnoreFilePathMatcher(".gitignore", true, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher.global = global
+
+	return matcher, nil
+}
+
+// NewIgnoreFilePathMatcher creates a matcher for files with the name using the gitignore syntax,
+// such as .cwcignore, found in the repository root and any directory below it.
+func NewIgnoreFilePathMatcher(name string, opts ...GitignoreOpt) (*GitignorePathMatcher, error) {
+	return newIgnoreFilePathMatcher(name, false, opts)
+}
+
+func newIgnoreFilePathMatcher(name string, gitExcludes bool, opts []GitignoreOpt) (*GitignorePathMatcher, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting working directory: %w", err)
+	}
+
 	matcher := &GitignorePathMatcher{
-		ignoredPaths: make([]string, 0),
+		workingDir:  workingDir,
+		ignoreFile:  name,
+		includeFile: "",
+		gitExcludes: gitExcludes,
+		global:      nil,
+		mu:          sync.Mutex{},
+		dirs:        make(map[string]*gitignoreDir),
+		roots:       make(map[string]string),
 	}
 
-	err := matcher.gitLsFiles()
+	for _, opt := range opts {
+		opt(matcher)
+	}
 
-	return matcher, err
+	return matcher, nil
 }
 
+// Match reports whether the file at the path is ignored.
 func (g *GitignorePathMatcher) Match(path string) bool {
-	return slices.Contains(g.ignoredPaths, path)
+	absPath := g.absPath(path)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dir := g.dir(filepath.Dir(absPath))
+	if dir.skipped {
+		return true
+	}
+
+	rel, err := filepath.Rel(dir.root, absPath)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	if !dir.ignored && !dir.match(rel, false) {
+		return false
+	}
+
+	return !dir.include(rel, false)
+}
+
+// MatchDir reports whether the directory at the path is ignored along with everything in it,
+// so that it need not be looked into.
+func (g *GitignorePathMatcher) MatchDir(path string) bool {
+	absPath := g.absPath(path)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.dir(absPath).skipped
+}
+
+func (g *GitignorePathMatcher) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(g.workingDir, path)
+}
+
+// dir returns the rules in effect in the directory, reading its ignore and include files the first time.
+func (g *GitignorePathMatcher) dir(path string) *gitignoreDir {
+	if dir, ok := g.dirs[path]; ok {
+		return dir
+	}
+
+	var dir *gitignoreDir
+
+	root := g.root(path)
+	if path == root {
+		dir = &gitignoreDir{
+			root:     root,
+			parent:   g.baseDir(root),
+			patterns: nil,
+			includes: nil,
+			ignored:  false,
+			skipped:  false,
+		}
+	} else {
+		parent := g.dir(filepath.Dir(path))
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		ignored := (parent.ignored || parent.match(rel, true)) && !parent.include(rel, true)
+
+		dir = &gitignoreDir{
+			root:     root,
+			parent:   parent,
+			patterns: nil,
+			includes: nil,
+			ignored:  ignored,
+			skipped:  ignored && !parent.mayIncludeBelow(rel),
+		}
+	}
+
+	// nothing in a skipped directory can be included again, so its files do not matter
+	if !dir.skipped {
+		base, _ := filepath.Rel(root, path)
+		if base == "." {
+			base = ""
+		}
+
+		// unreadable files are skipped, like git does
+		dir.patterns, _ = readGitignoreFile(filepath.Join(path, g.ignoreFile), filepath.ToSlash(base))
+
+		if g.includeFile != "" {
+			dir.includes, _ = readGitignoreFile(filepath.Join(path, g.includeFile), filepath.ToSlash(base))
+		}
+	}
+
+	g.dirs[path] = dir
+
+	return dir
+}
+
+// baseDir returns the rules in effect in the whole repository, below those of its ignore files.
+func (g *GitignorePathMatcher) baseDir(root string) *gitignoreDir {
+	if !g.gitExcludes {
+		return nil
+	}
+
+	global := &gitignoreDir{
+		root: root, parent: nil, patterns: g.global, includes: nil, ignored: false, skipped: false,
+	}
+	exclude, _ := readGitignoreFile(filepath.Join(gitDir(root), "info", "exclude"), "")
+
+	return &gitignoreDir{
+		root: root, parent: global, patterns: exclude, includes: nil, ignored: false, skipped: false,
+	}
+}
+
+// root returns the root of the repository the directory is in. Outside a repository, it is the
+// working directory, or the directory itself when it is not in the working directory.
+func (g *GitignorePathMatcher) root(path string) string {
+	repository := g.repository(path)
+	if repository != "" {
+		return repository
+	}
+
+	rel, err := filepath.Rel(g.workingDir, path)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return g.workingDir
+	}
+
+	return path
 }
 
-func (g *GitignorePathMatcher) Any() bool {
-	return len(g.ignoredPaths) > 0
+// repository returns the root of the repository the directory is in, or an empty string.
+func (g *GitignorePathMatcher) repository(path string) string {
+	if root, ok := g.roots[path]; ok {
+		return root
+	}
+
+	var root string
+
+	_, err := os.Lstat(filepath.Join(path, ".git"))
+
+	switch {
+	case err == nil:
+		root = path
+	case filepath.Dir(path) != path:
+		root = g.repository(filepath.Dir(path))
+	}
+
+	g.roots[path] = root
+
+	return root
+}
+
+// match reports whether the path, relative to the root, is ignored by the last pattern
+// matching it, looking at the patterns of the parents when none of the directory match.
+func (d *gitignoreDir) match(rel string, isDir bool) bool {
+	return lastMatch(d, rel, isDir, func(dir *gitignoreDir) []gitignorePattern { return dir.patterns })
+}
+
+// include reports whether the ignored path is included again by the include patterns.
+func (d *gitignoreDir) include(rel string, isDir bool) bool {
+	return lastMatch(d, rel, isDir, func(dir *gitignoreDir) []gitignorePattern { return dir.includes })
+}
+
+// mayIncludeBelow reports whether an include pattern could match a path inside the directory.
+// Include files inside ignored directories are not followed, like ignore files.
+func (d *gitignoreDir) mayIncludeBelow(rel string) bool {
+	for dir := d; dir != nil; dir = dir.parent {
+		for _, pattern := range dir.includes {
+			if !pattern.negate && pattern.mayMatchBelow(rel) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func lastMatch(d *gitignoreDir, rel string, isDir bool, patterns func(*gitignoreDir) []gitignorePattern) bool {
+	for dir := d; dir != nil; dir = dir.parent {
+		dirPatterns := patterns(dir)
+		for i := len(dirPatterns) - 1; i >= 0; i-- {
+			if dirPatterns[i].match(rel, isDir) {
+				return !dirPatterns[i].negate
+			}
+		}
+	}
+
+	return false
+}
+
+// gitDir returns the git directory of the repository, following the .git file of worktrees and submodules.
+func gitDir(root string) string {
+	dotGit := filepath.Join(root, ".git")
+
+	data, err := os.ReadFile(dotGit) // #nosec
+	if err != nil {
+		return dotGit
+	}
+
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return dotGit
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+
+	// worktrees share info/exclude with the main repository
+	commonDir, err := os.ReadFile(filepath.Join(dir, "commondir")) // #nosec
+	if err != nil {
+		return dir
+	}
+
+	common := strings.TrimSpace(string(commonDir))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(dir, common)
+	}
+
+	return common
 }
 
-func (g *GitignorePathMatcher) gitLsFiles() error {
-	// git ls-files -o --exclude-standard
-	buf := new(bytes.Buffer)
-	errBuf := new(bytes.Buffer)
-	cmd := exec.Command("git", "ls-files", "-o", "--ignored", "--exclude-standard")
-	cmd.Stdout = buf
-	cmd.Stderr = errBuf
+// globalExcludesFile returns the path of core.excludesFile from the git config,
+// or of git/ignore in the XDG config directory by default.
+func globalExcludesFile() string {
+	home, _ := os.UserHomeDir()
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" && home != "" {
+		configHome = filepath.Join(home, ".config")
+	}
+
+	var path string
 
-	err := cmd.Run()
+	if configHome != "" {
+		path = filepath.Join(configHome, "git", "ignore")
+	}
+
+	// the later config takes precedence
+	for _, config := range []string{filepath.Join(configHome, "git", "config"), filepath.Join(home, ".gitconfig")} {
+		if value, ok := gitConfigValue(config, "core", "excludesfile"); ok {
+			path = value
+			if rest, ok := strings.CutPrefix(path, "~/"); ok && home != "" {
+				path = filepath.Join(home, rest)
+			}
+		}
+	}
+
+	return path
+}
+
+// gitConfigValue returns the last value of the key in the section of a git config file.
+func gitConfigValue(path string, section string, key string) (string, bool) {
+	data, err := os.ReadFile(path) // #nosec
 	if err != nil {
-		errStr := errBuf.String()
+		return "", false
+	}
 
-		if strings.Contains(err.Error(), "executable file not found in") {
-			return errors.GitNotInstalledError{Message: "git not found in PATH"}
+	var (
+		value   string
+		found   bool
+		current string
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			name, _, _ := strings.Cut(strings.Trim(line, "[]"), " ")
+			current = strings.ToLower(strings.TrimSpace(name))
+
+			continue
+		}
+
+		name, rest, ok := strings.Cut(line, "=")
+		if !ok || current != section || !strings.EqualFold(strings.TrimSpace(name), key) {
+			continue
 		}
 
-		if strings.Contains(errStr, "fatal: not a git repository") {
-			return errors.NotAGitRepositoryError{Message: "not a git repository"}
+		value, found = strings.Trim(strings.TrimSpace(stripConfigComment(rest)), `"`), true
+	}
+
+	return value, found
+}
+
+// stripConfigComment removes a comment, starting with ; or # outside quotes, from a config value.
+func stripConfigComment(value string) string {
+	quoted := false
+
+	for i, char := range value {
+		switch {
+		case char == '"':
+			quoted = !quoted
+		case (char == ';' || char == '#') && !quoted:
+			return value[:i]
 		}
+	}
+
+	return value
+}
 
-		return fmt.Errorf("error running git ls-files: %w", err)
+// readGitignoreFile reads the patterns of a .gitignore file, a missing file has none.
+func readGitignoreFile(path string, base string) ([]gitignorePattern, error) {
+	if path == "" {
+		return nil, nil
 	}
 
-	// create a slice of ignored paths and remove the last empty string
-	ignored := strings.Split(buf.String(), "\n")
-	ignored = ignored[:len(ignored)-1]
+	data, err := os.ReadFile(path) // #nosec
+	if stderrors.Is(err, fs.ErrNotExist) || stderrors.Is(err, fs.ErrPermission) {
+		return nil, nil
+	}
 
-	g.ignoredPaths = append(g.ignoredPaths, ignored...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", path, err)
+	}
 
-	return nil
+	return parseGitignore(string(data), base), nil
 }
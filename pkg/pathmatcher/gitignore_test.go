@@ -0,0 +1,192 @@
+package pathmatcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+// writeFiles creates the files with their contents below the directory.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+// newMatcher creates a matcher in the directory, with the global excludes file set up in the config home.
+func newMatcher(t *testing.T, dir string, globalExcludes string) *pathmatcher.GitignorePathMatcher {
+	t.Helper()
+
+	configHome := t.TempDir()
+	t.Setenv("HOME", configHome)
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	writeFiles(t, configHome, map[string]string{"git/ignore": globalExcludes})
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	matcher, err := pathmatcher.NewGitignorePathMatcher()
+	require.NoError(t, err)
+
+	return matcher
+}
+
+func TestGitignorePathMatcher_Match(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".git/info/exclude": "secret.txt\n",
+		".gitignore": "# build output\n" +
+			"*.log\n" +
+			"!keep.log\n" +
+			"build/\n" +
+			"!build/keep.go\n" +
+			"/only-root.txt\n" +
+			"docs/**/*.tmp\n" +
+			"\\#hash\n" +
+			"vendor\n" +
+			"trailing.txt   \n" +
+			"file[0-9].txt\n",
+		"sub/.gitignore": "!debug.log\nlocal/\n",
+		"build":          "",
+	})
+
+	matcher := newMatcher(t, root, ".DS_Store\n")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "main.go", want: false},
+		{path: "x.log", want: true},
+		{path: "keep.log", want: false},
+		{path: "sub/x.log", want: true},
+		{path: "sub/debug.log", want: false},
+		{path: "build/out.go", want: true},
+		{path: "build/keep.go", want: true},
+		{path: "sub/build/out.go", want: true},
+		{path: "build", want: false},
+		{path: "only-root.txt", want: true},
+		{path: "sub/only-root.txt", want: false},
+		{path: "docs/a/b/c.tmp", want: true},
+		{path: "docs/c.tmp", want: true},
+		{path: "other/c.tmp", want: false},
+		{path: "#hash", want: true},
+		{path: "vendor/lib/x.go", want: true},
+		{path: "trailing.txt", want: true},
+		{path: "file1.txt", want: true},
+		{path: "fileA.txt", want: false},
+		{path: "secret.txt", want: true},
+		{path: ".DS_Store", want: true},
+		{path: "sub/.DS_Store", want: true},
+		{path: "sub/local/x.go", want: true},
+		{path: "local/x.go", want: false},
+		{path: filepath.Join(root, "x.log"), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, matcher.Match(tt.path))
+		})
+	}
+
+	assert.True(t, matcher.MatchDir("build"))
+	assert.True(t, matcher.MatchDir("sub/local"))
+	assert.False(t, matcher.MatchDir("sub"))
+}
+
+func TestGitignorePathMatcher_OutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".gitignore":     "*.log\n/dist\n",
+		"sub/.gitignore": "*.tmp\n",
+	})
+
+	matcher := newMatcher(t, dir, "")
+
+	assert.True(t, matcher.Match("x.log"))
+	assert.True(t, matcher.Match("sub/x.log"))
+	assert.True(t, matcher.Match("dist/x.js"))
+	assert.False(t, matcher.Match("sub/dist/x.js"))
+	assert.True(t, matcher.Match("sub/x.tmp"))
+	assert.False(t, matcher.Match("x.tmp"))
+	assert.False(t, matcher.Match("main.go"))
+}
+
+func TestGitignorePathMatcher_OtherRepository(t *testing.T) {
+	other := t.TempDir()
+	writeFiles(t, other, map[string]string{
+		".git/HEAD":  "ref: refs/heads/main\n",
+		".gitignore": "*.gen\n",
+	})
+
+	matcher := newMatcher(t, t.TempDir(), "")
+
+	assert.True(t, matcher.Match(filepath.Join(other, "pkg", "x.gen")))
+	assert.False(t, matcher.Match(filepath.Join(other, "pkg", "x.go")))
+}
+
+func TestGitignorePathMatcher_IncludeFile(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".git/HEAD":   "ref: refs/heads/main\n",
+		".gitignore":  "config.local*\nbuild/\nnode_modules/\n",
+		".cwcinclude": "/config.local.example\n/build/generated/api.go\n",
+	})
+
+	newMatcher(t, root, "")
+
+	matcher, err := pathmatcher.NewGitignorePathMatcher(pathmatcher.WithIncludeFile(".cwcinclude"))
+	require.NoError(t, err)
+
+	assert.True(t, matcher.Match("config.local"))
+	assert.False(t, matcher.Match("config.local.example"))
+	assert.True(t, matcher.Match("sub/config.local.example"))
+	assert.True(t, matcher.Match("build/out.o"))
+	assert.False(t, matcher.Match("build/generated/api.go"))
+	assert.True(t, matcher.Match("build/generated/other.go"))
+
+	// build holds an included file, the other ignored directories can still be skipped
+	assert.False(t, matcher.MatchDir("build"))
+	assert.False(t, matcher.MatchDir("build/generated"))
+	assert.True(t, matcher.MatchDir("build/cache"))
+	assert.True(t, matcher.MatchDir("node_modules"))
+}
+
+func TestIgnoreFilePathMatcher(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".git/HEAD":          "ref: refs/heads/main\n",
+		".gitignore":         "*.log\n",
+		".cwcignore":         "fixtures/\n*.csv\n",
+		"pkg/api/.cwcignore": "zz_generated.go\n",
+	})
+
+	newMatcher(t, root, "*.md\n")
+
+	matcher, err := pathmatcher.NewIgnoreFilePathMatcher(".cwcignore")
+	require.NoError(t, err)
+
+	assert.True(t, matcher.Match("testdata/fixtures/a.json"))
+	assert.True(t, matcher.MatchDir("fixtures"))
+	assert.True(t, matcher.Match("data/big.csv"))
+	assert.True(t, matcher.Match("pkg/api/zz_generated.go"))
+	assert.False(t, matcher.Match("pkg/zz_generated.go"))
+	assert.False(t, matcher.Match("main.go"))
+	// neither .gitignore nor the global excludes apply
+	assert.False(t, matcher.Match("debug.log"))
+	assert.False(t, matcher.Match("README.md"))
+}
@@ -0,0 +1,122 @@
+package pathmatcher
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// gitignorePattern is a line of a .gitignore file.
+type gitignorePattern struct {
+	re *regexp.Regexp
+	// the pattern includes the paths it matches again
+	negate bool
+	// the pattern ends with a slash and only matches directories
+	dirOnly bool
+	// the pattern has no slash and matches the name at any depth below the base
+	nameOnly bool
+	// the directory of the .gitignore file relative to the root, slash separated, empty at the root
+	base string
+}
+
+// match reports whether the pattern matches the path, which is slash separated and relative to the root.
+func (p gitignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	if p.base != "" {
+		var ok bool
+
+		rel, ok = strings.CutPrefix(rel, p.base+"/")
+		if !ok {
+			return false
+		}
+	}
+
+	if p.nameOnly {
+		rel = path.Base(rel)
+	}
+
+	return p.re.MatchString(rel)
+}
+
+// mayMatchBelow reports whether the pattern could match a path inside the directory, which is
+// relative to the root. It errs on the side of yes, looking at the literal beginning of the pattern.
+func (p gitignorePattern) mayMatchBelow(dir string) bool {
+	rel := dir + "/"
+
+	if p.base != "" {
+		base := p.base + "/"
+		if strings.HasPrefix(base, rel) {
+			// the pattern is in a directory inside the directory
+			return true
+		}
+
+		var ok bool
+
+		rel, ok = strings.CutPrefix(rel, base)
+		if !ok {
+			return false
+		}
+	}
+
+	if p.nameOnly {
+		return true
+	}
+
+	prefix, _ := p.re.LiteralPrefix()
+
+	return strings.HasPrefix(prefix, rel) || strings.HasPrefix(rel, prefix)
+}
+
+// parseGitignore parses the patterns of a .gitignore file in the base directory. Invalid patterns are skipped.
+func parseGitignore(data string, base string) []gitignorePattern {
+	var patterns []gitignorePattern
+
+	for _, line := range strings.Split(data, "\n") {
+		line = trimTrailingSpaces(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := gitignorePattern{re: nil, negate: false, dirOnly: false, nameOnly: false, base: base}
+
+		if rest, ok := strings.CutPrefix(line, "!"); ok {
+			pattern.negate = true
+			line = rest
+		}
+
+		if rest, ok := strings.CutSuffix(line, "/"); ok {
+			pattern.dirOnly = true
+			line = rest
+		}
+
+		if line == "" {
+			continue
+		}
+
+		// a slash at the beginning or in the middle anchors the pattern to the base
+		pattern.nameOnly = !strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		re, err := regexp.Compile(globToRegexp(line, false))
+		if err != nil {
+			continue
+		}
+
+		pattern.re = re
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
+
+// trimTrailingSpaces removes the trailing spaces of a line, unless they are escaped with a backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+
+	return line
+}
@@ -0,0 +1,163 @@
+package pathmatcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GlobPathMatcher matches paths with a glob, such as **/*.go, in which * and ? do not match
+// a slash, ** matches any number of directories and {a,b} matches either alternative.
+type GlobPathMatcher struct {
+	re *regexp.Regexp
+	// dirRe matches the directory of a glob ending in /**, whose contents all match
+	dirRe *regexp.Regexp
+}
+
+func NewGlobPathMatcher(pattern string) (*GlobPathMatcher, error) {
+	pattern = strings.TrimPrefix(pattern, "./")
+
+	re, err := regexp.Compile(globToRegexp(pattern, true))
+	if err != nil {
+		return nil, fmt.Errorf("error compiling glob pattern: %w", err)
+	}
+
+	var dirRe *regexp.Regexp
+
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok && dir != "" {
+		dirRe, err = regexp.Compile(globToRegexp(dir, true))
+		if err != nil {
+			return nil, fmt.Errorf("error compiling glob pattern: %w", err)
+		}
+	}
+
+	return &GlobPathMatcher{re: re, dirRe: dirRe}, nil
+}
+
+func (g *GlobPathMatcher) Match(path string) bool {
+	return g.re.MatchString(path)
+}
+
+// MatchDir reports whether the glob matches everything in the directory. Only a glob ending in /**,
+// such as vendor/** or **/node_modules/**, tells that for sure, others never match a directory.
+func (g *GlobPathMatcher) MatchDir(path string) bool {
+	return g.dirRe != nil && g.dirRe.MatchString(path)
+}
+
+// globToRegexp converts a glob to a regular expression. A * or ? does not match a slash, while
+// ** as a whole path segment matches any number of directories. With braces, {a,b} matches
+// either alternative, .gitignore files have no such syntax.
+func globToRegexp(glob string, braces bool) string { //nolint:cyclop,funlen,gocognit
+	runes := []rune(glob)
+	depth := 0
+
+	var expr strings.Builder
+
+	expr.WriteString("^")
+
+	for i := 0; i < len(runes); {
+		switch runes[i] {
+		case '*':
+			end := i
+			for end < len(runes) && runes[end] == '*' {
+				end++
+			}
+
+			segment := end-i > 1 && (i == 0 || runes[i-1] == '/') && (end == len(runes) || runes[end] == '/')
+
+			switch {
+			case segment && end == len(runes):
+				expr.WriteString(".*")
+			case segment:
+				expr.WriteString("(?:.*/)?")
+				// the slash is part of the match
+				end++
+			default:
+				expr.WriteString("[^/]*")
+			}
+
+			i = end
+		case '?':
+			expr.WriteString("[^/]")
+			i++
+		case '[':
+			class, end := bracketExpression(runes, i)
+			expr.WriteString(class)
+			i = end
+		case '{', ',', '}':
+			switch {
+			case !braces || (runes[i] != '{' && depth == 0):
+				expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+			case runes[i] == '{':
+				depth++
+
+				expr.WriteString("(?:")
+			case runes[i] == ',':
+				expr.WriteString("|")
+			default:
+				depth--
+
+				expr.WriteString(")")
+			}
+
+			i++
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+			i++
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(runes[i])))
+			i++
+		}
+	}
+
+	expr.WriteString("$")
+
+	return expr.String()
+}
+
+// bracketExpression converts the bracket expression starting at the index to a character class,
+// and returns the index after it. An unclosed bracket matches itself.
+func bracketExpression(runes []rune, start int) (string, int) {
+	i := start + 1
+
+	negate := i < len(runes) && (runes[i] == '!' || runes[i] == '^')
+	if negate {
+		i++
+	}
+
+	var class strings.Builder
+
+	// a closing bracket right at the beginning is part of the class
+	for first := true; i < len(runes) && (first || runes[i] != ']'); first = false {
+		char := runes[i]
+		if char == '\\' && i+1 < len(runes) {
+			i++
+			char = runes[i]
+		}
+
+		switch {
+		case char == '-' && !first && i+1 < len(runes) && runes[i+1] != ']':
+			class.WriteRune('-')
+		case char == '-':
+			class.WriteString(`\-`)
+		default:
+			class.WriteString(regexp.QuoteMeta(string(char)))
+		}
+
+		i++
+	}
+
+	if i >= len(runes) {
+		return regexp.QuoteMeta("["), start + 1
+	}
+
+	if negate {
+		return "[^/" + class.String() + "]", i + 1
+	}
+
+	return "[" + class.String() + "]", i + 1
+}
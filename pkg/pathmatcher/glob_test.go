@@ -0,0 +1,97 @@
+package pathmatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+func TestGlobPathMatcher_Match(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{glob: "**/*.go", path: "main.go", want: true},
+		{glob: "**/*.go", path: "pkg/chat/chat.go", want: true},
+		{glob: "**/*.go", path: "pkg/chat/chat.go.orig", want: false},
+		{glob: "*.go", path: "main.go", want: true},
+		{glob: "*.go", path: "cmd/main.go", want: false},
+		{glob: "./cmd/*.go", path: "cmd/main.go", want: true},
+		{glob: "pkg/**", path: "pkg/a/b.go", want: true},
+		{glob: "pkg/**/*_test.go", path: "pkg/a_test.go", want: true},
+		{glob: "pkg/**/*_test.go", path: "pkg/a/b/c_test.go", want: true},
+		{glob: "pkg/**/*_test.go", path: "cmd/a_test.go", want: false},
+		{glob: "**/*.{ts,tsx}", path: "web/app.tsx", want: true},
+		{glob: "**/*.{ts,tsx}", path: "web/app.js", want: false},
+		{glob: "file?.txt", path: "file1.txt", want: true},
+		{glob: "file?.txt", path: "file10.txt", want: false},
+		{glob: "file[0-9].txt", path: "file5.txt", want: true},
+		{glob: "file[!0-9].txt", path: "file5.txt", want: false},
+		{glob: "docs/*.md", path: "docs/a/b.md", want: false},
+		{glob: "a+b(c).md", path: "a+b(c).md", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.path, func(t *testing.T) {
+			matcher, err := pathmatcher.NewGlobPathMatcher(tt.glob)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, matcher.Match(tt.path))
+		})
+	}
+}
+
+func TestGlobPathMatcher_MatchDir(t *testing.T) {
+	tests := []struct {
+		glob string
+		dir  string
+		want bool
+	}{
+		{glob: "vendor/**", dir: "vendor", want: true},
+		{glob: "**/node_modules/**", dir: "web/node_modules", want: true},
+		{glob: "**/*.go", dir: "pkg", want: false},
+		// only the files directly in docs match, not those in its subdirectories
+		{glob: "docs/*", dir: "docs", want: false},
+		{glob: "vendor/**/*.go", dir: "vendor", want: false},
+		{glob: "**/*[^o]", dir: "pkg", want: false},
+		{glob: "*/**", dir: "pkg", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.dir, func(t *testing.T) {
+			matcher, err := pathmatcher.NewGlobPathMatcher(tt.glob)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, matcher.MatchDir(tt.dir))
+		})
+	}
+}
+
+func TestNewPatternPathMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: `\.go$`, path: "cmd/main.go", want: true},
+		{pattern: `re:\.go$`, path: "cmd/main.go", want: true},
+		{pattern: "glob:**/*.go", path: "cmd/main.go", want: true},
+		{pattern: "glob:*.go", path: "cmd/main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			matcher, err := pathmatcher.NewPatternPathMatcher(tt.pattern)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, matcher.Match(tt.path))
+		})
+	}
+
+	_, err := pathmatcher.NewPatternPathMatcher("glob:{a,b")
+	assert.ErrorContains(t, err, "error compiling glob pattern")
+}
@@ -1,5 +1,31 @@
 package pathmatcher
 
+import (
+	"strings"
+)
+
+// Prefixes selecting the syntax of a pattern, a pattern without a prefix is a regular expression.
+const (
+	RegexPrefix = "re:"
+	GlobPrefix  = "glob:"
+)
+
 type PathMatcher interface {
 	Match(path string) bool
 }
+
+// DirMatcher is implemented by the matchers that can tell whether a directory is matched as a
+// whole, which lets the directories that are excluded entirely be skipped without walking them.
+type DirMatcher interface {
+	MatchDir(path string) bool
+}
+
+// NewPatternPathMatcher creates a matcher for a glob prefixed with glob:, or a regular expression,
+// optionally prefixed with re:.
+func NewPatternPathMatcher(pattern string) (PathMatcher, error) { //nolint:ireturn
+	if glob, ok := strings.CutPrefix(pattern, GlobPrefix); ok {
+		return NewGlobPathMatcher(glob)
+	}
+
+	return NewRegexPathMatcher(strings.TrimPrefix(pattern, RegexPrefix))
+}
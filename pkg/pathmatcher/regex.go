@@ -3,10 +3,17 @@ package pathmatcher
 import (
 	"fmt"
 	"regexp"
+	"regexp/syntax"
+	"strings"
 )
 
 type RegexPathMatcher struct {
 	re *regexp.Regexp
+	// dir is the directory path, ending in a slash, that the whole expression comes down to,
+	// such as vendor/ or ^web/node_modules/.*, everything in a directory containing it matches
+	dir string
+	// anchored is set when the expression starts with ^, the directory must then start with dir
+	anchored bool
 }
 
 func NewRegexPathMatcher(pattern string) (*RegexPathMatcher, error) {
@@ -15,9 +22,66 @@ func NewRegexPathMatcher(pattern string) (*RegexPathMatcher, error) {
 		return nil, fmt.Errorf("error compiling regex pattern: %w", err)
 	}
 
-	return &RegexPathMatcher{re: re}, nil
+	dir, anchored := literalDir(pattern)
+
+	return &RegexPathMatcher{re: re, dir: dir, anchored: anchored}, nil
 }
 
 func (r *RegexPathMatcher) Match(path string) bool {
 	return r.re.MatchString(path)
 }
+
+// MatchDir reports whether the expression matches everything in the directory. Only an expression
+// that is a literal directory path ending in a slash, optionally anchored with ^ and followed by .*,
+// tells that for sure, others never match a directory.
+func (r *RegexPathMatcher) MatchDir(path string) bool {
+	if r.dir == "" {
+		return false
+	}
+
+	// every path in the directory starts with it and a slash, and so contains what the directory does
+	if r.anchored {
+		return strings.HasPrefix(path+"/", r.dir)
+	}
+
+	return strings.Contains(path+"/", r.dir)
+}
+
+// literalDir returns the directory path the expression comes down to, and whether it is anchored
+// to the start of the path. It returns an empty path for any other expression.
+func literalDir(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	anchored := len(subs) > 0 && subs[0].Op == syntax.OpBeginText
+	if anchored {
+		subs = subs[1:]
+	}
+
+	// a trailing .* matches the rest of any path in the directory
+	if n := len(subs); n > 0 && subs[n-1].Op == syntax.OpStar && isAnyChar(subs[n-1].Sub[0]) {
+		subs = subs[:n-1]
+	}
+
+	if len(subs) != 1 || subs[0].Op != syntax.OpLiteral || subs[0].Flags&syntax.FoldCase != 0 {
+		return "", false
+	}
+
+	dir := string(subs[0].Rune)
+	if !strings.HasSuffix(dir, "/") {
+		return "", false
+	}
+
+	return dir, anchored
+}
+
+func isAnyChar(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpAnyChar || re.Op == syntax.OpAnyCharNotNL
+}
@@ -0,0 +1,58 @@
+package pathmatcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/pathmatcher"
+)
+
+func TestRegexPathMatcher_MatchDir(t *testing.T) {
+	tests := []struct {
+		pattern string
+		dir     string
+		want    bool
+	}{
+		{`vendor/`, "vendor", true},
+		{`vendor/`, "third_party/vendor", true},
+		{`vendor/`, "vendor/github.com", true},
+		{`vendor/`, "pkg", false},
+		{`vendor/`, "vendors", false},
+		{`node_modules/`, "/home/user/web/node_modules", true},
+		{`^vendor/`, "vendor", true},
+		{`^vendor/`, "pkg/vendor", false},
+		{`^web/node_modules/.*`, "web/node_modules", true},
+		{`build\.out/`, "build.out", true},
+		// only a plain directory path excludes everything in the directory
+		{`vendor/.*\.go$`, "vendor", false},
+		{`vendor`, "vendor", false},
+		{`(?i)vendor/`, "vendor", false},
+		{`vendor/|docs/`, "vendor", false},
+	}
+
+	for _, tt := range tests {
+		matcher, err := pathmatcher.NewRegexPathMatcher(tt.pattern)
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.want, matcher.MatchDir(tt.dir), "%s %s", tt.pattern, tt.dir)
+
+		if tt.want {
+			// the files in a matched directory are all matched
+			assert.True(t, matcher.Match(tt.dir+"/main.go"), "%s %s", tt.pattern, tt.dir)
+			assert.True(t, matcher.Match(tt.dir+"/a/b/README"), "%s %s", tt.pattern, tt.dir)
+		}
+	}
+}
+
+func TestRegexPathMatcher_NoMatchDir(t *testing.T) {
+	// these match a path made up inside any directory, but not the files in it
+	for _, pattern := range []string{`(^|/)[^./]+$`, `[^o]$`} {
+		matcher, err := pathmatcher.NewRegexPathMatcher(pattern)
+		require.NoError(t, err)
+
+		assert.False(t, matcher.Match("pkg/main.go"), pattern)
+		assert.False(t, pathmatcher.NewCompoundPathMatcher(matcher).MatchDir("pkg"), pattern)
+	}
+}
@@ -0,0 +1,88 @@
+package redact
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// minEntropy is the Shannon entropy in bits per character above which a string looks random.
+// Random base64 of the length checked has about 4.3 to 5, words and identifiers stay below 4.
+const minEntropy = 4.0
+
+// minClassChanges is the share of characters followed by one of another class, such as a digit after a letter,
+// above which a string looks random. Random base64 changes class after about 60% of the characters, identifiers
+// only where words begin.
+const minClassChanges = 0.45
+
+// digestPrefixes start the checksums of lock files, such as go.sum and package-lock.json, which look random.
+var digestPrefixes = []string{"sha1-", "sha256-", "sha384-", "sha512-"} //nolint:gochecknoglobals
+
+// isHighEntropy reports whether the string looks like a random token. It must mix upper and lower
+// case letters and digits, which leaves out hex checksums and uuids, and change between them often,
+// which leaves out identifiers. Paths are left out too.
+func isHighEntropy(value string) bool {
+	if strings.Contains(value, "/") || strings.HasPrefix(value, placeholderPrefix) {
+		return false
+	}
+
+	for _, prefix := range digestPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return false
+		}
+	}
+
+	var (
+		upper, lower, digit bool
+		changes             int
+		previous            rune
+	)
+
+	for i, char := range value {
+		upper = upper || unicode.IsUpper(char)
+		lower = lower || unicode.IsLower(char)
+		digit = digit || unicode.IsDigit(char)
+
+		if i > 0 && charClass(char) != charClass(previous) {
+			changes++
+		}
+
+		previous = char
+	}
+
+	mixed := upper && lower && digit
+
+	return mixed && float64(changes)/float64(len(value)-1) >= minClassChanges && entropy(value) >= minEntropy
+}
+
+func charClass(char rune) int {
+	switch {
+	case unicode.IsUpper(char):
+		return 'A'
+	case unicode.IsLower(char):
+		return 'a'
+	case unicode.IsDigit(char):
+		return '0'
+	default:
+		return 0
+	}
+}
+
+// entropy returns the Shannon entropy of the string in bits per character.
+func entropy(value string) float64 {
+	counts := make(map[rune]int)
+	for _, char := range value {
+		counts[char]++
+	}
+
+	length := float64(len(value))
+
+	var bits float64
+
+	for _, count := range counts {
+		p := float64(count) / length
+		bits -= p * math.Log2(p)
+	}
+
+	return bits
+}
@@ -0,0 +1,131 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/intility/cwc/pkg/errors"
+	cwcui "github.com/intility/cwc/pkg/ui"
+)
+
+const (
+	sessionFileExtension  = ".json"
+	sessionDirPermissions = 0o700
+	sessionFilePermission = 0o600
+)
+
+// FileStore stores each session as a json file in a directory.
+type FileStore struct {
+	// Dir is the directory containing the session files
+	Dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (s *FileStore) Save(session *Session) error {
+	err := os.MkdirAll(s.Dir, sessionDirPermissions)
+	if err != nil {
+		return fmt.Errorf("error creating session directory: %w", err)
+	}
+
+	session.UpdatedAt = time.Now()
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling session: %w", err)
+	}
+
+	err = os.WriteFile(s.path(session.ID), data, sessionFilePermission)
+	if err != nil {
+		return fmt.Errorf("error writing session file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Load(id string) (*Session, error) {
+	if !isValidID(id) {
+		return nil, errors.SessionNotFoundError{SessionID: id}
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, errors.SessionNotFoundError{SessionID: id}
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading session file: %w", err)
+	}
+
+	var session Session
+
+	err = json.Unmarshal(data, &session)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding session %s: %w", id, err)
+	}
+
+	return &session, nil
+}
+
+func (s *FileStore) List() ([]Session, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		// no sessions have been saved yet
+		return []Session{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading session directory: %w", err)
+	}
+
+	sessions := make([]Session, 0, len(entries))
+	ui := cwcui.NewUI(cwcui.WithWriter(os.Stderr)) //nolint:varnamelen
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != sessionFileExtension {
+			continue
+		}
+
+		// a corrupt or partially written session does not hide the others
+		session, err := s.Load(strings.TrimSuffix(entry.Name(), sessionFileExtension))
+		if err != nil {
+			ui.PrintMessage(fmt.Sprintf("skipping %s: %s\n", entry.Name(), err), cwcui.MessageTypeWarning)
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *FileStore) Delete(id string) error {
+	if !isValidID(id) {
+		return errors.SessionNotFoundError{SessionID: id}
+	}
+
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return errors.SessionNotFoundError{SessionID: id}
+	} else if err != nil {
+		return fmt.Errorf("error removing session file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.Dir, id+sessionFileExtension)
+}
+
+// isValidID guards against ids that would resolve outside the session directory.
+func isValidID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\`) && id != "." && id != ".."
+}
@@ -0,0 +1,115 @@
+package sessions_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/sessions"
+)
+
+func TestFileStore_SaveAndLoad(t *testing.T) {
+	store := sessions.NewFileStore(t.TempDir())
+
+	session, err := sessions.NewSession()
+	require.NoError(t, err)
+
+	session.TemplateName = "default"
+	session.Files = []string{"main.go"}
+	session.Messages = []sessions.Message{
+		{Role: "system", Content: "you are helpful"},
+		{Role: "user", Content: "hello"},
+	}
+
+	require.NoError(t, store.Save(session))
+
+	loaded, err := store.Load(session.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, session.ID, loaded.ID)
+	assert.Equal(t, "default", loaded.TemplateName)
+	assert.Equal(t, []string{"main.go"}, loaded.Files)
+	assert.Equal(t, session.Messages, loaded.Messages)
+	assert.Equal(t, "hello", loaded.FirstUserMessage())
+}
+
+func TestFileStore_List(t *testing.T) {
+	store := sessions.NewFileStore(t.TempDir())
+
+	sessionList, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, sessionList)
+
+	older := &sessions.Session{ID: "older"}
+	newer := &sessions.Session{ID: "newer"}
+
+	require.NoError(t, store.Save(older))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, store.Save(newer))
+
+	sessionList, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, sessionList, 2)
+	assert.Equal(t, "newer", sessionList[0].ID)
+	assert.Equal(t, "older", sessionList[1].ID)
+}
+
+func TestFileStore_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr func(t *testing.T, err error)
+	}{
+		{
+			name: "existing session",
+			id:   "existing",
+			wantErr: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "unknown session",
+			id:   "unknown",
+			wantErr: func(t *testing.T, err error) {
+				assert.True(t, errors.IsSessionNotFoundError(err))
+			},
+		},
+		{
+			name: "path traversal",
+			id:   "../existing",
+			wantErr: func(t *testing.T, err error) {
+				assert.True(t, errors.IsSessionNotFoundError(err))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := sessions.NewFileStore(t.TempDir())
+			require.NoError(t, store.Save(&sessions.Session{ID: "existing"}))
+
+			tt.wantErr(t, store.Delete(tt.id))
+		})
+	}
+}
+
+func TestFileStore_ListSkipsCorruptSessions(t *testing.T) {
+	dir := t.TempDir()
+	store := sessions.NewFileStore(dir)
+
+	require.NoError(t, store.Save(&sessions.Session{ID: "good"}))
+
+	// a session written only partially, for example when cwc was killed while saving it
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partial.json"), []byte(`{"id": "partial", "mess`), 0o600))
+
+	sessionList, err := store.List()
+
+	require.NoError(t, err)
+	require.Len(t, sessionList, 1)
+	assert.Equal(t, "good", sessionList[0].ID)
+}
@@ -0,0 +1,106 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/intility/cwc/pkg/chat"
+)
+
+const (
+	idTimeFormat   = "20060102-150405"
+	idSuffixLength = 2
+)
+
+type Session struct {
+	// ID uniquely identifies the session and is used as the file name on disk
+	ID string `json:"id"`
+
+	// CreatedAt is the time the session was started
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is the time the session was last saved
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// TemplateName is the name of the template used to create the system message
+	TemplateName string `json:"templateName"`
+
+	// TemplateVariables are the variables fed into the template
+	TemplateVariables map[string]string `json:"templateVariables,omitempty"`
+
+	// IncludePatterns are the patterns used to include files in the context
+	IncludePatterns []string `json:"includePatterns,omitempty"`
+
+	// ExcludePatterns are the patterns used to exclude files from the context
+	ExcludePatterns []string `json:"excludePatterns,omitempty"`
+
+	// Paths are the search scopes used when gathering files
+	Paths []string `json:"paths"`
+
+	// Parameters are the model and sampling parameters in effect for the session
+	Parameters chat.Parameters `json:"parameters"`
+
+	// Files are the paths of the files included in the context
+	Files []string `json:"files"`
+
+	// Messages is the conversation history, including the system message
+	Messages []Message `json:"messages"`
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// NewSession creates a new session with a unique id.
+func NewSession() (*Session, error) {
+	suffix := make([]byte, idSuffixLength)
+
+	_, err := rand.Read(suffix)
+	if err != nil {
+		return nil, fmt.Errorf("error generating session id: %w", err)
+	}
+
+	now := time.Now()
+
+	return &Session{
+		ID:                now.Format(idTimeFormat) + "-" + hex.EncodeToString(suffix),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		TemplateName:      "",
+		TemplateVariables: nil,
+		IncludePatterns:   nil,
+		ExcludePatterns:   nil,
+		Paths:             []string{},
+		Parameters:        chat.Parameters{},
+		Files:             []string{},
+		Messages:          []Message{},
+	}, nil
+}
+
+// FirstUserMessage returns the first message sent by the user, or an empty string.
+func (s *Session) FirstUserMessage() string {
+	for _, message := range s.Messages {
+		if message.Role == "user" {
+			return message.Content
+		}
+	}
+
+	return ""
+}
+
+type Store interface {
+	// Save persists the session, overwriting any previous version
+	Save(session *Session) error
+
+	// Load returns the session with the given id
+	Load(id string) (*Session, error)
+
+	// List returns all stored sessions, most recently updated first
+	List() ([]Session, error)
+
+	// Delete removes the session with the given id
+	Delete(id string) error
+}
@@ -0,0 +1,116 @@
+package slashcommand
+
+import (
+	stderrors "errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/intility/cwc/pkg/errors"
+)
+
+// Prefix starts every command typed at the chat prompt.
+const Prefix = "/"
+
+// commandInput matches a command name at the start of the input, followed by a space or nothing, so that
+// a message starting with a path such as /etc/hosts is not taken for a command.
+var commandInput = regexp.MustCompile(`^/[\w-]+(?: |$)`)
+
+// ErrExit is returned by a command handler to end the chat.
+var ErrExit = stderrors.New("exit requested")
+
+// Handler runs a command with the text following the command name, trimmed of whitespace.
+type Handler func(args string) error
+
+// Command is a command the user can type at the chat prompt, such as /help.
+type Command struct {
+	// Name is the name without the leading slash
+	Name string
+	// Args describes the arguments in the help text, for example "<path>"
+	Args        string
+	Description string
+	Run         Handler
+}
+
+// Usage returns the command as it is typed, including the arguments.
+func (c Command) Usage() string {
+	if c.Args == "" {
+		return Prefix + c.Name
+	}
+
+	return Prefix + c.Name + " " + c.Args
+}
+
+// Registry holds the available commands and dispatches input to them.
+type Registry struct {
+	commands map[string]Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: make(map[string]Command),
+	}
+}
+
+// Register adds a command, replacing any command with the same name.
+func (r *Registry) Register(command Command) {
+	r.commands[command.Name] = command
+}
+
+// Commands returns the registered commands sorted by name.
+func (r *Registry) Commands() []Command {
+	commands := make([]Command, 0, len(r.commands))
+	for _, command := range r.commands {
+		commands = append(commands, command)
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+
+	return commands
+}
+
+// IsCommand reports whether the input should be handled as a command rather than sent as a message.
+func IsCommand(input string) bool {
+	return commandInput.MatchString(strings.TrimSpace(input))
+}
+
+// Execute runs the command in the input. It returns an UnknownCommandError
+// for commands that are not registered.
+func (r *Registry) Execute(input string) error {
+	name, args := parse(input)
+
+	command, ok := r.commands[name]
+	if !ok {
+		return errors.UnknownCommandError{Name: Prefix + name}
+	}
+
+	return command.Run(args)
+}
+
+// Complete returns the commands starting with the partially typed input, for tab completion.
+// Input that already holds arguments has no completions.
+func (r *Registry) Complete(input string) []string {
+	if !strings.HasPrefix(input, Prefix) || strings.ContainsAny(input, " \t") {
+		return nil
+	}
+
+	var completions []string
+
+	for _, command := range r.Commands() {
+		if strings.HasPrefix(Prefix+command.Name, input) {
+			completions = append(completions, Prefix+command.Name)
+		}
+	}
+
+	return completions
+}
+
+func parse(input string) (string, string) {
+	input = strings.TrimPrefix(strings.TrimSpace(input), Prefix)
+
+	name, args, _ := strings.Cut(input, " ")
+
+	return name, strings.TrimSpace(args)
+}
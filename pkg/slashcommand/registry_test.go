@@ -0,0 +1,94 @@
+package slashcommand_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/slashcommand"
+)
+
+func newTestRegistry(received *[]string) *slashcommand.Registry {
+	registry := slashcommand.NewRegistry()
+
+	for _, name := range []string{"model", "help", "save"} {
+		name := name
+		registry.Register(slashcommand.Command{
+			Name:        name,
+			Args:        "",
+			Description: name + " command",
+			Run: func(args string) error {
+				*received = append(*received, name+":"+args)
+				return nil
+			},
+		})
+	}
+
+	return registry
+}
+
+func TestRegistry_Execute(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        []string
+		wantUnknown bool
+	}{
+		{name: "command without arguments", input: "/help", want: []string{"help:"}},
+		{name: "command with arguments", input: "  /model   gpt-4o  ", want: []string{"model:gpt-4o"}},
+		{name: "arguments with spaces", input: "/save my notes.md", want: []string{"save:my notes.md"}},
+		{name: "unknown command", input: "/frobnicate now", wantUnknown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []string
+
+			err := newTestRegistry(&received).Execute(tt.input)
+
+			if tt.wantUnknown {
+				assert.True(t, errors.IsUnknownCommandError(err))
+				assert.Empty(t, received)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, received)
+		})
+	}
+}
+
+func TestRegistry_Complete(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "all commands", input: "/", want: []string{"/help", "/model", "/save"}},
+		{name: "unique prefix", input: "/mo", want: []string{"/model"}},
+		{name: "no match", input: "/x", want: nil},
+		{name: "not a command", input: "mo", want: nil},
+		{name: "arguments are not completed", input: "/model gp", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []string
+
+			assert.Equal(t, tt.want, newTestRegistry(&received).Complete(tt.input))
+		})
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	assert.True(t, slashcommand.IsCommand("/help"))
+	assert.True(t, slashcommand.IsCommand("  /help"))
+	assert.True(t, slashcommand.IsCommand("/add pkg/a.go"))
+	assert.True(t, slashcommand.IsCommand("/unknown-command"))
+	assert.False(t, slashcommand.IsCommand("/etc/hosts is broken"))
+	assert.False(t, slashcommand.IsCommand("/"))
+	assert.False(t, slashcommand.IsCommand("what does /etc/hosts do?"))
+}
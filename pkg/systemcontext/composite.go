@@ -0,0 +1,140 @@
+package systemcontext
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
+)
+
+// ContextSection is a part of the context, labelled to tell the model where it comes from.
+type ContextSection struct {
+	Label     string
+	Retriever ContextRetriever
+}
+
+// CompositeContextRetriever merges the context of several retrievers, such as piped
+// input and matched files. When more than one of them has content, each is put
+// under a heading with its label, otherwise the single context is used as it is.
+type CompositeContextRetriever struct {
+	sections []ContextSection
+}
+
+func NewCompositeContextRetriever(sections ...ContextSection) *CompositeContextRetriever {
+	return &CompositeContextRetriever{
+		sections: sections,
+	}
+}
+
+func (r *CompositeContextRetriever) RetrieveContext() (string, error) {
+	var labelled, contexts []string
+
+	for _, section := range r.sections {
+		ctx, err := section.Retriever.RetrieveContext()
+		if err != nil {
+			return "", fmt.Errorf("error retrieving %s: %w", strings.ToLower(section.Label), err)
+		}
+
+		if strings.TrimSpace(ctx) == "" {
+			continue
+		}
+
+		contexts = append(contexts, ctx)
+		labelled = append(labelled, "## "+section.Label+"\n\n"+strings.TrimRight(ctx, "\n")+"\n")
+	}
+
+	if len(contexts) == 1 {
+		return contexts[0], nil
+	}
+
+	return strings.Join(labelled, "\n"), nil
+}
+
+// Files returns the files gathered by the sections that include files, a file included by
+// several sections, such as a changed file and its diff, is returned once.
+func (r *CompositeContextRetriever) Files() []filetree.File {
+	return r.collect(func(retriever ContextRetriever) []filetree.File {
+		if provider, ok := retriever.(interface{ Files() []filetree.File }); ok {
+			return provider.Files()
+		}
+
+		return nil
+	})
+}
+
+// SentFiles returns the files sent as they are by the sections, with the secrets redacted. The
+// files whose changes are sent as a diff are left out.
+func (r *CompositeContextRetriever) SentFiles() []filetree.File {
+	return r.collect(func(retriever ContextRetriever) []filetree.File {
+		if provider, ok := retriever.(interface{ SentFiles() []filetree.File }); ok {
+			return provider.SentFiles()
+		}
+
+		return nil
+	})
+}
+
+// collect returns the files of every section, each path once.
+func (r *CompositeContextRetriever) collect(sectionFiles func(ContextRetriever) []filetree.File) []filetree.File {
+	var files []filetree.File
+
+	seen := map[string]bool{}
+
+	for _, section := range r.sections {
+		for _, file := range sectionFiles(section.Retriever) {
+			path := filepath.ToSlash(filepath.Clean(file.Path))
+			if seen[path] {
+				continue
+			}
+
+			seen[path] = true
+
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// ContextFileRetriever reads a file given with --context-file, of any type, as context.
+type ContextFileRetriever struct {
+	path string
+	// the contents read by the last call to RetrieveContext
+	data []byte
+}
+
+func NewContextFileRetriever(path string) *ContextFileRetriever {
+	return &ContextFileRetriever{
+		path: path,
+		data: nil,
+	}
+}
+
+// Files returns the file read by the last call to RetrieveContext.
+func (r *ContextFileRetriever) Files() []filetree.File {
+	if r.data == nil {
+		return nil
+	}
+
+	return []filetree.File{{Path: r.path, Type: "", Data: r.data}}
+}
+
+func (r *ContextFileRetriever) RetrieveContext() (string, error) {
+	data, err := os.ReadFile(r.path)
+	if stderrors.Is(err, fs.ErrNotExist) {
+		return "", errors.FileNotExistError{FileName: r.path}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error reading context file: %w", err)
+	}
+
+	r.data = data
+
+	return string(data), nil
+}
@@ -0,0 +1,123 @@
+package systemcontext_test
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/systemcontext"
+)
+
+func TestCompositeContextRetriever_RetrieveContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		contexts []string
+		want     string
+	}{
+		{
+			name:     "labels each section with content",
+			contexts: []string{"the diff\n", "File tree: ...", "log line"},
+			want: "## Standard input\n\nthe diff\n\n" +
+				"## Files\n\nFile tree: ...\n\n" +
+				"## Context file build.log\n\nlog line\n",
+		},
+		{
+			name:     "a single context is used as it is",
+			contexts: []string{"", "File tree: ...", " \n"},
+			want:     "File tree: ...",
+		},
+		{
+			name:     "no context",
+			contexts: []string{"", "", ""},
+			want:     "",
+		},
+	}
+
+	labels := []string{"Standard input", "Files", "Context file build.log"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sections []systemcontext.ContextSection
+
+			for i, ctx := range tt.contexts {
+				retriever := mocks.NewContextRetriever(t)
+				retriever.EXPECT().RetrieveContext().Return(ctx, nil)
+
+				sections = append(sections, systemcontext.ContextSection{Label: labels[i], Retriever: retriever})
+			}
+
+			got, err := systemcontext.NewCompositeContextRetriever(sections...).RetrieveContext()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompositeContextRetriever_Error(t *testing.T) {
+	failing := mocks.NewContextRetriever(t)
+	failing.EXPECT().RetrieveContext().Return("", stderrors.New("boom"))
+
+	retriever := systemcontext.NewCompositeContextRetriever(
+		systemcontext.ContextSection{Label: "Files", Retriever: failing},
+	)
+
+	_, err := retriever.RetrieveContext()
+
+	assert.ErrorContains(t, err, "error retrieving files: boom")
+}
+
+func TestContextFileRetriever_RetrieveContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.log")
+	require.NoError(t, os.WriteFile(path, []byte("log line\n"), 0o600))
+
+	got, err := systemcontext.NewContextFileRetriever(path).RetrieveContext()
+	require.NoError(t, err)
+	assert.Equal(t, "log line\n", got)
+
+	_, err = systemcontext.NewContextFileRetriever(strings.TrimSuffix(path, ".log")).RetrieveContext()
+	assert.True(t, errors.IsFileNotExistError(err))
+}
+
+func TestCompositeContextRetriever_Files(t *testing.T) {
+	retriever := systemcontext.NewCompositeContextRetriever(setupSecretSections(t, redact.ModeRedact)...)
+
+	_, err := retriever.RetrieveContext()
+	require.NoError(t, err)
+
+	files := retriever.Files()
+	require.Len(t, files, 2)
+	assert.Equal(t, ".env", files[0].Path)
+	assert.Equal(t, "deploy.log", filepath.Base(files[1].Path))
+
+	// the files are kept as they are on disk, the secrets are only redacted in the context
+	for _, file := range files {
+		assert.Contains(t, string(file.Data), "DB_PASSWORD="+secret)
+	}
+}
+
+func TestCompositeContextRetriever_FilesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build.log")
+	require.NoError(t, os.WriteFile(path, []byte("log line\n"), 0o600))
+
+	retriever := systemcontext.NewCompositeContextRetriever(
+		systemcontext.ContextSection{Label: "Log", Retriever: systemcontext.NewContextFileRetriever(path)},
+		systemcontext.ContextSection{Label: "Log again", Retriever: systemcontext.NewContextFileRetriever(path)},
+	)
+
+	assert.Empty(t, retriever.Files())
+
+	_, err := retriever.RetrieveContext()
+	require.NoError(t, err)
+
+	assert.Equal(t, []filetree.File{{Path: path, Type: "", Data: []byte("log line\n")}}, retriever.Files())
+}
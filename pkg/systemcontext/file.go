@@ -2,59 +2,186 @@ package systemcontext
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 
 	"github.com/intility/cwc/pkg/config"
 	"github.com/intility/cwc/pkg/errors"
 	"github.com/intility/cwc/pkg/filetree"
 	"github.com/intility/cwc/pkg/pathmatcher"
+	"github.com/intility/cwc/pkg/redact"
 	"github.com/intility/cwc/pkg/ui"
 )
 
+// Files in the repository, using the gitignore syntax, with the paths to exclude from the
+// context and the paths ignored by git to include in it anyway.
+const (
+	cwcignoreFile  = ".cwcignore"
+	cwcincludeFile = ".cwcinclude"
+)
+
 type FileContextRetriever struct {
-	ui             ui.UI
-	cfgProvider    config.Provider
-	includePattern string
-	excludePattern string
-	searchScopes   []string
-	contextPrinter func(fileTree string, files []filetree.File)
+	ui              ui.UI
+	noticeUI        ui.UI
+	cfgProvider     config.Provider
+	includePatterns []string
+	excludePatterns []string
+	searchScopes    []string
+	contextPrinter  func(fileTree string, files []filetree.File)
+	fileSelector    FileSelector
+	ref             string
+	redactor        *redact.Redactor
+	files           []filetree.File
+	// the files as they were sent, with the secrets redacted
+	sent []filetree.File
+	// added and dropped during the chat
+	extraIncludes []pathmatcher.PathMatcher
+	extraScopes   []string
+	droppedPaths  []string
 }
 
 type FileContextRetrieverOptions struct {
-	CfgProvider    config.Provider
-	IncludePattern string
-	ExcludePattern string
-	SearchScopes   []string
-	ContextPrinter func(fileTree string, files []filetree.File)
+	CfgProvider config.Provider
+	// regular expressions, or globs prefixed with glob:, a file matching any of them is included,
+	// all files are included when there are none
+	IncludePatterns []string
+	// patterns like the include patterns, a file matching any of them is excluded
+	ExcludePatterns []string
+	SearchScopes    []string
+	ContextPrinter  func(fileTree string, files []filetree.File)
+	// selects the files to gather instead of the search scopes, when set
+	FileSelector FileSelector
+	// a git revision to read the files from instead of the working tree, when set
+	Ref string
+	// masks the secrets in the files, shared by retrievers so that a secret gets the same
+	// placeholder in each, a new one is used when nil
+	Redactor *redact.Redactor
+}
+
+// FileSelector selects the files of the context, such as the files changed in git.
+type FileSelector interface {
+	SelectFiles() ([]string, error)
 }
 
 func NewFileContextRetriever(opts FileContextRetrieverOptions) *FileContextRetriever {
+	redactor := opts.Redactor
+	if redactor == nil {
+		redactor = redact.NewRedactor()
+	}
+
 	return &FileContextRetriever{
-		ui:             ui.NewUI(),
-		cfgProvider:    opts.CfgProvider,
-		includePattern: opts.IncludePattern,
-		excludePattern: opts.ExcludePattern,
-		searchScopes:   opts.SearchScopes,
-		contextPrinter: opts.ContextPrinter,
+		ui:              ui.NewUI(),
+		noticeUI:        ui.NewUI(ui.WithWriter(os.Stderr)),
+		cfgProvider:     opts.CfgProvider,
+		includePatterns: opts.IncludePatterns,
+		excludePatterns: opts.ExcludePatterns,
+		searchScopes:    opts.SearchScopes,
+		contextPrinter:  opts.ContextPrinter,
+		fileSelector:    opts.FileSelector,
+		ref:             opts.Ref,
+		redactor:        redactor,
+		files:           []filetree.File{},
+		extraIncludes:   nil,
+		extraScopes:     nil,
+		droppedPaths:    nil,
 	}
 }
 
+// AddInclude adds a file path, a regular expression or a glob prefixed with glob: to the files to include.
+// Files outside the search scopes are added to the scopes.
+func (r *FileContextRetriever) AddInclude(pathOrPattern string) error {
+	info, err := os.Stat(pathOrPattern)
+	if err == nil && !info.IsDir() {
+		path := filepath.ToSlash(filepath.Clean(pathOrPattern))
+
+		matcher, err := pathmatcher.NewRegexPathMatcher("^" + regexp.QuoteMeta(path) + "$")
+		if err != nil {
+			return fmt.Errorf("error creating include matcher: %w", err)
+		}
+
+		r.extraIncludes = append(r.extraIncludes, matcher)
+		r.droppedPaths = slices.DeleteFunc(r.droppedPaths, func(dropped string) bool { return dropped == path })
+
+		if !r.inSearchScope(path) {
+			r.extraScopes = append(r.extraScopes, path)
+		}
+
+		return nil
+	}
+
+	matcher, err := pathmatcher.NewPatternPathMatcher(pathOrPattern)
+	if err != nil {
+		return fmt.Errorf("error creating include matcher: %w", err)
+	}
+
+	r.extraIncludes = append(r.extraIncludes, matcher)
+
+	return nil
+}
+
+// Drop removes a file from the context.
+func (r *FileContextRetriever) Drop(path string) error {
+	path = filepath.ToSlash(filepath.Clean(path))
+
+	included := slices.ContainsFunc(r.files, func(file filetree.File) bool {
+		return filepath.ToSlash(file.Path) == path
+	})
+	if !included {
+		return errors.FileNotExistError{FileName: path}
+	}
+
+	r.droppedPaths = append(r.droppedPaths, path)
+
+	return nil
+}
+
+func (r *FileContextRetriever) inSearchScope(path string) bool {
+	return inScopes(r.searchScopes, path)
+}
+
+// Files returns the files gathered by the last call to RetrieveContext.
+func (r *FileContextRetriever) Files() []filetree.File {
+	return r.files
+}
+
+// SentFiles returns the files gathered by the last call to RetrieveContext as they were sent,
+// with the secrets redacted.
+func (r *FileContextRetriever) SentFiles() []filetree.File {
+	return r.sent
+}
+
 func (r *FileContextRetriever) RetrieveContext() (string, error) {
 	files, rootNode, err := r.gatherContext()
 	if err != nil {
 		return "", fmt.Errorf("error gathering context: %w", err)
 	}
 
+	r.files = files
 	fileTree := filetree.GenerateFileTree(rootNode, "", true)
 
 	if r.contextPrinter != nil {
 		r.contextPrinter(fileTree, files)
 	}
 
-	ctx := r.createContext(fileTree, files)
+	// the files keep their contents, changes to them are applied to the real files
+	redacted, err := r.redactSecrets(files)
+	if err != nil {
+		return "", err
+	}
+
+	r.sent = redacted
+	ctx := r.createContext(fileTree, redacted)
 
 	return ctx, nil
 }
 
+// redactSecrets masks the secrets in the files, or refuses to send them, as configured.
+func (r *FileContextRetriever) redactSecrets(files []filetree.File) ([]filetree.File, error) {
+	return redactFiles(r.cfgProvider, r.redactor, r.noticeUI, files)
+}
+
 func (r *FileContextRetriever) createContext(fileTree string, files []filetree.File) string {
 	contextStr := "File tree:\n\n"
 	contextStr += "```\n" + fileTree + "```\n\n"
@@ -72,8 +199,8 @@ func (r *FileContextRetriever) gatherContext() ([]filetree.File, *filetree.FileN
 	var excludeMatchers []pathmatcher.PathMatcher
 
 	// add exclude flag to excludeMatchers
-	if r.excludePattern != "" {
-		excludeMatcher, err := pathmatcher.NewRegexPathMatcher(r.excludePattern)
+	for _, pattern := range r.excludePatterns {
+		excludeMatcher, err := pathmatcher.NewPatternPathMatcher(pattern)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error creating exclude matcher: %w", err)
 		}
@@ -88,17 +215,57 @@ func (r *FileContextRetriever) gatherContext() ([]filetree.File, *filetree.FileN
 
 	excludeMatchers = append(excludeMatchers, excludeMatchersFromConfig...)
 
+	for _, path := range r.droppedPaths {
+		droppedMatcher, err := pathmatcher.NewRegexPathMatcher("^" + regexp.QuoteMeta(path) + "$")
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating exclude matcher: %w", err)
+		}
+
+		excludeMatchers = append(excludeMatchers, droppedMatcher)
+	}
+
 	excludeMatcher := pathmatcher.NewCompoundPathMatcher(excludeMatchers...)
 
-	includeMatcher, err := pathmatcher.NewRegexPathMatcher(r.includePattern)
+	includePatterns := r.includePatterns
+	if len(includePatterns) == 0 {
+		includePatterns = []string{".*"}
+	}
+
+	includeMatcher := pathmatcher.NewCompoundPathMatcher()
+
+	for _, pattern := range includePatterns {
+		matcher, err := pathmatcher.NewPatternPathMatcher(pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating include matcher: %w", err)
+		}
+
+		includeMatcher.Add(matcher)
+	}
+
+	for _, matcher := range r.extraIncludes {
+		includeMatcher.Add(matcher)
+	}
+
+	scopes := slices.Clone(r.searchScopes)
+
+	if r.fileSelector != nil {
+		scopes, err = r.fileSelector.SelectFiles()
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+	}
+
+	languages, err := r.languageOverrides()
 	if err != nil {
-		return nil, nil, fmt.Errorf("error creating include matcher: %w", err)
+		return nil, nil, err
 	}
 
 	files, rootNode, err := filetree.GatherFiles(&filetree.FileGatherOptions{
 		IncludeMatcher: includeMatcher,
 		ExcludeMatcher: excludeMatcher,
-		PathScopes:     r.searchScopes,
+		PathScopes:     append(scopes, r.extraScopes...),
+		Ref:            r.ref,
+		Languages:      languages,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("error gathering files: %w", err)
@@ -107,6 +274,23 @@ func (r *FileContextRetriever) gatherContext() ([]filetree.File, *filetree.FileN
 	return files, rootNode, nil
 }
 
+// languageOverrides returns the language overrides of the user config with those of the project on top.
+func (r *FileContextRetriever) languageOverrides() (*filetree.LanguageOverrides, error) {
+	cfg, err := r.cfgProvider.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	project, err := config.LoadProjectConfig(config.DefaultProjectConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("error loading project config: %w", err)
+	}
+
+	languages := cfg.Languages.Merge(project.Languages)
+
+	return &languages, nil
+}
+
 func (r *FileContextRetriever) excludeMatchersFromConfig() ([]pathmatcher.PathMatcher, error) {
 	var excludeMatchers []pathmatcher.PathMatcher
 
@@ -116,28 +300,26 @@ func (r *FileContextRetriever) excludeMatchersFromConfig() ([]pathmatcher.PathMa
 	}
 
 	if cfg.UseGitignore {
-		gitignoreMatcher, err := pathmatcher.NewGitignorePathMatcher()
+		// .cwcinclude brings back files that are ignored by git but useful as context
+		gitignoreMatcher, err := pathmatcher.NewGitignorePathMatcher(pathmatcher.WithIncludeFile(cwcincludeFile))
 		if err != nil {
-			switch {
-			case errors.IsGitNotInstalledError(err):
-				r.ui.PrintMessage("warning: git not found in PATH, skipping .gitignore\n", ui.MessageTypeWarning)
-			case errors.IsNotAGitRepositoryError(err):
-				r.ui.PrintMessage("warning: not a git repository, skipping .gitignore\n", ui.MessageTypeWarning)
-			default:
-				return nil, fmt.Errorf("error creating gitignore matcher: %w", err)
-			}
+			return nil, fmt.Errorf("error creating gitignore matcher: %w", err)
 		}
 
 		excludeMatchers = append(excludeMatchers, gitignoreMatcher)
 	}
 
-	if cfg.ExcludeGitDir {
-		gitDirMatcher, err := pathmatcher.NewRegexPathMatcher(`^\.git(/|\\)`)
-		if err != nil {
-			return nil, fmt.Errorf("error creating git directory matcher: %w", err)
-		}
+	// .cwcignore excludes files from the context for everyone working in the repository
+	cwcignoreMatcher, err := pathmatcher.NewIgnoreFilePathMatcher(cwcignoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s matcher: %w", cwcignoreFile, err)
+	}
 
-		excludeMatchers = append(excludeMatchers, gitDirMatcher)
+	excludeMatchers = append(excludeMatchers, cwcignoreMatcher)
+
+	if cfg.ExcludeGitDir {
+		// at any depth, for scopes given as absolute paths or outside the repository and nested clones
+		excludeMatchers = append(excludeMatchers, pathmatcher.NewGitDirPathMatcher())
 	}
 
 	return excludeMatchers, nil
@@ -0,0 +1,66 @@
+package systemcontext_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/systemcontext"
+)
+
+func TestFileContextRetriever_ExcludesGitDirInAbsoluteScope(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	repo := t.TempDir()
+	files := map[string]string{
+		"main.go":                      "package main\n",
+		".git/config":                  "[core]\n\tbare = false\n",
+		".git/HEAD":                    "ref: refs/heads/main\n",
+		".git/logs/HEAD":               "0000000 1111111 Test <test@example.com>\tcommit\n",
+		".git/hooks/pre-commit.sample": "#!/bin/sh\nexit 0\n",
+		"vendor/lib/.git/packed-refs":  "# pack-refs with: peeled\n",
+		"vendor/lib/lib.go":            "package lib\n",
+		"vendor/lib/.gitignore":        "*.log\n",
+	}
+
+	for name, data := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	}
+
+	cfgProvider := mocks.NewProvider(t)
+	cfgProvider.EXPECT().GetConfig().Return(config.NewConfig("", ""), nil)
+
+	retriever := systemcontext.NewFileContextRetriever(systemcontext.FileContextRetrieverOptions{
+		CfgProvider:     cfgProvider,
+		IncludePatterns: nil,
+		ExcludePatterns: nil,
+		SearchScopes:    []string{repo},
+		ContextPrinter:  nil,
+		FileSelector:    nil,
+		Ref:             "",
+		Redactor:        nil,
+	})
+
+	_, err = retriever.RetrieveContext()
+	require.NoError(t, err)
+
+	var gathered []string
+	for _, file := range retriever.Files() {
+		rel, err := filepath.Rel(repo, file.Path)
+		require.NoError(t, err)
+
+		gathered = append(gathered, filepath.ToSlash(rel))
+	}
+
+	assert.ElementsMatch(t, []string{"main.go", "vendor/lib/.gitignore", "vendor/lib/lib.go"}, gathered)
+}
@@ -0,0 +1,189 @@
+package systemcontext
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/git"
+)
+
+// maxCommits is the number of recent commits of the selected files added to the context.
+const maxCommits = 20
+
+// GitSelection selects the files of the context from git instead of walking the search scopes.
+type GitSelection struct {
+	// Staged selects the files with staged changes.
+	Staged bool
+	// Ref selects the files changed since the revision.
+	Ref string
+	// Diff adds the changes of the selected files to the context.
+	Diff bool
+}
+
+// Any reports whether files are selected from git.
+func (s GitSelection) Any() bool {
+	return s.Staged || s.Ref != ""
+}
+
+// GitContextRetriever selects the files with staged changes or changed since a revision,
+// and retrieves their changes and the recent commits touching them as context.
+type GitContextRetriever struct {
+	selection GitSelection
+	// restricts the selection to these paths
+	scopes []string
+	// the files whose changes were retrieved by the last call to RetrieveContext
+	files []filetree.File
+}
+
+func NewGitContextRetriever(selection GitSelection, scopes []string) *GitContextRetriever {
+	return &GitContextRetriever{
+		selection: selection,
+		scopes:    scopes,
+		files:     nil,
+	}
+}
+
+// Files returns the files whose changes were retrieved by the last call to RetrieveContext, as
+// they are in the working tree. The deleted files are left out.
+func (r *GitContextRetriever) Files() []filetree.File {
+	return r.files
+}
+
+// SelectFiles returns the selected files, relative to the working directory.
+func (r *GitContextRetriever) SelectFiles() ([]string, error) {
+	var (
+		paths []string
+		err   error
+	)
+
+	if r.selection.Staged {
+		paths, err = git.StagedFiles()
+	} else {
+		paths, err = git.ChangedFiles(r.selection.Ref)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting files from git: %w", err)
+	}
+
+	var selected []string
+
+	for _, path := range paths {
+		path = filepath.FromSlash(path)
+		if inScopes(r.scopes, path) {
+			selected = append(selected, path)
+		}
+	}
+
+	return selected, nil
+}
+
+// RetrieveContext returns the changes of the selected files, when asked for,
+// and the recent commits that changed them.
+func (r *GitContextRetriever) RetrieveContext() (string, error) {
+	r.files = nil
+
+	paths, err := r.SelectFiles()
+	if err != nil {
+		return "", err
+	}
+
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	var sections []string
+
+	if r.selection.Diff {
+		diff, err := r.diff(paths)
+		if err != nil {
+			return "", fmt.Errorf("error getting git diff: %w", err)
+		}
+
+		if diff != "" {
+			sections = append(sections, r.diffTitle()+":\n\n```diff\n"+diff+"```\n")
+
+			r.files, err = readChangedFiles(paths)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	revisionRange := "HEAD"
+	if !r.selection.Staged {
+		revisionRange = r.selection.Ref + "..HEAD"
+	}
+
+	log, err := git.Log(revisionRange, maxCommits, paths)
+	if err != nil {
+		return "", fmt.Errorf("error getting git log: %w", err)
+	}
+
+	if log != "" {
+		sections = append(sections, r.logTitle()+":\n\n```\n"+log+"```\n")
+	}
+
+	return strings.Join(sections, "\n"), nil
+}
+
+// readChangedFiles reads the changed files from the working tree, skipping the deleted ones.
+func readChangedFiles(paths []string) ([]filetree.File, error) {
+	files := make([]filetree.File, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path) // #nosec
+		if stderrors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading changed file: %w", err)
+		}
+
+		files = append(files, filetree.File{Path: path, Type: "", Data: data})
+	}
+
+	return files, nil
+}
+
+func (r *GitContextRetriever) diff(paths []string) (string, error) {
+	if r.selection.Staged {
+		return git.StagedDiff(paths) //nolint:wrapcheck
+	}
+
+	return git.Diff(r.selection.Ref, paths) //nolint:wrapcheck
+}
+
+func (r *GitContextRetriever) diffTitle() string {
+	if r.selection.Staged {
+		return "Staged changes"
+	}
+
+	return "Changes since " + r.selection.Ref
+}
+
+func (r *GitContextRetriever) logTitle() string {
+	if r.selection.Staged {
+		return "Recent commits of these files"
+	}
+
+	return "Commits since " + r.selection.Ref
+}
+
+// inScopes reports whether the path is within one of the scopes.
+func inScopes(scopes []string, path string) bool {
+	for _, scope := range scopes {
+		rel, err := filepath.Rel(scope, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+
+	return false
+}
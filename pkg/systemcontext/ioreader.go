@@ -7,19 +7,28 @@ import (
 
 type IOReaderContextRetriever struct {
 	io.Reader
+	size int
 }
 
 func NewIOReaderContextRetriever(reader io.Reader) *IOReaderContextRetriever {
 	return &IOReaderContextRetriever{
 		Reader: reader,
+		size:   0,
 	}
 }
 
+// Size returns the number of bytes read so far.
+func (r *IOReaderContextRetriever) Size() int {
+	return r.size
+}
+
 func (r *IOReaderContextRetriever) RetrieveContext() (string, error) {
 	bytes, err := io.ReadAll(r)
 	if err != nil {
 		return "", fmt.Errorf("error reading from io.Reader: %w", err)
 	}
 
+	r.size += len(bytes)
+
 	return string(bytes), nil
 }
@@ -0,0 +1,31 @@
+package systemcontext
+
+// RecordingContextRetriever remembers the context returned by the wrapped
+// retriever so it can be inspected after the system message is generated.
+type RecordingContextRetriever struct {
+	retriever ContextRetriever
+	context   string
+}
+
+func NewRecordingContextRetriever(retriever ContextRetriever) *RecordingContextRetriever {
+	return &RecordingContextRetriever{
+		retriever: retriever,
+		context:   "",
+	}
+}
+
+func (r *RecordingContextRetriever) RetrieveContext() (string, error) {
+	ctx, err := r.retriever.RetrieveContext()
+	if err != nil {
+		return "", err //nolint:wrapcheck // the error is passed through unchanged
+	}
+
+	r.context = ctx
+
+	return ctx, nil
+}
+
+// LastContext returns the context from the last successful call to RetrieveContext.
+func (r *RecordingContextRetriever) LastContext() string {
+	return r.context
+}
@@ -0,0 +1,127 @@
+package systemcontext
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/filetree"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/ui"
+)
+
+// RedactingContextRetriever masks the secrets in the context of the wrapped retriever, such as
+// a git diff, a context file or piped input, or refuses to send it, as configured.
+type RedactingContextRetriever struct {
+	retriever   ContextRetriever
+	name        string
+	cfgProvider config.Provider
+	redactor    *redact.Redactor
+	noticeUI    ui.UI
+	// the files of the wrapped retriever sent as the whole context, with the secrets redacted
+	sent []filetree.File
+}
+
+// NewRedactingContextRetriever wraps the retriever, the secrets found are reported under the name.
+// The redactor is shared with the other sections so that a secret gets the same placeholder in each.
+func NewRedactingContextRetriever(
+	retriever ContextRetriever,
+	name string,
+	cfgProvider config.Provider,
+	redactor *redact.Redactor,
+) *RedactingContextRetriever {
+	return &RedactingContextRetriever{
+		retriever:   retriever,
+		name:        name,
+		cfgProvider: cfgProvider,
+		redactor:    redactor,
+		noticeUI:    ui.NewUI(ui.WithWriter(os.Stderr)),
+		sent:        nil,
+	}
+}
+
+func (r *RedactingContextRetriever) RetrieveContext() (string, error) {
+	ctx, err := r.retriever.RetrieveContext()
+	if err != nil {
+		return "", err //nolint:wrapcheck // the error is passed through unchanged
+	}
+
+	redacted, err := redactFiles(r.cfgProvider, r.redactor, r.noticeUI, []filetree.File{
+		{Path: r.name, Data: []byte(ctx), Type: ""},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// a context file is sent as it is, the files of a diff are not sent themselves
+	r.sent = nil
+
+	for _, file := range r.Files() {
+		if string(file.Data) == ctx {
+			file.Data = redacted[0].Data
+			r.sent = append(r.sent, file)
+		}
+	}
+
+	return string(redacted[0].Data), nil
+}
+
+// Files returns the files of the wrapped retriever, when it has any.
+func (r *RedactingContextRetriever) Files() []filetree.File {
+	if provider, ok := r.retriever.(interface{ Files() []filetree.File }); ok {
+		return provider.Files()
+	}
+
+	return nil
+}
+
+// SentFiles returns the files of the wrapped retriever that were sent as the context, as they were sent.
+func (r *RedactingContextRetriever) SentFiles() []filetree.File {
+	return r.sent
+}
+
+// redactFiles masks the secrets in the files, or refuses to send them, as configured.
+func redactFiles(
+	cfgProvider config.Provider,
+	redactor *redact.Redactor,
+	noticeUI ui.UI,
+	files []filetree.File,
+) ([]filetree.File, error) {
+	cfg, err := cfgProvider.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	if cfg.Secrets == redact.ModeOff {
+		return files, nil
+	}
+
+	redacted := make([]filetree.File, 0, len(files))
+
+	var found []string
+
+	for _, file := range files {
+		data, findings := redactor.Redact(file.Path, file.Data)
+		for _, finding := range findings {
+			found = append(found, finding.String())
+		}
+
+		file.Data = data
+		redacted = append(redacted, file)
+	}
+
+	if len(found) == 0 {
+		return files, nil
+	}
+
+	if cfg.Secrets == redact.ModeBlock {
+		return nil, errors.SecretsFoundError{Secrets: found}
+	}
+
+	noticeUI.PrintMessage(fmt.Sprintf("redacted %d secrets: %s\n", len(found), strings.Join(found, ", ")),
+		ui.MessageTypeWarning)
+
+	return redacted, nil
+}
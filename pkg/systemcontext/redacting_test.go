@@ -0,0 +1,101 @@
+package systemcontext_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/mocks"
+	"github.com/intility/cwc/pkg/config"
+	"github.com/intility/cwc/pkg/errors"
+	"github.com/intility/cwc/pkg/git"
+	"github.com/intility/cwc/pkg/redact"
+	"github.com/intility/cwc/pkg/systemcontext"
+)
+
+const secret = "s3cr3t-Passw0rd"
+
+// setupSecretSections returns a git changes section with a secret in the diff of .env, and a
+// context file section with the same secret.
+func setupSecretSections(t *testing.T, mode string) []systemcontext.ContextSection {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	run := func(args ...string) {
+		_, err := git.Run(args...)
+		require.NoError(t, err)
+	}
+
+	run("init", "--quiet")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test")
+	require.NoError(t, os.WriteFile(".env", []byte("DEBUG=true\n"), 0o600))
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Initial commit")
+	require.NoError(t, os.WriteFile(".env", []byte("DEBUG=true\nDB_PASSWORD="+secret+"\n"), 0o600))
+	run("add", ".env")
+
+	contextFile := filepath.Join(dir, "deploy.log")
+	require.NoError(t, os.WriteFile(contextFile, []byte("DB_PASSWORD="+secret+"\n"), 0o600))
+
+	cfg := config.NewConfig("", "")
+	cfg.Secrets = mode
+
+	cfgProvider := mocks.NewProvider(t)
+	cfgProvider.EXPECT().GetConfig().Return(cfg, nil)
+
+	redactor := redact.NewRedactor()
+	gitRetriever := systemcontext.NewGitContextRetriever(
+		systemcontext.GitSelection{Staged: true, Ref: "", Diff: true}, []string{"."})
+
+	return []systemcontext.ContextSection{
+		{
+			Label:     "Git changes",
+			Retriever: systemcontext.NewRedactingContextRetriever(gitRetriever, "git changes", cfgProvider, redactor),
+		},
+		{
+			Label: "Context file deploy.log",
+			Retriever: systemcontext.NewRedactingContextRetriever(
+				systemcontext.NewContextFileRetriever(contextFile), "deploy.log", cfgProvider, redactor),
+		},
+	}
+}
+
+func TestRedactingContextRetriever_Redact(t *testing.T) {
+	sections := setupSecretSections(t, redact.ModeRedact)
+
+	retriever := systemcontext.NewCompositeContextRetriever(sections...)
+	ctx, err := retriever.RetrieveContext()
+
+	require.NoError(t, err)
+	assert.NotContains(t, ctx, secret)
+	assert.Contains(t, ctx, "+DB_PASSWORD=[REDACTED:secret-assignment:1]")
+	assert.Contains(t, ctx, "## Context file deploy.log\n\nDB_PASSWORD=[REDACTED:secret-assignment:1]\n")
+
+	// the context file is sent as it is, redacted, the changed .env only as its diff
+	sent := retriever.SentFiles()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "deploy.log", filepath.Base(sent[0].Path))
+	assert.Equal(t, "DB_PASSWORD=[REDACTED:secret-assignment:1]\n", string(sent[0].Data))
+}
+
+func TestRedactingContextRetriever_Block(t *testing.T) {
+	sections := setupSecretSections(t, redact.ModeBlock)
+
+	_, err := systemcontext.NewCompositeContextRetriever(sections...).RetrieveContext()
+
+	assert.True(t, errors.IsSecretsFoundError(err))
+}
@@ -38,6 +38,16 @@ func NewTemplatedSystemMessageGenerator(
 	}
 }
 
+// SetTemplateName changes the template used by subsequent calls to GenerateSystemMessage.
+func (smg *TemplatedSystemMessageGenerator) SetTemplateName(templateName string) {
+	smg.templateName = templateName
+}
+
+// TemplateName returns the name of the template in use.
+func (smg *TemplatedSystemMessageGenerator) TemplateName() string {
+	return smg.templateName
+}
+
 func (smg *TemplatedSystemMessageGenerator) GenerateSystemMessage() (string, error) {
 	ctx, err := smg.contextRetriever.RetrieveContext()
 	if err != nil {
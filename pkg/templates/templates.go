@@ -1,5 +1,7 @@
 package templates
 
+import "github.com/intility/cwc/pkg/chat"
+
 type Template struct {
 	// Name is the name of the template
 	Name string `yaml:"name"`
@@ -15,6 +17,9 @@ type Template struct {
 
 	// Variables is a list of input variables for the template
 	Variables []TemplateVariable `yaml:"variables"`
+
+	// Parameters override the model and sampling parameters from the config
+	chat.Parameters `yaml:",inline"`
 }
 
 type TemplateVariable struct {
@@ -0,0 +1,62 @@
+package tokens
+
+import (
+	"fmt"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	// tokensPerMessage is the overhead the chat format adds around each message.
+	tokensPerMessage = 3
+	// tokensPerReply primes the assistant reply.
+	tokensPerReply = 3
+	// fallbackEncoding is used for models without a known tokenizer, such as local models.
+	fallbackEncoding = tiktoken.MODEL_CL100K_BASE
+)
+
+//nolint:gochecknoinits
+func init() {
+	// use the encodings embedded in the binary instead of downloading them on first use
+	tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+}
+
+// Counter counts the tokens of text as seen by a model.
+type Counter interface {
+	Count(text string) int
+}
+
+type TiktokenCounter struct {
+	encoding *tiktoken.Tiktoken
+}
+
+// NewCounter creates a counter using the tokenizer of the given model. Models
+// with an unknown tokenizer are approximated with the cl100k_base encoding.
+func NewCounter(model string) (*TiktokenCounter, error) {
+	encoding, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		encoding, err = tiktoken.GetEncoding(fallbackEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("error loading tokenizer: %w", err)
+		}
+	}
+
+	return &TiktokenCounter{encoding: encoding}, nil
+}
+
+func (c *TiktokenCounter) Count(text string) int {
+	return len(c.encoding.EncodeOrdinary(text))
+}
+
+// CountMessages estimates the prompt tokens of a chat completion request with the given messages.
+func CountMessages(counter Counter, messages []openai.ChatCompletionMessage) int {
+	total := tokensPerReply
+
+	for _, message := range messages {
+		total += tokensPerMessage + counter.Count(message.Role) + counter.Count(message.Content)
+	}
+
+	return total
+}
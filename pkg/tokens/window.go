@@ -0,0 +1,71 @@
+package tokens
+
+import "strings"
+
+// contextWindows maps model name prefixes to the size of their context window in tokens.
+// Azure deployment style names (gpt-35-turbo) are listed alongside the OpenAI names.
+var contextWindows = map[string]int{ //nolint:gochecknoglobals
+	"gpt-3.5-turbo":      16385,
+	"gpt-35-turbo":       16385,
+	"gpt-3.5-turbo-0613": 4096,
+	"gpt-35-turbo-0613":  4096,
+	"gpt-4":              8192,
+	"gpt-4-0613":         8192,
+	"gpt-4-32k":          32768,
+	"gpt-4-1106-preview": 128000,
+	"gpt-4-0125-preview": 128000,
+	"gpt-4-turbo":        128000,
+	"gpt-4-vision":       128000,
+	"gpt-4o":             128000,
+	"gpt-4.1":            1047576,
+	"gpt-4.5":            128000,
+	"gpt-5":              400000,
+	"o1":                 200000,
+	"o1-mini":            128000,
+	"o3":                 200000,
+	"o4-mini":            200000,
+	"llama-2":            4096,
+	"llama-3":            8192,
+	"llama-3.1":          131072,
+	"mistral":            32768,
+	"mixtral":            32768,
+	"codellama":          16384,
+	"qwen2.5":            32768,
+	"deepseek-coder":     16384,
+}
+
+// ContextWindow returns the context window of the model, matched by the longest known prefix.
+func ContextWindow(model string) (int, bool) {
+	model = strings.ToLower(model)
+
+	var (
+		window    int
+		bestMatch int
+	)
+
+	for prefix, size := range contextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestMatch {
+			window, bestMatch = size, len(prefix)
+		}
+	}
+
+	return window, bestMatch > 0
+}
+
+// Limit returns the maximum number of tokens a request may use: the model's
+// context window or the budget, whichever is smaller. A budget of zero means
+// no budget. The second return value is false if no limit is known.
+func Limit(model string, budget int) (int, bool) {
+	window, known := ContextWindow(model)
+
+	switch {
+	case !known && budget <= 0:
+		return 0, false
+	case !known:
+		return budget, true
+	case budget > 0 && budget < window:
+		return budget, true
+	default:
+		return window, true
+	}
+}
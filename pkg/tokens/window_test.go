@@ -0,0 +1,66 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/cwc/pkg/tokens"
+)
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		model      string
+		wantWindow int
+		wantKnown  bool
+	}{
+		{model: "gpt-4", wantWindow: 8192, wantKnown: true},
+		{model: "gpt-4-turbo-preview", wantWindow: 128000, wantKnown: true},
+		{model: "gpt-4-32k-0613", wantWindow: 32768, wantKnown: true},
+		{model: "GPT-4o-mini", wantWindow: 128000, wantKnown: true},
+		{model: "gpt-35-turbo", wantWindow: 16385, wantKnown: true},
+		{model: "my-local-model", wantWindow: 0, wantKnown: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			window, known := tokens.ContextWindow(tt.model)
+			assert.Equal(t, tt.wantWindow, window)
+			assert.Equal(t, tt.wantKnown, known)
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     string
+		budget    int
+		wantLimit int
+		wantKnown bool
+	}{
+		{name: "window without budget", model: "gpt-4", budget: 0, wantLimit: 8192, wantKnown: true},
+		{name: "budget below window", model: "gpt-4", budget: 4000, wantLimit: 4000, wantKnown: true},
+		{name: "budget above window", model: "gpt-4", budget: 10000, wantLimit: 8192, wantKnown: true},
+		{name: "budget for unknown model", model: "local", budget: 2000, wantLimit: 2000, wantKnown: true},
+		{name: "unknown model without budget", model: "local", budget: 0, wantLimit: 0, wantKnown: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, known := tokens.Limit(tt.model, tt.budget)
+			assert.Equal(t, tt.wantLimit, limit)
+			assert.Equal(t, tt.wantKnown, known)
+		})
+	}
+}
+
+func TestTiktokenCounter_Count(t *testing.T) {
+	counter, err := tokens.NewCounter("gpt-4")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, counter.Count("hello world"))
+
+	fallback, err := tokens.NewCounter("my-local-model")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fallback.Count("hello world"))
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// OpenEditor lets the user edit text in $VISUAL or $EDITOR, falling back to vi
+// or notepad, and returns the saved text.
+func OpenEditor(initial string) (string, error) {
+	file, err := os.CreateTemp("", "cwc-message-*.md")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(initial)
+	if err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("error writing temporary file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", fmt.Errorf("error closing temporary file: %w", err)
+	}
+
+	editor := strings.Fields(editorCommand())
+
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...) // #nosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("error running editor %s: %w", editor[0], err)
+	}
+
+	text, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("error reading temporary file: %w", err)
+	}
+
+	return string(text), nil
+}
+
+func editorCommand() string {
+	for _, variable := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(variable)); editor != "" {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+
+	return "vi"
+}
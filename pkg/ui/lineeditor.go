@@ -0,0 +1,478 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+const (
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyBackspace = 8
+	keyTab       = '\t'
+	keyCtrlJ     = '\n'
+	keyEnter     = '\r'
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+
+	// a line ending with the continuation character continues on the next line
+	continuation = '\\'
+
+	bracketedPasteOn  = "\x1b[?2004h"
+	bracketedPasteOff = "\x1b[?2004l"
+	pasteStart        = "200~"
+	pasteEnd          = "201~"
+	clearToEnd        = "\r\x1b[J"
+
+	defaultTerminalWidth = 80
+)
+
+// Completer returns the completions for the text before the cursor.
+type Completer func(text string) []string
+
+// LineEditor reads the user's messages, which may span multiple lines.
+//
+// On a terminal, Enter sends the message while Alt-Enter, Ctrl-J or a line ending
+// with a backslash start a new line. Pasted text arrives whole, however many lines it
+// has, using the terminal's bracketed paste mode. The arrow keys browse earlier messages
+// and Tab completes commands. Without a terminal, lines ending with a backslash continue
+// the message on the next line.
+type LineEditor struct {
+	prompt   string
+	complete Completer
+	reader   *bufio.Reader
+	writer   io.Writer
+	keys     bool
+	makeRaw  func() (func(), error)
+	width    func() int
+	history  []string
+}
+
+// NewLineEditor creates a line editor for stdin, interpreting key presses when stdin is a terminal.
+func NewLineEditor(prompt string, complete Completer) *LineEditor {
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) {
+		return &LineEditor{
+			prompt:   prompt,
+			complete: complete,
+			reader:   bufio.NewReader(os.Stdin),
+			writer:   os.Stdout,
+			keys:     false,
+			makeRaw:  nil,
+			width:    nil,
+			history:  nil,
+		}
+	}
+
+	editor := NewTerminalLineEditor(os.Stdin, os.Stdout, prompt, complete)
+
+	editor.makeRaw = func() (func(), error) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, fmt.Errorf("error setting terminal to raw mode: %w", err)
+		}
+
+		return func() { _ = term.Restore(fd, state) }, nil
+	}
+
+	editor.width = func() int {
+		width, _, err := term.GetSize(fd)
+		if err != nil || width <= 0 {
+			return defaultTerminalWidth
+		}
+
+		return width
+	}
+
+	return editor
+}
+
+// NewTerminalLineEditor creates a line editor interpreting the key presses
+// read from reader, for a terminal that is already in raw mode.
+func NewTerminalLineEditor(reader io.Reader, writer io.Writer, prompt string, complete Completer) *LineEditor {
+	return &LineEditor{
+		prompt:   prompt,
+		complete: complete,
+		reader:   bufio.NewReader(reader),
+		writer:   writer,
+		keys:     true,
+		makeRaw:  func() (func(), error) { return func() {}, nil },
+		width:    func() int { return defaultTerminalWidth },
+		history:  nil,
+	}
+}
+
+// ReadLine prints the prompt and reads a message. It returns io.EOF when the
+// input ends or the user presses Ctrl-C or Ctrl-D at an empty prompt.
+func (e *LineEditor) ReadLine() (string, error) {
+	if !e.keys {
+		return e.readLines()
+	}
+
+	restore, err := e.makeRaw()
+	if err != nil {
+		return "", err
+	}
+
+	defer restore()
+
+	fmt.Fprint(e.writer, bracketedPasteOn)
+	defer fmt.Fprint(e.writer, bracketedPasteOff)
+
+	line, err := e.readKeys()
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(line) != "" {
+		e.history = append(e.history, line)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// readLines reads lines until one does not end with the continuation character.
+func (e *LineEditor) readLines() (string, error) {
+	fmt.Fprint(e.writer, e.prompt)
+
+	var message strings.Builder
+
+	for {
+		line, err := e.reader.ReadString('\n')
+		if err != nil && line == "" {
+			if message.Len() > 0 {
+				return strings.TrimSpace(message.String()), nil
+			}
+
+			return "", io.EOF
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if strings.HasSuffix(line, string(continuation)) {
+			message.WriteString(strings.TrimSuffix(line, string(continuation)) + "\n")
+			continue
+		}
+
+		message.WriteString(line)
+
+		return strings.TrimSpace(message.String()), nil
+	}
+}
+
+// editState is the message being edited and where it is drawn on the screen.
+type editState struct {
+	text []rune
+	// rows above the line the cursor is on, and the width of that line
+	rowsAbove int
+	lineWidth int
+	// index into the history while browsing it, len(history) for the new message
+	historyIndex int
+	draft        []rune
+}
+
+func (e *LineEditor) readKeys() (string, error) { //nolint:cyclop,funlen
+	state := &editState{
+		text:         nil,
+		rowsAbove:    0,
+		lineWidth:    0,
+		historyIndex: len(e.history),
+		draft:        nil,
+	}
+
+	e.redraw(state)
+
+	for {
+		key, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err //nolint:wrapcheck // io.EOF must be returned unwrapped
+		}
+
+		switch key {
+		case keyEnter:
+			if len(state.text) > 0 && state.text[len(state.text)-1] == continuation {
+				state.text[len(state.text)-1] = '\n'
+				e.redraw(state)
+
+				continue
+			}
+
+			fmt.Fprint(e.writer, "\r\n")
+
+			return string(state.text), nil
+		case keyCtrlJ:
+			e.insert(state, '\n')
+		case keyCtrlC:
+			if len(state.text) == 0 {
+				return "", io.EOF
+			}
+
+			state.text = nil
+			e.redraw(state)
+		case keyCtrlD:
+			if len(state.text) == 0 {
+				return "", io.EOF
+			}
+		case keyCtrlU:
+			state.text = nil
+			e.redraw(state)
+		case keyBackspace, keyDelete:
+			e.backspace(state)
+		case keyTab:
+			e.completeText(state)
+		case keyEscape:
+			err = e.handleEscape(state)
+			if err != nil {
+				return "", err
+			}
+		default:
+			if key >= ' ' {
+				e.insert(state, key)
+			}
+		}
+	}
+}
+
+// handleEscape handles Alt-Enter, the arrow keys and pasted text, ignoring other escape sequences.
+func (e *LineEditor) handleEscape(state *editState) error {
+	key, _, err := e.reader.ReadRune()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	switch key {
+	case keyEnter:
+		e.insert(state, '\n')
+		return nil
+	case '[', 'O':
+	default:
+		return nil
+	}
+
+	sequence, err := e.readEscapeSequence()
+	if err != nil {
+		return err
+	}
+
+	switch sequence {
+	case "A":
+		e.browseHistory(state, -1)
+	case "B":
+		e.browseHistory(state, 1)
+	case pasteStart:
+		return e.readPaste(state)
+	}
+
+	return nil
+}
+
+// readEscapeSequence reads the parameters and the final character of a control sequence.
+func (e *LineEditor) readEscapeSequence() (string, error) {
+	var sequence strings.Builder
+
+	for {
+		key, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		sequence.WriteRune(key)
+
+		if key >= '@' && key <= '~' {
+			return sequence.String(), nil
+		}
+	}
+}
+
+// readPaste adds pasted text verbatim until the end of the paste, without size limits.
+func (e *LineEditor) readPaste(state *editState) error {
+	var previous rune
+
+	for {
+		key, _, err := e.reader.ReadRune()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if key == keyEscape {
+			next, _, err := e.reader.ReadRune()
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			sequence := ""
+			if next == '[' {
+				sequence, err = e.readEscapeSequence()
+				if err != nil {
+					return err
+				}
+			}
+
+			if sequence == pasteEnd {
+				e.redraw(state)
+				return nil
+			}
+
+			continue
+		}
+
+		switch {
+		case key == '\r':
+			state.text = append(state.text, '\n')
+		case key == '\n' && previous == '\r':
+		case key == '\n' || key == '\t' || key >= ' ':
+			state.text = append(state.text, key)
+		}
+
+		previous = key
+	}
+}
+
+func (e *LineEditor) browseHistory(state *editState, direction int) {
+	index := state.historyIndex + direction
+	if index < 0 || index > len(e.history) {
+		return
+	}
+
+	if state.historyIndex == len(e.history) {
+		state.draft = state.text
+	}
+
+	state.historyIndex = index
+
+	if index == len(e.history) {
+		state.text = state.draft
+	} else {
+		state.text = []rune(e.history[index])
+	}
+
+	e.redraw(state)
+}
+
+func (e *LineEditor) completeText(state *editState) {
+	if e.complete == nil {
+		return
+	}
+
+	text := string(state.text)
+
+	completions := e.complete(text)
+	if len(completions) == 0 {
+		return
+	}
+
+	if len(completions) == 1 {
+		state.text = []rune(completions[0] + " ")
+		e.redraw(state)
+
+		return
+	}
+
+	prefix := completions[0]
+	for _, completion := range completions[1:] {
+		prefix = commonPrefix(prefix, completion)
+	}
+
+	if prefix != text {
+		state.text = []rune(prefix)
+		e.redraw(state)
+
+		return
+	}
+
+	// nothing more to complete, list the candidates below the prompt
+	fmt.Fprint(e.writer, "\r\n"+strings.Join(completions, "  ")+"\r\n")
+
+	state.rowsAbove, state.lineWidth = 0, 0
+	e.redraw(state)
+}
+
+// insert adds a key at the end of the message, drawing only the new character.
+func (e *LineEditor) insert(state *editState, key rune) {
+	state.text = append(state.text, key)
+
+	if key == '\n' {
+		fmt.Fprint(e.writer, "\r\n")
+
+		state.rowsAbove += e.rowsOf(state.lineWidth)
+		state.lineWidth = 0
+
+		return
+	}
+
+	fmt.Fprint(e.writer, string(key))
+
+	state.lineWidth += runewidth.RuneWidth(key)
+}
+
+// backspace removes the last character, redrawing when the cursor moves to another row.
+func (e *LineEditor) backspace(state *editState) {
+	if len(state.text) == 0 {
+		return
+	}
+
+	last := state.text[len(state.text)-1]
+	width := runewidth.RuneWidth(last)
+
+	state.text = state.text[:len(state.text)-1]
+
+	// the cursor waits past the last column of a full row, where backspace is unreliable
+	if last == '\n' || state.lineWidth%e.width() == 0 || e.rowsOf(state.lineWidth) != e.rowsOf(state.lineWidth-width) {
+		e.redraw(state)
+		return
+	}
+
+	state.lineWidth -= width
+
+	erase := strings.Repeat("\b", width)
+	fmt.Fprint(e.writer, erase+strings.Repeat(" ", width)+erase)
+}
+
+// redraw clears the message from the screen and draws it again with the prompt.
+func (e *LineEditor) redraw(state *editState) {
+	rowsUp := state.rowsAbove + e.rowsOf(state.lineWidth) - 1
+	if rowsUp > 0 {
+		fmt.Fprint(e.writer, "\x1b["+strconv.Itoa(rowsUp)+"A")
+	}
+
+	lines := strings.Split(string(state.text), "\n")
+	lines[0] = e.prompt + lines[0]
+
+	fmt.Fprint(e.writer, clearToEnd+strings.Join(lines, "\r\n"))
+
+	state.rowsAbove = 0
+	for _, line := range lines[:len(lines)-1] {
+		state.rowsAbove += e.rowsOf(runewidth.StringWidth(line))
+	}
+
+	state.lineWidth = runewidth.StringWidth(lines[len(lines)-1])
+}
+
+// rowsOf returns the number of rows a line of the given width takes up on the screen.
+func (e *LineEditor) rowsOf(lineWidth int) int {
+	width := e.width()
+
+	if lineWidth <= 0 {
+		return 1
+	}
+
+	return (lineWidth + width - 1) / width
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return a[:i]
+}
@@ -0,0 +1,74 @@
+package ui_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/intility/cwc/pkg/ui"
+)
+
+func completeCommands(text string) []string {
+	var completions []string
+
+	for _, command := range []string{"/model", "/help", "/history"} {
+		if strings.HasPrefix(command, text) {
+			completions = append(completions, command)
+		}
+	}
+
+	return completions
+}
+
+func TestLineEditor_ReadLine(t *testing.T) {
+	longLine := strings.Repeat("x", 100_000)
+
+	tests := []struct {
+		name    string
+		keys    string
+		want    []string
+		wantErr error
+	}{
+		{name: "single line", keys: "hello\r", want: []string{"hello"}},
+		{name: "alt-enter starts a new line", keys: "one\x1b\rtwo\r", want: []string{"one\ntwo"}},
+		{name: "ctrl-j starts a new line", keys: "one\ntwo\r", want: []string{"one\ntwo"}},
+		{name: "backslash continues the line", keys: "one\\\rtwo\r", want: []string{"one\ntwo"}},
+		{name: "backspace", keys: "helx\x7flo\r", want: []string{"hello"}},
+		{name: "backspace joins lines", keys: "one\n\x7f two\r", want: []string{"one two"}},
+		{
+			name: "pasted lines arrive whole",
+			keys: "look: \x1b[200~panic: oops\r\n\tmain.go:12\r\n\x1b[201~\r",
+			want: []string{"look: panic: oops\n\tmain.go:12"},
+		},
+		{name: "large paste", keys: "\x1b[200~" + longLine + "\x1b[201~\r", want: []string{longLine}},
+		{name: "tab completes a unique command", keys: "/mo\t gpt-4o\r", want: []string{"/model  gpt-4o"}},
+		{name: "tab completes the common prefix", keys: "/h\tel\t\r", want: []string{"/help"}},
+		{name: "arrow up recalls the previous message", keys: "first\r\x1b[A\r", want: []string{"first", "first"}},
+		{name: "ctrl-c clears the line", keys: "oops\x03fine\r", want: []string{"fine"}},
+		{name: "ctrl-c at an empty prompt ends the input", keys: "\x03", wantErr: io.EOF},
+		{name: "ctrl-d at an empty prompt ends the input", keys: "\x04", wantErr: io.EOF},
+		{name: "end of input", keys: "", wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+
+			editor := ui.NewTerminalLineEditor(strings.NewReader(tt.keys), &output, "> ", completeCommands)
+
+			for _, want := range tt.want {
+				line, err := editor.ReadLine()
+				require.NoError(t, err)
+				assert.Equal(t, want, line)
+			}
+
+			if tt.wantErr != nil {
+				_, err := editor.ReadLine()
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
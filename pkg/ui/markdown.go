@@ -0,0 +1,382 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	styleReset   = "\033[0m"
+	styleBold    = "\033[1m"
+	styleDim     = "\033[2m"
+	styleItalic  = "\033[3m"
+	styleHeading = "\033[1;35m"
+	styleCode    = "\033[36m"
+
+	codeStyleName = "monokai"
+	codeGutter    = "│ "
+	minCodeWidth  = 20
+
+	// characters that may start a block element, which are held back at the
+	// start of a line until it is clear what the line is
+	blockStartChars = "#`~ \t>-*+_0123456789.)"
+)
+
+// MarkdownRenderer renders markdown as it is streamed, for display in a terminal.
+// Text is written as soon as its formatting is known. Markup split across chunks,
+// such as a heading or a fence arriving in pieces, is held back until it is complete.
+// Code blocks are highlighted by language and wrapped to the terminal width.
+type MarkdownRenderer struct {
+	writer  io.Writer
+	width   int
+	pending string
+
+	lineStart bool
+	heading   bool
+	quote     bool
+	bold      bool
+	italic    bool
+	code      bool
+	previous  rune
+
+	inFence     bool
+	fenceMarker string
+	lexer       chroma.Lexer
+}
+
+func NewMarkdownRenderer(writer io.Writer, width int) *MarkdownRenderer {
+	return &MarkdownRenderer{
+		writer:      writer,
+		width:       width,
+		pending:     "",
+		lineStart:   true,
+		heading:     false,
+		quote:       false,
+		bold:        false,
+		italic:      false,
+		code:        false,
+		previous:    ' ',
+		inFence:     false,
+		fenceMarker: "",
+		lexer:       nil,
+	}
+}
+
+// NewTerminalMarkdownRenderer creates a renderer for the file when it is a terminal.
+// It returns nil otherwise, so that answers piped or redirected elsewhere stay raw markdown.
+func NewTerminalMarkdownRenderer(file *os.File) *MarkdownRenderer {
+	if !IsTerminal(file) {
+		return nil
+	}
+
+	return NewMarkdownRenderer(file, TerminalWidth(file))
+}
+
+// Write renders the next chunk of the message.
+func (r *MarkdownRenderer) Write(chunk string) {
+	r.pending += chunk
+	r.render(false)
+}
+
+// Flush renders the text held back at the end of the message and resets the renderer for the next one.
+func (r *MarkdownRenderer) Flush() {
+	r.render(true)
+
+	if r.inFence {
+		r.write(styleDim + "╰─" + styleReset)
+	}
+
+	r.write(styleReset)
+
+	*r = *NewMarkdownRenderer(r.writer, r.width)
+}
+
+func (r *MarkdownRenderer) render(final bool) {
+	for r.pending != "" {
+		var ok bool
+
+		switch {
+		case r.inFence:
+			ok = r.renderCodeLine(final)
+		case r.lineStart:
+			ok = r.renderLineStart(final)
+		default:
+			ok = r.renderInline(final)
+		}
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// nextLine returns the next complete line, or the rest of the text at the end of the message.
+func (r *MarkdownRenderer) nextLine(final bool) (string, bool) {
+	line, rest, found := strings.Cut(r.pending, "\n")
+	if !found && !final {
+		return "", false
+	}
+
+	r.pending = rest
+
+	return line, true
+}
+
+func (r *MarkdownRenderer) renderCodeLine(final bool) bool {
+	line, ok := r.nextLine(final)
+	if !ok {
+		return false
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(line), r.fenceMarker) &&
+		strings.Trim(strings.TrimSpace(line), r.fenceMarker[:1]) == "" {
+		r.write(styleDim + "╰─" + styleReset + "\n")
+		r.inFence = false
+		r.lineStart = true
+
+		return true
+	}
+
+	for _, segment := range wrapCode(line, max(r.width-runewidth.StringWidth(codeGutter), minCodeWidth)) {
+		r.write(styleDim + codeGutter + styleReset + r.highlight(segment) + styleReset + "\n")
+	}
+
+	return true
+}
+
+// renderLineStart handles block elements: fences, headings, rules, list items and quotes.
+func (r *MarkdownRenderer) renderLineStart(final bool) bool { //nolint:cyclop
+	decided := strings.IndexFunc(r.pending, func(c rune) bool {
+		return !strings.ContainsRune(blockStartChars, c)
+	})
+	if decided < 0 && !final {
+		return false
+	}
+
+	trimmed := strings.TrimLeft(r.pending, " \t")
+	indent := r.pending[:len(r.pending)-len(trimmed)]
+
+	switch {
+	case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+		line, ok := r.nextLine(final)
+		if !ok {
+			return false
+		}
+
+		r.startFence(strings.TrimSpace(line))
+
+		return true
+	case isRule(r.pending):
+		// the line is complete, its characters would not have decided it otherwise
+		r.nextLine(true)
+		r.write(styleDim + strings.Repeat("─", r.width) + styleReset + "\n")
+
+		return true
+	case headingLevel(trimmed) > 0:
+		r.pending = strings.TrimLeft(trimmed[headingLevel(trimmed):], " ")
+		r.heading = true
+	case strings.HasPrefix(trimmed, "> ") || trimmed == ">":
+		r.pending = strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+		r.quote = true
+		r.write(styleDim + codeGutter)
+	case len(trimmed) > 1 && strings.ContainsRune("-*+", rune(trimmed[0])) && trimmed[1] == ' ':
+		r.pending = trimmed[2:]
+		r.write(indent + "• ")
+	}
+
+	r.lineStart = false
+	r.applyStyle()
+
+	return true
+}
+
+func (r *MarkdownRenderer) startFence(line string) {
+	r.fenceMarker = line[:3]
+	r.inFence = true
+	r.lineStart = false
+
+	language := strings.TrimSpace(strings.TrimLeft(line, r.fenceMarker[:1]))
+	if fields := strings.Fields(language); len(fields) > 0 {
+		language = fields[0]
+	}
+
+	r.lexer = lexers.Get(language)
+	if r.lexer == nil {
+		r.lexer = lexers.Fallback
+	}
+
+	r.lexer = chroma.Coalesce(r.lexer)
+
+	r.write(styleDim + "╭─ " + language + styleReset + "\n")
+}
+
+// renderInline renders emphasis and inline code up to the end of the line.
+func (r *MarkdownRenderer) renderInline(final bool) bool {
+	for r.pending != "" {
+		char := rune(r.pending[0])
+
+		switch {
+		case char == '\n':
+			r.pending = r.pending[1:]
+			r.endLine()
+
+			return true
+		case char == '`':
+			r.pending = r.pending[1:]
+			r.code = !r.code
+			r.applyStyle()
+		case char == '*' && !r.code:
+			if len(r.pending) == 1 && !final {
+				return false
+			}
+
+			r.renderEmphasis()
+		default:
+			r.renderText()
+		}
+	}
+
+	return true
+}
+
+// renderText writes the text up to the next character that may change the style.
+func (r *MarkdownRenderer) renderText() {
+	stops := "\n`*"
+	if r.code {
+		stops = "\n`"
+	}
+
+	text := r.pending
+	if end := strings.IndexAny(text[1:], stops); end >= 0 {
+		text = text[:end+1]
+	}
+
+	r.pending = r.pending[len(text):]
+	r.previous, _ = utf8.DecodeLastRuneInString(text)
+	r.write(text)
+}
+
+// renderEmphasis toggles bold for ** and italic for a single * next to a word,
+// other asterisks, such as in 2 * 3, are written as they are.
+func (r *MarkdownRenderer) renderEmphasis() {
+	if strings.HasPrefix(r.pending, "**") {
+		r.pending = r.pending[2:]
+		r.bold = !r.bold
+		r.applyStyle()
+
+		return
+	}
+
+	r.pending = r.pending[1:]
+
+	next, _ := utf8.DecodeRuneInString(r.pending)
+
+	switch {
+	case !r.italic && r.pending != "" && !unicode.IsSpace(next):
+		r.italic = true
+		r.applyStyle()
+	case r.italic && !unicode.IsSpace(r.previous):
+		r.italic = false
+		r.applyStyle()
+	default:
+		r.write("*")
+		r.previous = '*'
+	}
+}
+
+func (r *MarkdownRenderer) endLine() {
+	r.heading, r.quote, r.bold, r.italic, r.code = false, false, false, false, false
+	r.previous = ' '
+	r.lineStart = true
+
+	r.write(styleReset + "\n")
+}
+
+func (r *MarkdownRenderer) applyStyle() {
+	style := styleReset
+
+	if r.quote {
+		style += styleDim
+	}
+
+	if r.heading {
+		style += styleHeading
+	}
+
+	if r.bold {
+		style += styleBold
+	}
+
+	if r.italic {
+		style += styleItalic
+	}
+
+	if r.code {
+		style += styleCode
+	}
+
+	r.write(style)
+}
+
+func (r *MarkdownRenderer) highlight(code string) string {
+	iterator, err := r.lexer.Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+
+	var highlighted bytes.Buffer
+
+	err = formatters.TTY256.Format(&highlighted, styles.Get(codeStyleName), iterator)
+	if err != nil {
+		return code
+	}
+
+	return strings.TrimRight(highlighted.String(), "\n")
+}
+
+func (r *MarkdownRenderer) write(text string) {
+	_, _ = io.WriteString(r.writer, text)
+}
+
+// headingLevel returns the level of an ATX heading such as "## Usage", or zero.
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || len(line) == level || line[level] != ' ' {
+		return 0
+	}
+
+	return level
+}
+
+// isRule reports whether the text starts with a thematic break such as "---".
+func isRule(text string) bool {
+	line, _, _ := strings.Cut(text, "\n")
+	line = strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+
+	return len(line) >= 3 && strings.Trim(line, line[:1]) == "" && strings.ContainsAny(line[:1], "-*_")
+}
+
+// wrapCode splits a line of code into segments fitting the width, expanding tabs.
+func wrapCode(line string, width int) []string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	var segments []string
+
+	for runewidth.StringWidth(line) > width {
+		cut := runewidth.Truncate(line, width, "")
+		segments = append(segments, cut)
+		line = line[len(cut):]
+	}
+
+	return append(segments, line)
+}
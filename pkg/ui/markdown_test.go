@@ -0,0 +1,94 @@
+package ui_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/intility/cwc/pkg/ui"
+)
+
+var escapeSequence = regexp.MustCompile("\033\\[[0-9;]*m")
+
+func renderMarkdown(width int, chunks ...string) string {
+	var output strings.Builder
+
+	renderer := ui.NewMarkdownRenderer(&output, width)
+	for _, chunk := range chunks {
+		renderer.Write(chunk)
+	}
+
+	renderer.Flush()
+
+	return output.String()
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		width    int
+		want     string
+	}{
+		{name: "plain text", markdown: "hello world", want: "hello world"},
+		{name: "heading", markdown: "## Usage\ntext\n", want: "Usage\ntext\n"},
+		{name: "hash without space is text", markdown: "#hashtag", want: "#hashtag"},
+		{name: "list items", markdown: "- one\n  * two\n", want: "• one\n  • two\n"},
+		{name: "quote", markdown: "> quoted\n", want: "│ quoted\n"},
+		{name: "rule", markdown: "a\n---\nb", width: 5, want: "a\n─────\nb"},
+		{name: "emphasis", markdown: "**bold** *italic* `code`", want: "bold italic code"},
+		{name: "lone asterisks", markdown: "2 * 3 = 6, a*", want: "2 * 3 = 6, a*"},
+		{name: "asterisks in inline code", markdown: "`a * b`", want: "a * b"},
+		{
+			name:     "code block",
+			markdown: "```go\nfmt.Println(\"*hi*\")\n```\nafter",
+			want:     "╭─ go\n│ fmt.Println(\"*hi*\")\n╰─\nafter",
+		},
+		{name: "unclosed code block", markdown: "~~~\nx", want: "╭─ \n│ x\n╰─"},
+		{
+			name:     "long code lines wrap",
+			markdown: "```\n" + strings.Repeat("x", 30) + "\n```\n",
+			width:    22,
+			want:     "╭─ \n│ " + strings.Repeat("x", 20) + "\n│ " + strings.Repeat("x", 10) + "\n╰─\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width := tt.width
+			if width == 0 {
+				width = 80
+			}
+
+			got := renderMarkdown(width, tt.markdown)
+
+			assert.Equal(t, tt.want, escapeSequence.ReplaceAllString(got, ""))
+		})
+	}
+}
+
+func TestMarkdownRenderer_Styles(t *testing.T) {
+	got := renderMarkdown(80, "# Title\n**bold** and `code`\n```go\nfunc main() {}\n```\n")
+
+	assert.Contains(t, got, "\033[0m\033[1;35mTitle")
+	assert.Contains(t, got, "\033[0m\033[1mbold\033[0m and ")
+	assert.Contains(t, got, "\033[0m\033[36mcode\033[0m")
+	// the keyword is highlighted
+	assert.Regexp(t, "\033\\[[0-9;]+mfunc\033\\[0m", got)
+}
+
+func TestMarkdownRenderer_SplitChunks(t *testing.T) {
+	markdown := "# Heading\n\nSome **bold** text, *italic* and `code * x`.\n\n" +
+		"- item\n> quote\n***\n```python\ndef f():\n    return 2 * 3\n```\nend *"
+	want := renderMarkdown(80, markdown)
+
+	// however the stream is split, the rendered output is the same
+	for i := range len(markdown) {
+		assert.Equal(t, want, renderMarkdown(80, markdown[:i], markdown[i:]), "split at %d", i)
+	}
+
+	chunks := strings.SplitAfter(markdown, "")
+	assert.Equal(t, want, renderMarkdown(80, chunks...), "split into characters")
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether the file is a terminal, such as stdout when it is not redirected.
+func IsTerminal(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
+// TerminalWidth returns the width of the terminal, or a default when the file is not a terminal.
+func TerminalWidth(file *os.File) int {
+	width, _, err := term.GetSize(int(file.Fd()))
+	if err != nil || width <= 0 {
+		return defaultTerminalWidth
+	}
+
+	return width
+}
+
+// OpenTerminal opens the controlling terminal for reading, to ask the user
+// questions when stdin is piped from another command.
+func OpenTerminal() (*os.File, error) {
+	name := "/dev/tty"
+	if runtime.GOOS == "windows" {
+		name = "CONIN$"
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening terminal: %w", err)
+	}
+
+	return file, nil
+}
@@ -75,26 +75,44 @@ func (u UI) AskYesNo(prompt string, defaultYes bool) bool {
 
 	yesStrings := []string{"Y", "YES", "YEAH", "YEP", "YEA", "YEAH", "YUP"}
 
-	scanner := bufio.NewScanner(u.Reader)
-	for scanner.Scan() {
-		answer := strings.ToUpper(scanner.Text())
-		if answer == "" {
-			return defaultYes
+	line, err := readLine(u.Reader)
+	if err != nil && line == "" {
+		return false
+	}
+
+	answer := strings.ToUpper(strings.TrimSpace(line))
+	if answer == "" {
+		return defaultYes
+	}
+
+	return slices.Contains(yesStrings, answer)
+}
+
+// readLine reads a single line without reading ahead, so that consecutive
+// questions each get their own answer when the reader is a pipe.
+func readLine(reader io.Reader) (string, error) {
+	var (
+		line []byte
+		char [1]byte
+	)
+
+	for {
+		n, err := reader.Read(char[:])
+		if n == 1 && char[0] == '\n' {
+			return string(line), nil
 		}
 
-		if slices.Contains(yesStrings, answer) {
-			return true
+		line = append(line, char[:n]...)
+
+		if err != nil {
+			return string(line), err //nolint:wrapcheck
 		}
 	}
-
-	return false
 }
 
-// ReadUserInput reads a line of input from the user.
+// ReadUserInput reads a line of input from the user, of any length.
 func (u UI) ReadUserInput() string {
-	scanner := bufio.NewScanner(u.Reader)
-	scanner.Scan()
-	userInput := scanner.Text()
+	userInput, _ := bufio.NewReader(u.Reader).ReadString('\n')
 
 	return strings.TrimSpace(userInput)
 }
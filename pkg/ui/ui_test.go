@@ -109,6 +109,20 @@ func TestAskYesNo(t *testing.T) {
 	}
 }
 
+func TestAskYesNo_Consecutive(t *testing.T) {
+	reader := bytes.NewBufferString("y\nn\n\nyes\n")
+	ui := ui.NewUI(ui.WithReader(reader), ui.WithWriter(&bytes.Buffer{}))
+
+	var got []bool
+	for range 4 {
+		got = append(got, ui.AskYesNo("Apply this hunk?", false))
+	}
+
+	if diff := cmp.Diff([]bool{true, false, false, true}, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestReadUserInput(t *testing.T) {
 	testCases := []struct {
 		name      string